@@ -0,0 +1,72 @@
+package smallset
+
+import "net/netip"
+
+// AddrSet is a [Custom] set of IP addresses, ordered by [netip.Addr.Compare], with operations
+// on CIDR prefixes. IPv4 addresses are sorted before IPv6 ones, so the addresses of a prefix
+// are always contiguous in the set. It's meant for small allow or deny lists.
+// Not safe for concurrent use.
+type AddrSet struct {
+	*Custom[netip.Addr]
+}
+
+// NewAddrSet returns an initialized set of IP addresses with the provided capacity.
+// It panics if the capacity is <= 0.
+func NewAddrSet(capacity int) *AddrSet {
+	return &AddrSet{NewCustom(netip.Addr.Compare, capacity)}
+}
+
+// AddrSetFrom returns an initialized set of IP addresses that contains the provided addresses.
+func AddrSetFrom(addrs ...netip.Addr) *AddrSet {
+	return &AddrSet{CustomFrom(netip.Addr.Compare, addrs...)}
+}
+
+// RemoveCIDR removes all the addresses contained in the prefix. Returns num removed.
+// An invalid prefix contains no addresses.
+func (s *AddrSet) RemoveCIDR(prefix netip.Prefix) int {
+	start, end := s.cidrRange(prefix)
+	if start == end {
+		return 0
+	}
+
+	s.delete(start, end)
+	return end - start
+}
+
+// ContainsCIDRAny returns whether at least one address of the set is contained in the prefix.
+// An invalid prefix contains no addresses. Operation is O(log(N)).
+func (s *AddrSet) ContainsCIDRAny(prefix netip.Prefix) bool {
+	start, end := s.cidrRange(prefix)
+	return start < end
+}
+
+// cidrRange returns the indices [start, end) of the addresses contained in the prefix.
+func (s *AddrSet) cidrRange(prefix netip.Prefix) (start, end int) {
+	if !prefix.IsValid() {
+		return 0, 0
+	}
+
+	prefix = prefix.Masked()
+	start, _ = s.search(prefix.Addr())
+	end, found := s.search(lastAddr(prefix))
+	if found {
+		end++
+	}
+	return start, end
+}
+
+// lastAddr returns the last address of the masked prefix, which has all the host bits set.
+func lastAddr(prefix netip.Prefix) netip.Addr {
+	addr := prefix.Addr()
+	bytes := addr.As16()
+	offset := 128 - addr.BitLen() // IPv4 addresses are stored in the last 4 bytes
+
+	for bit := offset + prefix.Bits(); bit < 128; bit++ {
+		bytes[bit/8] |= 1 << (7 - bit%8)
+	}
+
+	if addr.Is4() {
+		return netip.AddrFrom4([4]byte(bytes[12:]))
+	}
+	return netip.AddrFrom16(bytes)
+}
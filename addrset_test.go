@@ -0,0 +1,83 @@
+package smallset
+
+import (
+	"fmt"
+	"net/netip"
+	"slices"
+	"testing"
+)
+
+func addrs(ss ...string) []netip.Addr {
+	addrs := make([]netip.Addr, len(ss))
+	for i, s := range ss {
+		addrs[i] = netip.MustParseAddr(s)
+	}
+	return addrs
+}
+
+func TestAddrSetRemoveCIDR(t *testing.T) {
+	initial := addrs("10.0.0.1", "10.0.0.255", "10.0.1.0", "192.168.1.1", "::1", "2001:db8::1", "2001:db8::ffff")
+
+	cases := []struct {
+		prefix   string
+		expected int
+		items    []netip.Addr
+	}{
+		{
+			prefix:   "10.0.0.0/24",
+			expected: 2,
+			items:    addrs("10.0.1.0", "192.168.1.1", "::1", "2001:db8::1", "2001:db8::ffff"),
+		},
+		{
+			prefix:   "10.0.0.0/8",
+			expected: 3,
+			items:    addrs("192.168.1.1", "::1", "2001:db8::1", "2001:db8::ffff"),
+		},
+		{
+			prefix:   "0.0.0.0/0",
+			expected: 4,
+			items:    addrs("::1", "2001:db8::1", "2001:db8::ffff"),
+		},
+		{
+			prefix:   "2001:db8::/112",
+			expected: 2,
+			items:    addrs("10.0.0.1", "10.0.0.255", "10.0.1.0", "192.168.1.1", "::1"),
+		},
+		{
+			prefix:   "192.168.1.1/32",
+			expected: 1,
+			items:    addrs("10.0.0.1", "10.0.0.255", "10.0.1.0", "::1", "2001:db8::1", "2001:db8::ffff"),
+		},
+		{
+			prefix:   "172.16.0.0/12",
+			expected: 0,
+			items:    initial,
+		},
+	}
+
+	for i, test := range cases {
+		t.Run(fmt.Sprintf("Case_%d", i), func(t *testing.T) {
+			s := AddrSetFrom(initial...)
+			prefix := netip.MustParsePrefix(test.prefix)
+
+			if any := s.ContainsCIDRAny(prefix); any != (test.expected > 0) {
+				t.Errorf("ContainsCIDRAny(%s) expected %t, got %t", prefix, test.expected > 0, any)
+			}
+
+			if res := s.RemoveCIDR(prefix); res != test.expected {
+				t.Errorf("RemoveCIDR(%s) expected %d, got %d", prefix, test.expected, res)
+			}
+
+			if !slices.Equal(s.items, test.items) {
+				t.Errorf("Items mismatch.\nExpected: %v\nActual: %v", test.items, s.items)
+			}
+		})
+	}
+}
+
+func TestAddrSetInvalidPrefix(t *testing.T) {
+	s := AddrSetFrom(addrs("10.0.0.1", "::1")...)
+	if s.ContainsCIDRAny(netip.Prefix{}) || s.RemoveCIDR(netip.Prefix{}) != 0 {
+		t.Errorf("expected the invalid prefix to contain no addresses")
+	}
+}
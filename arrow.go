@@ -0,0 +1,50 @@
+package smallset
+
+import (
+	"cmp"
+	"fmt"
+)
+
+// ArrowBuilder is the subset of the Apache Arrow array builders used by [ToArrow].
+// It's implemented by the numeric builders, like *array.Int64Builder, and by *array.StringBuilder,
+// so that this package doesn't depend on the Arrow module.
+type ArrowBuilder[T any] interface {
+	AppendValues(values []T, valid []bool)
+}
+
+// ArrowArray is the subset of the Apache Arrow arrays used by [FromArrow].
+// It's implemented by the numeric arrays, like *array.Int64, and by *array.String.
+type ArrowArray[T any] interface {
+	Len() int
+	IsNull(i int) bool
+	Value(i int) T
+}
+
+// ToArrow appends the elements of the set to the builder in ascending order, all valid,
+// in a single call that passes the internal slice without copying it.
+// The builder must not retain the slice after the call, which Arrow builders don't.
+func ToArrow[T cmp.Ordered](s *Ordered[T], b ArrowBuilder[T]) {
+	if s.IsEmpty() {
+		return
+	}
+
+	defer s.pin()()
+	b.AppendValues(s.items, nil)
+}
+
+// FromArrow returns a set that contains the values of the array.
+// It returns an error if the array contains nulls, which have no place in a set.
+func FromArrow[T cmp.Ordered](a ArrowArray[T]) (*Ordered[T], error) {
+	if a.Len() == 0 {
+		return New[T](defaultCapacity), nil
+	}
+
+	items := make([]T, a.Len())
+	for i := range items {
+		if a.IsNull(i) {
+			return nil, fmt.Errorf("smallset.FromArrow: null value at index %d", i)
+		}
+		items[i] = a.Value(i)
+	}
+	return Adopt(items), nil
+}
@@ -0,0 +1,82 @@
+package smallset
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+// arrowColumn mimics an Arrow builder and array, with nulls marked by valid.
+type arrowColumn[T any] struct {
+	values []T
+	valid  []bool
+}
+
+func (c *arrowColumn[T]) AppendValues(values []T, valid []bool) {
+	for i, v := range values {
+		c.values = append(c.values, v)
+		c.valid = append(c.valid, valid == nil || valid[i])
+	}
+}
+
+func (c *arrowColumn[T]) Len() int          { return len(c.values) }
+func (c *arrowColumn[T]) IsNull(i int) bool { return !c.valid[i] }
+func (c *arrowColumn[T]) Value(i int) T     { return c.values[i] }
+
+func TestToArrow(t *testing.T) {
+	cases := []struct {
+		set      *Ordered[int64]
+		expected []int64
+	}{
+		{set: New[int64](10), expected: nil},
+		{set: From[int64](3, 1, 2), expected: []int64{1, 2, 3}},
+	}
+
+	for i, test := range cases {
+		t.Run(fmt.Sprintf("Case_%d", i), func(t *testing.T) {
+			col := &arrowColumn[int64]{}
+			ToArrow(test.set, col)
+
+			if !slices.Equal(col.values, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, col.values)
+			}
+			if slices.Contains(col.valid, false) {
+				t.Errorf("expected all values to be valid, got %v", col.valid)
+			}
+		})
+	}
+}
+
+func TestFromArrow(t *testing.T) {
+	cases := []struct {
+		col      *arrowColumn[string]
+		expected []string
+		err      bool
+	}{
+		{col: &arrowColumn[string]{}, expected: []string{}},
+		{
+			col:      &arrowColumn[string]{values: []string{"b", "a", "b"}, valid: []bool{true, true, true}},
+			expected: []string{"a", "b"},
+		},
+		{
+			col: &arrowColumn[string]{values: []string{"b", ""}, valid: []bool{true, false}},
+			err: true,
+		},
+	}
+
+	for i, test := range cases {
+		t.Run(fmt.Sprintf("Case_%d", i), func(t *testing.T) {
+			set, err := FromArrow(test.col)
+			if (err != nil) != test.err {
+				t.Fatalf("expected error %v, got %v", test.err, err)
+			}
+			if err != nil {
+				return
+			}
+
+			if !slices.Equal(set.items, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, set.items)
+			}
+		})
+	}
+}
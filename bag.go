@@ -0,0 +1,135 @@
+package smallset
+
+import (
+	"iter"
+	"slices"
+	"sort"
+)
+
+// SortedBag is a sorted collection that, unlike [Custom], keeps multiple elements that compare
+// equal, in their insertion order. It's meant for sequences with legitimate collisions,
+// like events ordered by timestamp. Not safe for concurrent use.
+type SortedBag[T any] struct {
+	items []T
+	cmp   compareFunc[T]
+}
+
+// NewSortedBag returns an initialized bag with the provided compare function and capacity.
+// It panics if cmp is nil or the capacity is <= 0.
+func NewSortedBag[T any](cmp func(a, b T) int, capacity int) *SortedBag[T] {
+	if cmp == nil {
+		panic("smallset.NewSortedBag: cmp cannot be nil")
+	}
+	if capacity <= 0 {
+		panic("smallset.NewSortedBag: capacity must be > 0")
+	}
+
+	return &SortedBag[T]{
+		items: make([]T, 0, capacity),
+		cmp:   cmp,
+	}
+}
+
+// Size returns the number of elements in the bag.
+func (b *SortedBag[T]) Size() int {
+	return len(b.items)
+}
+
+// IsEmpty returns whether the bag has no elements.
+func (b *SortedBag[T]) IsEmpty() bool {
+	return len(b.items) == 0
+}
+
+// Clear removes all elements from the bag, retaining the allocated capacity.
+func (b *SortedBag[T]) Clear() {
+	clear(b.items)
+	b.items = b.items[:0]
+}
+
+// Items returns the elements of the bag in ascending order, with equal elements in insertion order.
+func (b *SortedBag[T]) Items() []T {
+	return slices.Clone(b.items)
+}
+
+// equalRange returns the indices [start, end) of the elements equal to e.
+func (b *SortedBag[T]) equalRange(e T) (start, end int) {
+	start, _ = slices.BinarySearchFunc(b.items, e, b.cmp)
+	end = start + sort.Search(len(b.items)-start, func(i int) bool { return b.cmp(b.items[start+i], e) > 0 })
+	return start, end
+}
+
+// Add an element after the ones equal to it. O(N) complexity.
+func (b *SortedBag[T]) Add(e T) {
+	_, i := b.equalRange(e)
+	b.items = slices.Insert(b.items, i, e)
+}
+
+// Contains returns whether at least one element equal to e is in the bag.
+func (b *SortedBag[T]) Contains(e T) bool {
+	_, found := slices.BinarySearchFunc(b.items, e, b.cmp)
+	return found
+}
+
+// Count returns the number of elements equal to e. O(log(N)) complexity.
+func (b *SortedBag[T]) Count(e T) int {
+	start, end := b.equalRange(e)
+	return end - start
+}
+
+// EqualRange iterates the elements equal to e in insertion order, together with their index.
+func (b *SortedBag[T]) EqualRange(e T) iter.Seq2[int, T] {
+	start, end := b.equalRange(e)
+	return func(yield func(int, T) bool) {
+		for i := start; i < end; i++ {
+			if !yield(i, b.items[i]) {
+				return
+			}
+		}
+	}
+}
+
+// Remove the first inserted element equal to e, and returns whether it was removed (true),
+// or no such element was present (false). O(N) complexity.
+func (b *SortedBag[T]) Remove(e T) bool {
+	i, found := slices.BinarySearchFunc(b.items, e, b.cmp)
+	if !found {
+		return false
+	}
+
+	b.items = slices.Delete(b.items, i, i+1)
+	return true
+}
+
+// RemoveAll removes all elements equal to e. Returns num removed.
+func (b *SortedBag[T]) RemoveAll(e T) int {
+	start, end := b.equalRange(e)
+	b.items = slices.Delete(b.items, start, end)
+	return end - start
+}
+
+// Min returns the first inserted smallest element in the bag, or panics if the bag is empty.
+func (b *SortedBag[T]) Min() T {
+	if len(b.items) == 0 {
+		panic("smallset.SortedBag.Min: bag is empty")
+	}
+	return b.items[0]
+}
+
+// Max returns the last inserted biggest element in the bag, or panics if the bag is empty.
+func (b *SortedBag[T]) Max() T {
+	if len(b.items) == 0 {
+		panic("smallset.SortedBag.Max: bag is empty")
+	}
+	return b.items[len(b.items)-1]
+}
+
+// Ascend returns an iterator over the bag in ascending order, with equal elements in insertion order.
+func (b *SortedBag[T]) Ascend() iter.Seq2[int, T] {
+	return slices.All(b.items)
+}
+
+// Descend returns an iterator over the bag in descending order, with equal elements
+// in reverse insertion order.
+func (b *SortedBag[T]) Descend() iter.Seq2[int, T] {
+	return slices.Backward(b.items)
+}
@@ -0,0 +1,96 @@
+package smallset
+
+import (
+	"fmt"
+	"iter"
+	"slices"
+	"testing"
+)
+
+// event is ordered by its time only, so events at the same time compare equal.
+type event struct {
+	time int
+	name string
+}
+
+func eventCmp(a, b event) int { return a.time - b.time }
+
+func eventNames(seq iter.Seq2[int, event]) []string {
+	var names []string
+	for _, e := range seq {
+		names = append(names, e.name)
+	}
+	return names
+}
+
+func events() *SortedBag[event] {
+	b := NewSortedBag(eventCmp, 10)
+	b.Add(event{2, "b1"})
+	b.Add(event{1, "a"})
+	b.Add(event{2, "b2"})
+	b.Add(event{3, "c"})
+	b.Add(event{2, "b3"})
+	return b
+}
+
+func TestSortedBagAdd(t *testing.T) {
+	b := events()
+	expected := []string{"a", "b1", "b2", "b3", "c"}
+	if got := eventNames(b.Ascend()); !slices.Equal(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+	if b.Min().name != "a" || b.Max().name != "c" {
+		t.Errorf("unexpected min %v or max %v", b.Min(), b.Max())
+	}
+}
+
+func TestSortedBagEqualRange(t *testing.T) {
+	cases := []struct {
+		time     int
+		expected []string
+	}{
+		{time: 0, expected: nil},
+		{time: 1, expected: []string{"a"}},
+		{time: 2, expected: []string{"b1", "b2", "b3"}},
+		{time: 4, expected: nil},
+	}
+
+	b := events()
+	for i, test := range cases {
+		t.Run(fmt.Sprintf("Case_%d", i), func(t *testing.T) {
+			got := eventNames(b.EqualRange(event{time: test.time}))
+			if !slices.Equal(got, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, got)
+			}
+			if b.Count(event{time: test.time}) != len(test.expected) {
+				t.Errorf("expected count %d, got %d", len(test.expected), b.Count(event{time: test.time}))
+			}
+			if b.Contains(event{time: test.time}) != (len(test.expected) > 0) {
+				t.Errorf("unexpected Contains for time %d", test.time)
+			}
+		})
+	}
+}
+
+func TestSortedBagRemove(t *testing.T) {
+	b := events()
+	if !b.Remove(event{time: 2}) {
+		t.Fatal("expected an event to be removed")
+	}
+	expected := []string{"a", "b2", "b3", "c"}
+	if got := eventNames(b.Ascend()); !slices.Equal(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+
+	if removed := b.RemoveAll(event{time: 2}); removed != 2 {
+		t.Errorf("expected 2 removed, got %d", removed)
+	}
+	if b.Remove(event{time: 2}) {
+		t.Error("expected no event to be removed")
+	}
+
+	expected = []string{"c", "a"}
+	if got := eventNames(b.Descend()); !slices.Equal(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
@@ -0,0 +1,67 @@
+package smallset
+
+import (
+	"cmp"
+	"hash/maphash"
+	"slices"
+)
+
+// bloomHashes is the number of bit positions set for each element.
+const bloomHashes = 3
+
+// bloom is a Bloom filter used to quickly rule out elements that are not in a set.
+// Elements can't be removed from the filter, so removals only increase the false positive rate,
+// until the filter is reset.
+type bloom[T cmp.Ordered] struct {
+	bits []uint64
+	seed maphash.Seed
+}
+
+func newBloom[T cmp.Ordered](bits int) *bloom[T] {
+	return &bloom[T]{
+		bits: make([]uint64, (bits+63)/64),
+		seed: maphash.MakeSeed(),
+	}
+}
+
+// positions returns the two hashes used to derive the bit positions of the element,
+// following the double hashing scheme of Kirsch and Mitzenmacher.
+func (b *bloom[T]) positions(e T) (h1, h2 uint64) {
+	h := hashComparable(b.seed, e)
+	return h & 0xffffffff, h>>32 | 1
+}
+
+func (b *bloom[T]) add(e T) {
+	size := uint64(len(b.bits) * 64)
+	h1, h2 := b.positions(e)
+	for i := range uint64(bloomHashes) {
+		pos := (h1 + i*h2) % size
+		b.bits[pos/64] |= 1 << (pos % 64)
+	}
+}
+
+func (b *bloom[T]) mayContain(e T) bool {
+	size := uint64(len(b.bits) * 64)
+	h1, h2 := b.positions(e)
+	for i := range uint64(bloomHashes) {
+		pos := (h1 + i*h2) % size
+		if b.bits[pos/64]&(1<<(pos%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (b *bloom[T]) reset() {
+	clear(b.bits)
+}
+
+func (b *bloom[T]) clone() *bloom[T] {
+	if b == nil {
+		return nil
+	}
+	return &bloom[T]{
+		bits: slices.Clone(b.bits),
+		seed: b.seed,
+	}
+}
@@ -0,0 +1,220 @@
+package smallset
+
+import (
+	"cmp"
+	"iter"
+	"slices"
+)
+
+// Buffered is a sorted set whose insertions accumulate in a small sorted staging area,
+// which is merged into the main slice when it fills up. This turns the O(N) shift of every
+// insertion into an O(B) shift within the staging area, plus a single O(N) merge every B
+// insertions, where B is the buffer size, which pays off for insert-heavy workloads on big sets.
+// Contains and Remove consult both areas, while the methods that depend on the order of
+// the elements, like Min or Ascend, merge the staging area first.
+// It implements [SetOf]. Not safe for concurrent use.
+type Buffered[T any] struct {
+	items  []T // sorted elements
+	staged []T // sorted elements not in items, at most cap(staged)
+	cmp    compareFunc[T]
+}
+
+// NewBuffered returns an initialized buffered set for ordered types, whose staging area
+// holds at most bufferSize elements. A good buffer size is in the hundreds.
+// It panics if bufferSize is <= 0.
+func NewBuffered[T cmp.Ordered](bufferSize int) *Buffered[T] {
+	if bufferSize <= 0 {
+		panic("smallset.NewBuffered: buffer size must be > 0")
+	}
+	return &Buffered[T]{staged: make([]T, 0, bufferSize), cmp: cmp.Compare[T]}
+}
+
+// NewBufferedCustom returns an initialized buffered set with the provided compare function,
+// whose staging area holds at most bufferSize elements. It panics if cmp is nil or bufferSize is <= 0.
+func NewBufferedCustom[T any](cmp func(a, b T) int, bufferSize int) *Buffered[T] {
+	if cmp == nil {
+		panic("smallset.NewBufferedCustom: cmp cannot be nil")
+	}
+	if bufferSize <= 0 {
+		panic("smallset.NewBufferedCustom: buffer size must be > 0")
+	}
+	return &Buffered[T]{staged: make([]T, 0, bufferSize), cmp: cmp}
+}
+
+// Size returns the number of elements in the set.
+func (s *Buffered[T]) Size() int {
+	return len(s.items) + len(s.staged)
+}
+
+// IsEmpty returns whether the set has no elements.
+func (s *Buffered[T]) IsEmpty() bool {
+	return s.Size() == 0
+}
+
+// Clear removes all elements from the set, preserving its capacity.
+func (s *Buffered[T]) Clear() {
+	clear(s.items)
+	clear(s.staged)
+	s.items = s.items[:0]
+	s.staged = s.staged[:0]
+}
+
+// Flush merges the staging area into the main slice. O(N + B*log(N)) complexity.
+// It's called automatically when the staging area is full, and by the methods that depend
+// on the order of the elements.
+func (s *Buffered[T]) Flush() {
+	if len(s.staged) == 0 {
+		return
+	}
+
+	// merge backwards, moving the blocks of items between staged elements with a single copy,
+	// so that each element of items is moved at most once
+	n := len(s.items)
+	s.items = slices.Grow(s.items, len(s.staged))[:n+len(s.staged)]
+
+	end := n
+	for j := len(s.staged) - 1; j >= 0; j-- {
+		i, _ := slices.BinarySearchFunc(s.items[:end], s.staged[j], s.cmp)
+		copy(s.items[i+j+1:], s.items[i:end])
+		s.items[i+j] = s.staged[j]
+		end = i
+	}
+
+	clear(s.staged)
+	s.staged = s.staged[:0]
+}
+
+// Items returns the elements of the set in ascending order.
+func (s *Buffered[T]) Items() []T {
+	s.Flush()
+	return slices.Clone(s.items)
+}
+
+// Ascend returns an iterator over the set in ascending order.
+func (s *Buffered[T]) Ascend() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		s.Flush()
+		for i, e := range s.items {
+			if !yield(i, e) {
+				return
+			}
+		}
+	}
+}
+
+// Contains returns whether the element is in the set. Operation is O(log(N)).
+func (s *Buffered[T]) Contains(e T) bool {
+	if _, found := slices.BinarySearchFunc(s.items, e, s.cmp); found {
+		return true
+	}
+	_, found := slices.BinarySearchFunc(s.staged, e, s.cmp)
+	return found
+}
+
+// Add an element and returns whether is was added (true), or was already present (false).
+// Operation is O(log(N) + B), plus the merge of the staging area every B insertions.
+func (s *Buffered[T]) Add(e T) bool {
+	if _, found := slices.BinarySearchFunc(s.items, e, s.cmp); found {
+		return false
+	}
+
+	i, found := slices.BinarySearchFunc(s.staged, e, s.cmp)
+	if found {
+		return false
+	}
+
+	s.staged = slices.Insert(s.staged, i, e)
+	if len(s.staged) == cap(s.staged) {
+		s.Flush()
+	}
+	return true
+}
+
+// Remove an element if present, and returns whether is was removed (true), or was never present (false).
+// Operation is O(log(N) + B) if the element is staged, O(N) otherwise.
+func (s *Buffered[T]) Remove(e T) bool {
+	if i, found := slices.BinarySearchFunc(s.staged, e, s.cmp); found {
+		s.staged = slices.Delete(s.staged, i, i+1)
+		return true
+	}
+
+	i, found := slices.BinarySearchFunc(s.items, e, s.cmp)
+	if !found {
+		return false
+	}
+
+	s.items = slices.Delete(s.items, i, i+1)
+	return true
+}
+
+// RemoveBefore removes all elements e such that e < max. Returns num removed.
+func (s *Buffered[T]) RemoveBefore(max T) int {
+	s.Flush()
+	end, _ := slices.BinarySearchFunc(s.items, max, s.cmp)
+	s.items = slices.Delete(s.items, 0, end)
+	return end
+}
+
+// RemoveFrom removes all elements e such that e >= min. Returns num removed.
+func (s *Buffered[T]) RemoveFrom(min T) int {
+	s.Flush()
+	start, _ := slices.BinarySearchFunc(s.items, min, s.cmp)
+	removed := len(s.items) - start
+	s.items = slices.Delete(s.items, start, len(s.items))
+	return removed
+}
+
+// RemoveBetween removes all elements e such that min <= e < max. Returns num removed.
+// Panics if max < min.
+func (s *Buffered[T]) RemoveBetween(min, max T) int {
+	if s.cmp.less(max, min) {
+		panic("smallset.Buffered.RemoveBetween: invalid range (max < min)")
+	}
+
+	s.Flush()
+	start, _ := slices.BinarySearchFunc(s.items, min, s.cmp)
+	end, _ := slices.BinarySearchFunc(s.items, max, s.cmp)
+	s.items = slices.Delete(s.items, start, end)
+	return end - start
+}
+
+// Min returns the smallest element in the set, or panics if the set is empty.
+func (s *Buffered[T]) Min() T {
+	if s.IsEmpty() {
+		panic("smallset.Buffered.Min: set is empty")
+	}
+	s.Flush()
+	return s.items[0]
+}
+
+// Max returns the biggest element in the set, or panics if the set is empty.
+func (s *Buffered[T]) Max() T {
+	if s.IsEmpty() {
+		panic("smallset.Buffered.Max: set is empty")
+	}
+	s.Flush()
+	return s.items[len(s.items)-1]
+}
+
+// PopMin removes and returns the smallest element in the set, or panics if the set is empty.
+func (s *Buffered[T]) PopMin() T {
+	if s.IsEmpty() {
+		panic("smallset.Buffered.PopMin: set is empty")
+	}
+	s.Flush()
+	e := s.items[0]
+	s.items = slices.Delete(s.items, 0, 1)
+	return e
+}
+
+// PopMax removes and returns the biggest element in the set, or panics if the set is empty.
+func (s *Buffered[T]) PopMax() T {
+	if s.IsEmpty() {
+		panic("smallset.Buffered.PopMax: set is empty")
+	}
+	s.Flush()
+	last := len(s.items) - 1
+	e := s.items[last]
+	s.items = slices.Delete(s.items, last, last+1)
+	return e
+}
@@ -0,0 +1,64 @@
+package smallset
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestBuffered(t *testing.T) {
+	s := NewBuffered[int](4)
+	for _, e := range []int{30, 10, 20} {
+		s.Add(e)
+	}
+
+	// the staging area is not full yet
+	if len(s.items) != 0 || len(s.staged) != 3 {
+		t.Fatalf("expected 3 staged elements, got items %v and staged %v", s.items, s.staged)
+	}
+
+	if !s.Contains(20) || s.Add(20) {
+		t.Error("expected the staged 20 to be found")
+	}
+
+	s.Add(5)
+	if len(s.items) != 4 || len(s.staged) != 0 {
+		t.Fatalf("expected a flush, got items %v and staged %v", s.items, s.staged)
+	}
+
+	s.Add(15)
+	s.Add(25)
+	if !s.Remove(15) || !s.Remove(30) || s.Remove(99) {
+		t.Error("unexpected result of Remove")
+	}
+
+	expected := []int{5, 10, 20, 25}
+	if items := s.Items(); !slices.Equal(items, expected) {
+		t.Errorf("Items mismatch.\nExpected: %v\nActual: %v", expected, items)
+	}
+}
+
+func BenchmarkBufferedInsert(b *testing.B) {
+	const size = 100_000
+	vals := rand.New(rand.NewPCG(1, 2)).Perm(size)
+
+	b.Run("ordered", func(b *testing.B) {
+		b.ResetTimer()
+		for range b.N {
+			s := New[int](size)
+			for _, v := range vals {
+				s.Add(v)
+			}
+		}
+	})
+
+	b.Run("buffered", func(b *testing.B) {
+		b.ResetTimer()
+		for range b.N {
+			s := NewBuffered[int](256)
+			for _, v := range vals {
+				s.Add(v)
+			}
+		}
+	})
+}
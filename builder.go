@@ -0,0 +1,95 @@
+package smallset
+
+import (
+	"cmp"
+	"slices"
+)
+
+// Builder accumulates unsorted elements cheaply, and builds an [Ordered] set from them
+// with a single sort in [Builder.Build]. It's the fastest way to bulk-load a set when the
+// elements are produced one at a time, since every [Ordered.Add] would shift the slice.
+// Not safe for concurrent use.
+type Builder[T cmp.Ordered] struct {
+	items []T
+}
+
+// NewBuilder returns a builder with the provided capacity.
+// It panics if the capacity is <= 0.
+func NewBuilder[T cmp.Ordered](capacity int) *Builder[T] {
+	if capacity <= 0 {
+		panic("smallset.NewBuilder: capacity must be > 0")
+	}
+	return &Builder[T]{items: make([]T, 0, capacity)}
+}
+
+// Append adds the elements to the builder, in any order and with duplicates. O(1) complexity.
+func (b *Builder[T]) Append(items ...T) {
+	b.items = append(b.items, items...)
+}
+
+// Len returns the number of elements appended since the last build, including duplicates.
+func (b *Builder[T]) Len() int {
+	return len(b.items)
+}
+
+// Build returns a set with the appended elements, sorting and deduplicating them once.
+// The set takes ownership of the elements, and the builder is reset for reuse.
+// O(N*log(N)) complexity.
+func (b *Builder[T]) Build() *Ordered[T] {
+	items := b.items
+	b.items = nil
+	if len(items) == 0 {
+		return New[T](defaultCapacity)
+	}
+
+	if !slices.IsSorted(items) {
+		slices.Sort(items)
+	}
+	return &Ordered[T]{items: compact(items)}
+}
+
+// CustomBuilder is the [Builder] of [Custom] sets, which sorts the elements with the
+// provided compare function. Not safe for concurrent use.
+type CustomBuilder[T any] struct {
+	items []T
+	cmp   compareFunc[T]
+}
+
+// NewCustomBuilder returns a builder with the provided compare function and capacity.
+// It panics if cmp is nil or the capacity is <= 0.
+func NewCustomBuilder[T any](cmp func(a, b T) int, capacity int) *CustomBuilder[T] {
+	if cmp == nil {
+		panic("smallset.NewCustomBuilder: cmp cannot be nil")
+	}
+	if capacity <= 0 {
+		panic("smallset.NewCustomBuilder: capacity must be > 0")
+	}
+	return &CustomBuilder[T]{items: make([]T, 0, capacity), cmp: cmp}
+}
+
+// Append adds the elements to the builder, in any order and with duplicates. O(1) complexity.
+func (b *CustomBuilder[T]) Append(items ...T) {
+	b.items = append(b.items, items...)
+}
+
+// Len returns the number of elements appended since the last build, including duplicates.
+func (b *CustomBuilder[T]) Len() int {
+	return len(b.items)
+}
+
+// Build returns a set with the appended elements, sorting and deduplicating them once.
+// Among equivalent elements, the first appended is kept.
+// The set takes ownership of the elements, and the builder is reset for reuse.
+// O(N*log(N)) complexity.
+func (b *CustomBuilder[T]) Build() *Custom[T] {
+	items := b.items
+	b.items = nil
+	if len(items) == 0 {
+		return NewCustom(b.cmp, defaultCapacity)
+	}
+
+	if !slices.IsSortedFunc(items, b.cmp) {
+		slices.SortStableFunc(items, b.cmp)
+	}
+	return &Custom[T]{items: slices.CompactFunc(items, b.cmp.equal), cmp: b.cmp}
+}
@@ -0,0 +1,51 @@
+package smallset
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestBuilder(t *testing.T) {
+	b := NewBuilder[int](4)
+	b.Append(5, 1, 3)
+	b.Append(1)
+	b.Append(4, 5)
+
+	if b.Len() != 6 {
+		t.Errorf("expected Len 6, got %d", b.Len())
+	}
+
+	s := b.Build()
+	if expected := []int{1, 3, 4, 5}; !slices.Equal(s.items, expected) {
+		t.Errorf("Items mismatch.\nExpected: %v\nActual: %v", expected, s.items)
+	}
+
+	if b.Len() != 0 || !b.Build().IsEmpty() {
+		t.Errorf("expected the builder to be reset after Build")
+	}
+
+	// the built set must not share memory with the builder
+	b.Append(0)
+	if expected := []int{1, 3, 4, 5}; !slices.Equal(s.items, expected) {
+		t.Errorf("set modified by the builder: %v", s.items)
+	}
+}
+
+func TestCustomBuilder(t *testing.T) {
+	b := NewCustomBuilder(PersonCmp, 4)
+	for _, p := range people2 {
+		b.Append(p)
+	}
+
+	s := b.Build()
+	expected := []Person{
+		{ID: 20, Name: "Delta", Age: 2},
+		{ID: 30, Name: "Gamma", Age: 3},
+		{ID: 40, Name: "Beta", Age: 4},
+		{ID: 50, Name: "Alpha", Age: 5},
+	}
+
+	if !slices.Equal(s.items, expected) {
+		t.Errorf("Items mismatch.\nExpected: %v\nActual: %v", expected, s.items)
+	}
+}
@@ -0,0 +1,172 @@
+package smallset
+
+import (
+	"iter"
+	"math/bits"
+)
+
+// ByteSet is a set of bytes backed by a fixed 256-bit bitmap, which gives O(1) Add, Remove
+// and Contains, and set operations that process 64 elements at a time.
+// Like the other sets, elements are iterated in ascending order.
+// The zero value is an empty set ready to use. Not safe for concurrent use.
+type ByteSet struct {
+	words [4]uint64
+}
+
+// ByteSetFrom returns a set that contains the provided bytes.
+func ByteSetFrom(items ...byte) *ByteSet {
+	s := &ByteSet{}
+	for _, e := range items {
+		s.Add(e)
+	}
+	return s
+}
+
+// ToByteSet returns a set with the elements of the [Ordered] set of bytes.
+func ToByteSet(s *Ordered[byte]) *ByteSet {
+	defer s.pin()()
+	return ByteSetFrom(s.items...)
+}
+
+// ToOrdered returns an [Ordered] set with the elements of the set.
+func (s *ByteSet) ToOrdered() *Ordered[byte] {
+	if s.IsEmpty() {
+		return New[byte](defaultCapacity)
+	}
+	return &Ordered[byte]{items: s.Items()}
+}
+
+// Size returns the number of elements in the set.
+func (s *ByteSet) Size() int {
+	size := 0
+	for _, w := range s.words {
+		size += bits.OnesCount64(w)
+	}
+	return size
+}
+
+// IsEmpty returns whether the set has no elements.
+func (s *ByteSet) IsEmpty() bool {
+	return s.words == [4]uint64{}
+}
+
+// Clear removes all elements from the set.
+func (s *ByteSet) Clear() {
+	s.words = [4]uint64{}
+}
+
+// Clone returns a clone of the set.
+func (s *ByteSet) Clone() *ByteSet {
+	return &ByteSet{words: s.words}
+}
+
+// Contains returns whether the element is in the set. Operation is O(1).
+func (s *ByteSet) Contains(e byte) bool {
+	return s.words[e>>6]&(1<<(e&63)) != 0
+}
+
+// Add an element and returns whether is was added (true), or was already present (false).
+func (s *ByteSet) Add(e byte) bool {
+	if s.Contains(e) {
+		return false
+	}
+	s.words[e>>6] |= 1 << (e & 63)
+	return true
+}
+
+// Remove an element if present, and returns whether is was removed (true), or was never present (false).
+func (s *ByteSet) Remove(e byte) bool {
+	if !s.Contains(e) {
+		return false
+	}
+	s.words[e>>6] &^= 1 << (e & 63)
+	return true
+}
+
+// Min returns the smallest element in the set, or panics if the set is empty.
+func (s *ByteSet) Min() byte {
+	for i, w := range s.words {
+		if w != 0 {
+			return byte(64*i + bits.TrailingZeros64(w))
+		}
+	}
+	panic("smallset.ByteSet.Min: set is empty")
+}
+
+// Max returns the biggest element in the set, or panics if the set is empty.
+func (s *ByteSet) Max() byte {
+	for i := len(s.words) - 1; i >= 0; i-- {
+		if w := s.words[i]; w != 0 {
+			return byte(64*i + 63 - bits.LeadingZeros64(w))
+		}
+	}
+	panic("smallset.ByteSet.Max: set is empty")
+}
+
+// Items returns the elements of the set in ascending order.
+func (s *ByteSet) Items() []byte {
+	items := make([]byte, 0, s.Size())
+	for _, e := range s.Ascend() {
+		items = append(items, e)
+	}
+	return items
+}
+
+// Ascend returns an iterator over the set in ascending order, yielding the elements with their rank.
+func (s *ByteSet) Ascend() iter.Seq2[int, byte] {
+	return func(yield func(int, byte) bool) {
+		rank := 0
+		for i, w := range s.words {
+			for w != 0 {
+				if !yield(rank, byte(64*i+bits.TrailingZeros64(w))) {
+					return
+				}
+				w &= w - 1 // clear the lowest set bit
+				rank++
+			}
+		}
+	}
+}
+
+// IsEqual returns whether the two sets have the same elements.
+func (s *ByteSet) IsEqual(other *ByteSet) bool {
+	return s.words == other.words
+}
+
+// Intersect returns a new set containing only the common elements. O(1) complexity.
+func (s *ByteSet) Intersect(other *ByteSet) *ByteSet {
+	inter := &ByteSet{}
+	for i := range s.words {
+		inter.words[i] = s.words[i] & other.words[i]
+	}
+	return inter
+}
+
+// Difference returns a new set with all elements of this set that are not elements of other.
+// O(1) complexity.
+func (s *ByteSet) Difference(other *ByteSet) *ByteSet {
+	diff := &ByteSet{}
+	for i := range s.words {
+		diff.words[i] = s.words[i] &^ other.words[i]
+	}
+	return diff
+}
+
+// SymmetricDifference returns a new set with all elements which are in either this set
+// or the other set but not in both. O(1) complexity.
+func (s *ByteSet) SymmetricDifference(other *ByteSet) *ByteSet {
+	sdiff := &ByteSet{}
+	for i := range s.words {
+		sdiff.words[i] = s.words[i] ^ other.words[i]
+	}
+	return sdiff
+}
+
+// Union returns a new set with all elements in both sets. O(1) complexity.
+func (s *ByteSet) Union(other *ByteSet) *ByteSet {
+	union := &ByteSet{}
+	for i := range s.words {
+		union.words[i] = s.words[i] | other.words[i]
+	}
+	return union
+}
@@ -0,0 +1,73 @@
+package smallset
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestByteSet(t *testing.T) {
+	s := &ByteSet{}
+	if !s.IsEmpty() {
+		t.Fatalf("expected the zero value to be empty")
+	}
+
+	for _, e := range []byte{200, 0, 63, 64, 255, 63} {
+		s.Add(e)
+	}
+
+	expected := []byte{0, 63, 64, 200, 255}
+	if items := s.Items(); !slices.Equal(items, expected) {
+		t.Errorf("Items mismatch.\nExpected: %v\nActual: %v", expected, items)
+	}
+	if s.Size() != 5 || s.Min() != 0 || s.Max() != 255 {
+		t.Errorf("expected size 5, min 0 and max 255, got %d, %d and %d", s.Size(), s.Min(), s.Max())
+	}
+
+	if !s.Remove(0) || s.Remove(0) || s.Contains(0) {
+		t.Errorf("Remove(0) mismatch")
+	}
+	if s.Min() != 63 {
+		t.Errorf("expected min 63, got %d", s.Min())
+	}
+}
+
+func TestByteSetOperations(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	random := func() []byte {
+		items := make([]byte, rng.IntN(100))
+		for i := range items {
+			items[i] = byte(rng.IntN(256))
+		}
+		return items
+	}
+
+	for i := range 100 {
+		t.Run(fmt.Sprintf("Case_%d", i), func(t *testing.T) {
+			o1, o2 := From(random()...), From(random()...)
+			b1, b2 := ToByteSet(o1), ToByteSet(o2)
+
+			if !b1.ToOrdered().IsEqual(o1) {
+				t.Fatalf("conversion mismatch.\nExpected: %v\nActual: %v", o1.items, b1.Items())
+			}
+
+			ops := []struct {
+				name     string
+				res      *ByteSet
+				expected *Ordered[byte]
+			}{
+				{name: "Intersect", res: b1.Intersect(b2), expected: o1.Intersect(o2)},
+				{name: "Union", res: b1.Union(b2), expected: o1.Union(o2)},
+				{name: "Difference", res: b1.Difference(b2), expected: o1.Difference(o2)},
+				{name: "SymmetricDifference", res: b1.SymmetricDifference(b2), expected: o1.SymmetricDifference(o2)},
+			}
+
+			for _, op := range ops {
+				if items := op.res.Items(); !slices.Equal(items, op.expected.items) {
+					t.Errorf("%s mismatch.\nExpected: %v\nActual: %v", op.name, op.expected.items, items)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package smallset
+
+import (
+	"errors"
+	"iter"
+	"slices"
+)
+
+// ErrChangesTrimmed is returned when the changes after a sequence number are requested,
+// but some of them have already been trimmed from the change log.
+var ErrChangesTrimmed = errors.New("smallset: changes have been trimmed")
+
+// Op is the kind of operation recorded by a [Change].
+type Op uint8
+
+const (
+	OpAdd Op = iota + 1
+	OpRemove
+)
+
+func (o Op) String() string {
+	switch o {
+	case OpAdd:
+		return "add"
+	case OpRemove:
+		return "remove"
+	default:
+		return "unknown"
+	}
+}
+
+// Change is an entry of the change log of a set, recording that an element was added or removed.
+// See [Ordered.WithChangeLog] and [Custom.WithChangeLog].
+type Change[T any] struct {
+	Seq  uint64 // the sequence number of the change, starting from 1
+	Op   Op
+	Item T
+}
+
+// Backpressure is the policy of a subscription when its channel is full.
+// See [Ordered.Subscribe] and [Custom.Subscribe].
+type Backpressure uint8
+
+const (
+	// BackpressureBlock blocks the change of the set until the subscriber receives the previous
+	// changes, which requires the subscriber to receive from another goroutine.
+	BackpressureBlock Backpressure = iota
+
+	// BackpressureDrop discards the change. Subscribers can detect the changes
+	// they missed by the gaps in the sequence numbers.
+	BackpressureDrop
+)
+
+// changelog records the changes of a set in order, numbering them with increasing sequence numbers,
+// and sends them to its subscriptions.
+type changelog[T any] struct {
+	entries []Change[T]
+	seq     uint64
+	trimmed uint64 // the biggest sequence number trimmed
+	retain  bool   // whether the entries are kept, false if enabled only by subscriptions
+	subs    []*subscription[T]
+}
+
+type subscription[T any] struct {
+	ch     chan Change[T]
+	policy Backpressure
+}
+
+// enabled returns whether the change log was enabled by WithChangeLog.
+func (c *changelog[T]) enabled() bool {
+	return c != nil && c.retain
+}
+
+func (c *changelog[T]) record(op Op, e T) {
+	c.seq++
+	change := Change[T]{Seq: c.seq, Op: op, Item: e}
+	if c.retain {
+		c.entries = append(c.entries, change)
+	}
+
+	for _, sub := range c.subs {
+		if sub.policy == BackpressureDrop {
+			select {
+			case sub.ch <- change:
+			default:
+			}
+			continue
+		}
+		sub.ch <- change
+	}
+}
+
+// subscribe adds a subscription, returning its channel and the function to cancel it.
+func (c *changelog[T]) subscribe(buffer int, policy Backpressure) (<-chan Change[T], func()) {
+	sub := &subscription[T]{ch: make(chan Change[T], buffer), policy: policy}
+	c.subs = append(c.subs, sub)
+
+	cancel := func() {
+		i := slices.Index(c.subs, sub)
+		if i == -1 {
+			return
+		}
+		c.subs = slices.Delete(c.subs, i, i+1)
+		close(sub.ch)
+	}
+	return sub.ch, cancel
+}
+
+// recordDiff records the changes that turn the sorted slice old into the sorted slice new,
+// in ascending order of the elements.
+func (c *changelog[T]) recordDiff(old, new []T, cmp func(a, b T) int) {
+	i, j := 0, 0
+	for i < len(old) || j < len(new) {
+		switch {
+		case j == len(new) || (i < len(old) && cmp(old[i], new[j]) < 0):
+			c.record(OpRemove, old[i])
+			i++
+		case i == len(old) || cmp(new[j], old[i]) < 0:
+			c.record(OpAdd, new[j])
+			j++
+		default:
+			i++
+			j++
+		}
+	}
+}
+
+// since returns an iterator over the changes with a sequence number bigger than seq.
+func (c *changelog[T]) since(seq uint64) iter.Seq[Change[T]] {
+	i, _ := slices.BinarySearchFunc(c.entries, seq+1, func(c Change[T], seq uint64) int {
+		switch {
+		case c.Seq < seq:
+			return -1
+		case c.Seq > seq:
+			return 1
+		default:
+			return 0
+		}
+	})
+	return slices.Values(slices.Clip(c.entries[i:]))
+}
+
+// trim discards the changes with a sequence number smaller or equal than seq.
+func (c *changelog[T]) trim(seq uint64) {
+	c.trimmed = max(c.trimmed, min(seq, c.seq))
+	i := 0
+	for i < len(c.entries) && c.entries[i].Seq <= seq {
+		i++
+	}
+	c.entries = slices.Delete(c.entries, 0, i)
+}
+
+// delta returns the net changes after seq, sorted by cmp: the elements added and the ones removed.
+// Elements added and removed cancel out. An element removed and then added again is in both lists
+// if replaced is true, to carry a payload that may have changed, otherwise in none.
+func (c *changelog[T]) delta(seq uint64, cmp func(a, b T) int, replaced bool) (adds, removes []T, err error) {
+	if seq < c.trimmed {
+		return nil, nil, ErrChangesTrimmed
+	}
+
+	changes := slices.Collect(c.since(seq))
+	slices.SortStableFunc(changes, func(a, b Change[T]) int { return cmp(a.Item, b.Item) })
+
+	for i := 0; i < len(changes); {
+		// the changes of the same element are in [i, j), in the order they happened
+		j := i + 1
+		for j < len(changes) && cmp(changes[j].Item, changes[i].Item) == 0 {
+			j++
+		}
+
+		first, last := changes[i], changes[j-1]
+		switch {
+		case first.Op == OpAdd && last.Op == OpAdd:
+			adds = append(adds, last.Item)
+		case first.Op == OpRemove && last.Op == OpRemove:
+			removes = append(removes, first.Item)
+		case first.Op == OpRemove && last.Op == OpAdd && replaced:
+			removes = append(removes, first.Item)
+			adds = append(adds, last.Item)
+		}
+		i = j
+	}
+	return adds, removes, nil
+}
+
+// clone returns a copy of the change log without its subscriptions, or nil if it's not enabled.
+func (c *changelog[T]) clone() *changelog[T] {
+	if !c.enabled() {
+		return nil
+	}
+	return &changelog[T]{entries: slices.Clone(c.entries), seq: c.seq, trimmed: c.trimmed, retain: true}
+}
@@ -0,0 +1,272 @@
+package smallset
+
+import (
+	"cmp"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestChangeLog(t *testing.T) {
+	s := New[int](10).WithChangeLog()
+	s.Add(3)
+	s.Add(1)
+	s.Add(3)
+	s.Remove(1)
+	s.ApplyDiff([]int{5, 7}, []int{3})
+	s.RemoveBefore(6)
+	s.Clear()
+
+	expected := []Change[int]{
+		{Seq: 1, Op: OpAdd, Item: 3},
+		{Seq: 2, Op: OpAdd, Item: 1},
+		{Seq: 3, Op: OpRemove, Item: 1},
+		{Seq: 4, Op: OpRemove, Item: 3},
+		{Seq: 5, Op: OpAdd, Item: 5},
+		{Seq: 6, Op: OpAdd, Item: 7},
+		{Seq: 7, Op: OpRemove, Item: 5},
+		{Seq: 8, Op: OpRemove, Item: 7},
+	}
+
+	if changes := slices.Collect(s.Changes(0)); !slices.Equal(changes, expected) {
+		t.Errorf("Changes mismatch.\nExpected: %v\nActual: %v", expected, changes)
+	}
+
+	if changes := slices.Collect(s.Changes(6)); !slices.Equal(changes, expected[6:]) {
+		t.Errorf("Changes(6) mismatch.\nExpected: %v\nActual: %v", expected[6:], changes)
+	}
+
+	s.TrimChanges(4)
+	if changes := slices.Collect(s.Changes(0)); !slices.Equal(changes, expected[4:]) {
+		t.Errorf("Changes after trim mismatch.\nExpected: %v\nActual: %v", expected[4:], changes)
+	}
+
+	if seq := s.ChangeSeq(); seq != 8 {
+		t.Errorf("ChangeSeq expected 8, got %d", seq)
+	}
+}
+
+func TestChangeLogReplay(t *testing.T) {
+	s := NewCustom(cmp.Compare[int], 10).WithChangeLog()
+	replica := NewCustom(cmp.Compare[int], 10)
+	var seq uint64
+
+	sync := func() {
+		for c := range s.Changes(seq) {
+			switch c.Op {
+			case OpAdd:
+				replica.Add(c.Item)
+			case OpRemove:
+				replica.Remove(c.Item)
+			}
+			seq = c.Seq
+		}
+	}
+
+	for i := range 20 {
+		s.Add(i * 7 % 13)
+	}
+	sync()
+
+	s.RemoveBetween(3, 9)
+	s.ReplaceAt(0, -1)
+	s.RemoveIndices(1, 2)
+	sync()
+
+	if !replica.IsEqual(s) {
+		t.Errorf("replica mismatch.\nExpected: %v\nActual: %v", s.items, replica.items)
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	s := New[int](10)
+	changes, cancel := s.Subscribe(10, BackpressureBlock)
+	s.Add(3)
+	s.Add(1)
+	s.Remove(3)
+	cancel()
+	cancel()
+	s.Add(5)
+
+	expected := []Change[int]{
+		{Seq: 1, Op: OpAdd, Item: 3},
+		{Seq: 2, Op: OpAdd, Item: 1},
+		{Seq: 3, Op: OpRemove, Item: 3},
+	}
+
+	var received []Change[int]
+	for c := range changes {
+		received = append(received, c)
+	}
+	if !slices.Equal(received, expected) {
+		t.Errorf("Subscribe mismatch.\nExpected: %v\nActual: %v", expected, received)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected Changes to panic without WithChangeLog")
+		}
+	}()
+	s.Changes(0)
+}
+
+func TestSubscribeDrop(t *testing.T) {
+	s := NewCustom(cmp.Compare[int], 10).WithChangeLog()
+	changes, cancel := s.Subscribe(2, BackpressureDrop)
+	for i := range 4 {
+		s.Add(i)
+	}
+	cancel()
+
+	expected := []Change[int]{
+		{Seq: 1, Op: OpAdd, Item: 0},
+		{Seq: 2, Op: OpAdd, Item: 1},
+	}
+
+	var received []Change[int]
+	for c := range changes {
+		received = append(received, c)
+	}
+	if !slices.Equal(received, expected) {
+		t.Errorf("Subscribe mismatch.\nExpected: %v\nActual: %v", expected, received)
+	}
+
+	if seq := s.ChangeSeq(); seq != 4 {
+		t.Errorf("ChangeSeq expected 4, got %d", seq)
+	}
+	if clone := s.Clone(); clone.changelog.subs != nil {
+		t.Errorf("expected the clone to have no subscriptions, got %d", len(clone.changelog.subs))
+	}
+}
+
+func TestSubscribeBlock(t *testing.T) {
+	s := New[int](10)
+	changes, cancel := s.Subscribe(0, BackpressureBlock)
+
+	done := make(chan []int)
+	go func() {
+		var items []int
+		for c := range changes {
+			items = append(items, c.Item)
+		}
+		done <- items
+	}()
+
+	for i := range 100 {
+		s.Add(i)
+	}
+	cancel()
+
+	if items := <-done; len(items) != 100 {
+		t.Errorf("expected 100 changes, got %d", len(items))
+	}
+}
+
+func TestDeltaSince(t *testing.T) {
+	s := From(1, 2, 3).WithChangeLog()
+	replica := s.Clone()
+	seq := s.ChangeSeq()
+
+	s.Add(5)
+	s.Remove(1)
+	s.Add(4)
+	s.Remove(4)
+	s.Remove(2)
+	s.Add(2)
+
+	adds, removes, newSeq, err := s.DeltaSince(seq)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(adds, []int{5}) || !slices.Equal(removes, []int{1}) || newSeq != 6 {
+		t.Fatalf("unexpected delta: adds %v, removes %v, seq %d", adds, removes, newSeq)
+	}
+
+	replica.ApplyDiff(adds, removes)
+	if !replica.IsEqual(s) {
+		t.Errorf("expected replica %v, got %v", s.items, replica.items)
+	}
+
+	adds, removes, newSeq, err = s.DeltaSince(newSeq)
+	if err != nil || adds != nil || removes != nil || newSeq != 6 {
+		t.Errorf("expected an empty delta, got adds %v, removes %v, seq %d, err %v", adds, removes, newSeq, err)
+	}
+
+	s.TrimChanges(3)
+	if _, _, _, err := s.DeltaSince(2); !errors.Is(err, ErrChangesTrimmed) {
+		t.Errorf("expected ErrChangesTrimmed, got %v", err)
+	}
+	if _, _, _, err := s.DeltaSince(3); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
+
+func TestCustomDeltaSince(t *testing.T) {
+	s := NewCustom(PersonCmp, 10).WithChangeLog()
+	s.Add(Person{ID: 1, Name: "Bob"})
+	s.Add(Person{ID: 2, Name: "Carl"})
+
+	s.Remove(Person{ID: 2})
+	s.Add(Person{ID: 2, Name: "Carly"})
+
+	adds, removes, _, err := s.DeltaSince(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !slices.Equal(removes, []Person{{ID: 2, Name: "Carl"}}) {
+		t.Errorf("unexpected removes %v", removes)
+	}
+	if !slices.Equal(adds, []Person{{ID: 2, Name: "Carly"}}) {
+		t.Errorf("unexpected adds %v", adds)
+	}
+}
+
+func TestSetOperationsChangeLog(t *testing.T) {
+	s := From(1, 2, 3).WithChangeLog()
+	empty := New[int](1)
+
+	d12, _, d21 := s.Partition(empty)
+	results := []*Ordered[int]{
+		s.Union(empty),
+		empty.Union(s),
+		s.Difference(empty),
+		s.SymmetricDifference(empty),
+		empty.SymmetricDifference(s),
+		d12,
+		d21,
+		Merge(s),
+		Intersect(s),
+	}
+
+	for i, r := range results {
+		if !r.IsEqual(s) && !r.IsEmpty() {
+			t.Errorf("result %d: unexpected elements %v", i, r.items)
+		}
+		if r.changelog != nil {
+			t.Errorf("result %d: expected no change log, like the results with non-empty operands", i)
+		}
+	}
+
+	c := CustomFrom(cmp.Compare[int], 1, 2, 3).WithChangeLog()
+	cempty := NewCustom(cmp.Compare[int], 1)
+
+	cd12, _, cd21 := c.Partition(cempty)
+	cresults := []*Custom[int]{
+		c.Union(cempty),
+		cempty.Union(c),
+		c.Difference(cempty),
+		c.SymmetricDifference(cempty),
+		cempty.SymmetricDifference(c),
+		cd12,
+		cd21,
+		MergeCustom(cmp.Compare[int], c),
+		IntersectCustom(cmp.Compare[int], c),
+	}
+
+	for i, r := range cresults {
+		if r.changelog != nil {
+			t.Errorf("custom result %d: expected no change log, like the results with non-empty operands", i)
+		}
+	}
+}
@@ -0,0 +1,240 @@
+package smallset
+
+import (
+	"cmp"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// Comparator is a three-way comparison function that can be passed to [NewCustom] and
+// the other constructors of [Custom] sets. It can be built with [By] and refined with
+// [Comparator.ThenBy], which is less error-prone than writing multi-field comparators by hand.
+//
+//	byAgeThenName := smallset.By(func(p Person) int { return p.Age }).
+//		ThenBy(smallset.By(func(p Person) string { return p.Name }))
+//
+//	set := smallset.NewCustom(byAgeThenName, 10)
+type Comparator[T any] func(a, b T) int
+
+// By returns a [Comparator] that orders elements by the key extracted from them.
+// It panics if key is nil.
+func By[T any, K cmp.Ordered](key func(T) K) Comparator[T] {
+	if key == nil {
+		panic("smallset.By: key cannot be nil")
+	}
+	return func(a, b T) int {
+		return cmp.Compare(key(a), key(b))
+	}
+}
+
+// ThenBy returns a [Comparator] that orders elements by c, breaking ties with next.
+// It panics if next is nil.
+func (c Comparator[T]) ThenBy(next func(a, b T) int) Comparator[T] {
+	if next == nil {
+		panic("smallset.Comparator.ThenBy: next cannot be nil")
+	}
+	return func(a, b T) int {
+		if r := c(a, b); r != 0 {
+			return r
+		}
+		return next(a, b)
+	}
+}
+
+// Reverse returns a [Comparator] that orders elements in the opposite order of cmp.
+// It panics if cmp is nil.
+func Reverse[T any](cmp func(a, b T) int) Comparator[T] {
+	if cmp == nil {
+		panic("smallset.Reverse: cmp cannot be nil")
+	}
+	return func(a, b T) int {
+		return cmp(b, a)
+	}
+}
+
+// NewDescending returns an initialized [Custom] set with the provided capacity, sorted in descending order.
+// Since all methods follow the order of the set, Min and MinK return the biggest elements,
+// Max and MaxK the smallest ones, and Ascend iterates from the biggest to the smallest.
+// It's meant for leaderboard-style data that is always consumed in descending order.
+// It panics if the capacity is <= 0.
+func NewDescending[T cmp.Ordered](capacity int) *Custom[T] {
+	if capacity <= 0 {
+		panic("smallset.NewDescending: capacity must be > 0")
+	}
+	return NewCustom(Reverse(cmp.Compare[T]), capacity)
+}
+
+// Collator compares strings according to the rules of a locale.
+// It's implemented by *collate.Collator of the golang.org/x/text/collate package.
+type Collator interface {
+	CompareString(a, b string) int
+}
+
+// NewCollated returns an initialized [Custom] set of strings with the provided capacity,
+// sorted according to the collator, so that user-facing strings like names are sorted
+// per-locale rather than by their bytes. Strings that the collator considers equal,
+// for example when it ignores case or accents, are deduplicated.
+//
+//	names := smallset.NewCollated(collate.New(language.German), 10)
+//
+// Since a collator is not safe for concurrent use, it must not be shared with other goroutines.
+// It panics if c is nil or the capacity is <= 0.
+func NewCollated(c Collator, capacity int) *Custom[string] {
+	if c == nil {
+		panic("smallset.NewCollated: collator cannot be nil")
+	}
+	if capacity <= 0 {
+		panic("smallset.NewCollated: capacity must be > 0")
+	}
+	return NewCustom(c.CompareString, capacity)
+}
+
+// Comparable is implemented by the types that compare themselves, like [time.Time] and [netip.Addr].
+type Comparable[T any] interface {
+	Compare(other T) int
+}
+
+// NewComparable returns an initialized [Custom] set with the provided capacity, sorted by the
+// Compare method of the elements, without having to write a comparison closure.
+//
+//	deadlines := smallset.NewComparable[time.Time](10)
+//
+// It panics if the capacity is <= 0.
+func NewComparable[T Comparable[T]](capacity int) *Custom[T] {
+	if capacity <= 0 {
+		panic("smallset.NewComparable: capacity must be > 0")
+	}
+	return NewCustom(T.Compare, capacity)
+}
+
+// CompareWithTolerance returns a [Comparator] for floats that treats values within eps
+// of each other as equal, so that a [Custom] set doesn't accumulate near-duplicates
+// like 0.3 and 0.30000000000000004.
+//
+// Beware that equality within a tolerance is not transitive: a ~ b and b ~ c doesn't imply a ~ c.
+// Hence the elements kept by the set depend on the insertion order, and elements closer
+// than eps can coexist when a third one was in between. This is fine as long as the distinct
+// values of interest are much further apart than eps.
+// It panics if eps is negative or NaN.
+func CompareWithTolerance[F ~float32 | ~float64](eps F) Comparator[F] {
+	if !(eps >= 0) {
+		panic("smallset.CompareWithTolerance: eps must be >= 0")
+	}
+	return func(a, b F) int {
+		if a-b <= eps && b-a <= eps {
+			return 0
+		}
+		return cmp.Compare(a, b)
+	}
+}
+
+// CompareBytes16 compares two 16 bytes arrays like UUIDs in lexicographic order, the same as
+// [bytes.Compare], but loading them as two big-endian uint64 and comparing 64 bits at a time.
+// It's meant to be passed to [NewCustom], where lookups of random UUIDs take about a quarter less time
+// than with bytes.Compare.
+func CompareBytes16(a, b [16]byte) int {
+	if x, y := binary.BigEndian.Uint64(a[0:8]), binary.BigEndian.Uint64(b[0:8]); x != y {
+		return compareWords(x, y)
+	}
+	return compareWords(binary.BigEndian.Uint64(a[8:16]), binary.BigEndian.Uint64(b[8:16]))
+}
+
+// CompareBytes32 compares two 32 bytes arrays like SHA-256 hashes in lexicographic order,
+// the same as [bytes.Compare], but loading them as four big-endian uint64 and comparing 64 bits at a time.
+// It's meant to be passed to [NewCustom], where lookups of hashes take about a third less time
+// than with bytes.Compare.
+func CompareBytes32(a, b [32]byte) int {
+	if x, y := binary.BigEndian.Uint64(a[0:8]), binary.BigEndian.Uint64(b[0:8]); x != y {
+		return compareWords(x, y)
+	}
+	if x, y := binary.BigEndian.Uint64(a[8:16]), binary.BigEndian.Uint64(b[8:16]); x != y {
+		return compareWords(x, y)
+	}
+	if x, y := binary.BigEndian.Uint64(a[16:24]), binary.BigEndian.Uint64(b[16:24]); x != y {
+		return compareWords(x, y)
+	}
+	return compareWords(binary.BigEndian.Uint64(a[24:32]), binary.BigEndian.Uint64(b[24:32]))
+}
+
+// compareWords compares two words loaded in big-endian order,
+// which is equivalent to comparing their bytes in lexicographic order.
+func compareWords(x, y uint64) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// ErrInconsistentCmp is returned by [ValidateCmp] when a comparison function is not a valid ordering.
+var ErrInconsistentCmp = errors.New("smallset: inconsistent comparison function")
+
+// ValidateCmp checks that cmp is a consistent three-way comparison over the provided samples,
+// returning an error wrapping [ErrInconsistentCmp] that describes the first violation found:
+//   - reflexivity: cmp(a, a) == 0
+//   - antisymmetry: cmp(a, b) and cmp(b, a) have opposite signs
+//   - transitivity: a < b and b < c implies a < c
+//   - equality consistency: a == b implies that a and b compare the same way with every c
+//
+// A broken comparator silently corrupts the sets that use it, so this is meant for tests
+// and debug builds. It's O(N^3), so keep the samples small.
+// It panics if cmp is nil.
+func ValidateCmp[T any](cmp func(a, b T) int, samples []T) error {
+	if cmp == nil {
+		panic("smallset.ValidateCmp: cmp cannot be nil")
+	}
+
+	for _, a := range samples {
+		if c := cmp(a, a); c != 0 {
+			return fmt.Errorf("%w: reflexivity violated: cmp(%v, %v) = %d", ErrInconsistentCmp, a, a, c)
+		}
+	}
+
+	for _, a := range samples {
+		for _, b := range samples {
+			ab, ba := sign(cmp(a, b)), sign(cmp(b, a))
+			if ab != -ba {
+				return fmt.Errorf("%w: antisymmetry violated: cmp(%v, %v) = %d but cmp(%v, %v) = %d",
+					ErrInconsistentCmp, a, b, ab, b, a, ba)
+			}
+		}
+	}
+
+	for _, a := range samples {
+		for _, b := range samples {
+			ab := sign(cmp(a, b))
+			if ab > 0 {
+				continue
+			}
+
+			for _, c := range samples {
+				ac, bc := sign(cmp(a, c)), sign(cmp(b, c))
+				switch {
+				case ab == 0 && ac != bc:
+					return fmt.Errorf("%w: equality consistency violated: %v == %v but cmp(%v, %v) = %d and cmp(%v, %v) = %d",
+						ErrInconsistentCmp, a, b, a, c, ac, b, c, bc)
+
+				case ab < 0 && bc < 0 && ac >= 0:
+					return fmt.Errorf("%w: transitivity violated: %v < %v < %v but cmp(%v, %v) = %d",
+						ErrInconsistentCmp, a, b, c, a, c, ac)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func sign(c int) int {
+	switch {
+	case c < 0:
+		return -1
+	case c > 0:
+		return 1
+	default:
+		return 0
+	}
+}
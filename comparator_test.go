@@ -0,0 +1,261 @@
+package smallset
+
+import (
+	"bytes"
+	"cmp"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"net/netip"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestComparatorBy(t *testing.T) {
+	byAgeThenName := By(func(p Person) int { return p.Age }).
+		ThenBy(By(func(p Person) string { return p.Name }))
+
+	cases := []struct {
+		a, b     Person
+		expected int
+	}{
+		{a: Person{Age: 1}, b: Person{Age: 2}, expected: -1},
+		{a: Person{Age: 3, Name: "A"}, b: Person{Age: 2, Name: "B"}, expected: 1},
+		{a: Person{Age: 2, Name: "A"}, b: Person{Age: 2, Name: "B"}, expected: -1},
+		{a: Person{Age: 2, Name: "A", ID: 1}, b: Person{Age: 2, Name: "A", ID: 2}, expected: 0},
+	}
+
+	for i, test := range cases {
+		t.Run(fmt.Sprintf("Case_%d", i), func(t *testing.T) {
+			if res := byAgeThenName(test.a, test.b); res != test.expected {
+				t.Errorf("expected %d, got %d", test.expected, res)
+			}
+		})
+	}
+}
+
+func TestComparatorWithCustom(t *testing.T) {
+	s := NewCustom(By(func(p Person) string { return p.Name }), 10)
+	for _, p := range people1 {
+		s.Add(p)
+	}
+
+	names := make([]string, 0, s.Size())
+	for _, p := range s.Ascend() {
+		names = append(names, p.Name)
+	}
+
+	expected := []string{"Alice", "Bob", "Carly (Duplicate)", "Charlie", "Eva (Duplicate)", "Eve"}
+	if !slices.Equal(names, expected) {
+		t.Errorf("Expected %v, got %v", expected, names)
+	}
+}
+
+func TestReverse(t *testing.T) {
+	byID := Reverse(PersonCmp)
+	if byID(Person{ID: 1}, Person{ID: 2}) <= 0 {
+		t.Errorf("expected reversed comparator to order 1 after 2")
+	}
+	if byID(Person{ID: 1}, Person{ID: 1}) != 0 {
+		t.Errorf("expected reversed comparator to preserve equality")
+	}
+}
+
+func TestNewDescending(t *testing.T) {
+	s := NewDescending[int](10)
+	for _, e := range []int{5, 1, 9, 3, 9} {
+		s.Add(e)
+	}
+
+	if !slices.Equal(s.items, []int{9, 5, 3, 1}) {
+		t.Errorf("Expected %v, got %v", []int{9, 5, 3, 1}, s.items)
+	}
+	if s.Min() != 9 || s.Max() != 1 {
+		t.Errorf("expected Min 9 and Max 1, got %d and %d", s.Min(), s.Max())
+	}
+	if top := s.MinK(2); !slices.Equal(top, []int{9, 5}) {
+		t.Errorf("expected MinK(2) to be the top two %v, got %v", []int{9, 5}, top)
+	}
+	if res := slices.Collect(s.BetweenValues(8, 2)); !slices.Equal(res, []int{5, 3}) {
+		t.Errorf("expected BetweenValues(8, 2) to be %v, got %v", []int{5, 3}, res)
+	}
+}
+
+// foldCollator is a [Collator] that ignores case, like a collator with the IgnoreCase option.
+type foldCollator struct{}
+
+func (foldCollator) CompareString(a, b string) int {
+	return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+}
+
+func TestNewCollated(t *testing.T) {
+	s := NewCollated(foldCollator{}, 10)
+	for _, name := range []string{"bob", "Alice", "alice", "Carl"} {
+		s.Add(name)
+	}
+
+	expected := []string{"Alice", "bob", "Carl"}
+	if !slices.Equal(s.items, expected) {
+		t.Errorf("Expected %v, got %v", expected, s.items)
+	}
+}
+
+func TestNewComparable(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	times := NewComparable[time.Time](10)
+	times.Add(now.Add(time.Hour))
+	times.Add(now)
+	times.Add(now.In(time.FixedZone("CET", 3600))) // same instant, different location
+
+	if times.Size() != 2 || !times.Min().Equal(now) {
+		t.Errorf("unexpected times %v", times.Items())
+	}
+
+	addrs := NewComparable[netip.Addr](10)
+	for _, a := range []string{"10.0.0.2", "::1", "10.0.0.1", "10.0.0.2"} {
+		addrs.Add(netip.MustParseAddr(a))
+	}
+
+	expected := []netip.Addr{netip.MustParseAddr("10.0.0.1"), netip.MustParseAddr("10.0.0.2"), netip.MustParseAddr("::1")}
+	if !slices.Equal(addrs.Items(), expected) {
+		t.Errorf("expected %v, got %v", expected, addrs.Items())
+	}
+}
+
+func TestCompareWithTolerance(t *testing.T) {
+	s := NewCustom(CompareWithTolerance(1e-9), 10)
+	for _, e := range []float64{0.3, 0.1 + 0.2, 1, 1 + 1e-12, 0.30001} {
+		s.Add(e)
+	}
+
+	if !slices.Equal(s.items, []float64{0.3, 0.30001, 1}) {
+		t.Errorf("Expected %v, got %v", []float64{0.3, 0.30001, 1}, s.items)
+	}
+	if !s.Contains(0.1 + 0.2) {
+		t.Errorf("expected 0.1 + 0.2 to be considered equal to 0.3")
+	}
+}
+
+func TestCompareBytes(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	random := func() [32]byte {
+		var a [32]byte
+		for i := range a {
+			// few distinct values, to have long common prefixes
+			a[i] = byte(rng.IntN(3))
+		}
+		return a
+	}
+
+	for range 1000 {
+		a, b := random(), random()
+		if rng.IntN(10) == 0 {
+			b = a
+		}
+
+		if res, expected := CompareBytes32(a, b), bytes.Compare(a[:], b[:]); res != expected {
+			t.Fatalf("CompareBytes32(%v, %v) expected %d, got %d", a, b, expected, res)
+		}
+
+		a16, b16 := [16]byte(a[16:]), [16]byte(b[16:])
+		if res, expected := CompareBytes16(a16, b16), bytes.Compare(a16[:], b16[:]); res != expected {
+			t.Fatalf("CompareBytes16(%v, %v) expected %d, got %d", a16, b16, expected, res)
+		}
+	}
+}
+
+func BenchmarkCompareBytes16(b *testing.B) {
+	r := rand.New(rand.NewPCG(1, 2))
+	uuids := make([][16]byte, 1000)
+	for i := range uuids {
+		binary.BigEndian.PutUint64(uuids[i][0:8], r.Uint64())
+		binary.BigEndian.PutUint64(uuids[i][8:16], r.Uint64())
+	}
+
+	b.Run("bytes", func(b *testing.B) {
+		s := CustomFrom(func(a, b [16]byte) int { return bytes.Compare(a[:], b[:]) }, uuids...)
+		b.ResetTimer()
+		for i := range b.N {
+			s.Contains(uuids[i%len(uuids)])
+		}
+	})
+
+	b.Run("words", func(b *testing.B) {
+		s := CustomFrom(CompareBytes16, uuids...)
+		b.ResetTimer()
+		for i := range b.N {
+			s.Contains(uuids[i%len(uuids)])
+		}
+	})
+}
+
+func BenchmarkCompareBytes32(b *testing.B) {
+	hashes := make([][32]byte, 1000)
+	for i := range hashes {
+		hashes[i] = sha256.Sum256(binary.BigEndian.AppendUint64(nil, uint64(i)))
+	}
+
+	b.Run("bytes", func(b *testing.B) {
+		s := CustomFrom(func(a, b [32]byte) int { return bytes.Compare(a[:], b[:]) }, hashes...)
+		b.ResetTimer()
+		for i := range b.N {
+			s.Contains(hashes[i%len(hashes)])
+		}
+	})
+
+	b.Run("words", func(b *testing.B) {
+		s := CustomFrom(CompareBytes32, hashes...)
+		b.ResetTimer()
+		for i := range b.N {
+			s.Contains(hashes[i%len(hashes)])
+		}
+	})
+}
+
+func TestValidateCmp(t *testing.T) {
+	floats := []float64{0, 0.5, 1, 1.5, 2, math.NaN()}
+
+	cases := []struct {
+		name    string
+		cmp     func(a, b float64) int
+		isValid bool
+	}{
+		{name: "compare", cmp: cmp.Compare[float64], isValid: true},
+		{name: "reverse", cmp: Reverse(cmp.Compare[float64]), isValid: true},
+		{name: "irreflexive", cmp: func(a, b float64) int { return -1 }, isValid: false},
+		{name: "less than", cmp: func(a, b float64) int {
+			if a < b {
+				return -1
+			}
+			return 1
+		}, isValid: false},
+		{name: "naive float", cmp: func(a, b float64) int {
+			switch {
+			case a < b:
+				return -1
+			case a > b:
+				return 1
+			default:
+				return 0
+			}
+		}, isValid: false},
+		{name: "tolerance", cmp: CompareWithTolerance(0.6), isValid: false},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateCmp(test.cmp, floats)
+			if test.isValid && err != nil {
+				t.Errorf("expected valid comparator, got %v", err)
+			}
+			if !test.isValid && !errors.Is(err, ErrInconsistentCmp) {
+				t.Errorf("expected error %v, got %v", ErrInconsistentCmp, err)
+			}
+		})
+	}
+}
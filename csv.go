@@ -0,0 +1,123 @@
+package smallset
+
+import (
+	"cmp"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ReadCSVColumn returns a set with the values in the column col (starting from 0) of the CSV
+// records read from r, parsed according to the type of T: base 10 for integers, and the syntax
+// of [strconv.ParseFloat] for floats. Values are trimmed of surrounding spaces, and empty values
+// are skipped. Records can have a different number of fields, but they must all have the column.
+// A header row must be consumed from r beforehand, for example with a [bufio.Reader].
+// Parsing errors wrap [ErrInvalidFormat] and report the line. It panics if col is < 0.
+func ReadCSVColumn[T cmp.Ordered](r io.Reader, col int) (*Ordered[T], error) {
+	if col < 0 {
+		panic("smallset.ReadCSVColumn: col must be >= 0")
+	}
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+
+	var items []T
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidFormat, err)
+		}
+
+		line, _ := reader.FieldPos(0)
+		if col >= len(record) {
+			return nil, fmt.Errorf("%w: line %d: missing column %d", ErrInvalidFormat, line, col)
+		}
+
+		value := strings.TrimSpace(record[col])
+		if value == "" {
+			continue
+		}
+
+		e, err := parseOrdered[T](value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %w", ErrInvalidFormat, line, err)
+		}
+		items = append(items, e)
+	}
+	return From(items...), nil
+}
+
+// WriteCSV writes the elements of the set to w as CSV, one per record in ascending order.
+// Floats are formatted with the shortest representation that parses back to the same value.
+func (s *Ordered[T]) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	record := make([]string, 1)
+	for _, e := range s.Ascend() {
+		record[0] = formatOrdered(e)
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// parseOrdered parses the text representation of an element, according to the kind of T.
+func parseOrdered[T cmp.Ordered](s string) (T, error) {
+	var e T
+	v := reflect.ValueOf(&e).Elem()
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		x, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return e, err
+		}
+		v.SetInt(x)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		x, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return e, err
+		}
+		v.SetUint(x)
+
+	case reflect.Float32, reflect.Float64:
+		x, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return e, err
+		}
+		v.SetFloat(x)
+
+	case reflect.String:
+		v.SetString(s)
+	}
+	return e, nil
+}
+
+// formatOrdered returns the text representation of an element, the inverse of parseOrdered.
+func formatOrdered[T cmp.Ordered](e T) string {
+	v := reflect.ValueOf(e)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10)
+
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits())
+
+	default:
+		return v.String()
+	}
+}
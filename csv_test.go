@@ -0,0 +1,65 @@
+package smallset
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestReadCSVColumn(t *testing.T) {
+	cases := []struct {
+		csv      string
+		col      int
+		expected []int
+		err      error
+	}{
+		{csv: "", col: 0, expected: []int{}},
+		{csv: "3\n1\n2\n1\n", col: 0, expected: []int{1, 2, 3}},
+		{csv: "a,3\nb, 1 \nc,\nd,3", col: 1, expected: []int{1, 3}},
+		{csv: "a,3\nb\n", col: 1, err: ErrInvalidFormat},
+		{csv: "a,3\nb,x\n", col: 1, err: ErrInvalidFormat},
+		{csv: "a,\"3\n", col: 1, err: ErrInvalidFormat},
+	}
+
+	for i, test := range cases {
+		t.Run(fmt.Sprintf("Case_%d", i), func(t *testing.T) {
+			set, err := ReadCSVColumn[int](strings.NewReader(test.csv), test.col)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("expected error %v, got %v", test.err, err)
+			}
+			if err != nil {
+				return
+			}
+
+			if !slices.Equal(set.items, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, set.items)
+			}
+		})
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	strs := From("b", "a,c", "with \"quotes\"")
+	var buf bytes.Buffer
+	if err := strs.WriteCSV(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	read, err := ReadCSVColumn[string](&buf, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !read.IsEqual(strs) {
+		t.Errorf("expected %v, got %v", strs.items, read.items)
+	}
+
+	floats := From(0.1, 1e300, -2.5)
+	buf.Reset()
+	floats.WriteCSV(&buf)
+	if expected := "-2.5\n0.1\n1e+300\n"; buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+}
@@ -0,0 +1,114 @@
+package smallset
+
+// Cursor is a bidirectional iterator over an [Ordered] or [Custom] set, obtained by calling
+// their Cursor method. It's anchored to an element rather than to an index, so it remains valid
+// when elements are added or removed elsewhere in the set, even when the element under the cursor
+// is itself removed. Long-lived scans over a mutating set never skip or repeat elements.
+//
+// Each move performs a binary search, so it's O(log(N)).
+//
+//	for c := s.Cursor(); c.Next(); {
+//		fmt.Println(c.Value())
+//	}
+type Cursor[T any] struct {
+	set indexed[T]
+
+	value T
+	state cursorState
+}
+
+type cursorState uint8
+
+const (
+	beforeStart cursorState = iota
+	atValue
+	afterEnd
+)
+
+// Valid returns whether the cursor is positioned at an element.
+func (c *Cursor[T]) Valid() bool {
+	return c.state == atValue
+}
+
+// Value returns the element the cursor is positioned at.
+// The element might have been removed from the set after the cursor moved to it.
+// It panics if the cursor is not valid.
+func (c *Cursor[T]) Value() T {
+	if c.state != atValue {
+		panic("smallset.Cursor.Value: cursor is not valid")
+	}
+	return c.value
+}
+
+// Next moves the cursor to the smallest element bigger than the current one,
+// or to the smallest element of the set if the cursor was never moved.
+// It returns whether such element exists, otherwise the cursor moves past the end of the set.
+func (c *Cursor[T]) Next() bool {
+	var i int
+
+	switch c.state {
+	case beforeStart:
+		i = 0
+
+	case atValue:
+		j, found := c.set.Find(c.value)
+		if found {
+			j++
+		}
+		i = j
+
+	case afterEnd:
+		return false
+	}
+
+	return c.moveTo(i, afterEnd)
+}
+
+// Prev moves the cursor to the biggest element smaller than the current one,
+// or to the biggest element of the set if the cursor is past the end.
+// It returns whether such element exists, otherwise the cursor moves before the start of the set.
+func (c *Cursor[T]) Prev() bool {
+	var i int
+
+	switch c.state {
+	case beforeStart:
+		return false
+
+	case atValue:
+		j, _ := c.set.Find(c.value)
+		i = j - 1
+
+	case afterEnd:
+		i = c.set.Size() - 1
+	}
+
+	return c.moveTo(i, beforeStart)
+}
+
+// Seek moves the cursor to the smallest element bigger or equal to e.
+// It returns whether such element exists, otherwise the cursor moves past the end of the set.
+func (c *Cursor[T]) Seek(e T) bool {
+	i, _ := c.set.Find(e)
+	return c.moveTo(i, afterEnd)
+}
+
+// moveTo moves the cursor to the element at index i if it exists, otherwise to the fallback state.
+func (c *Cursor[T]) moveTo(i int, fallback cursorState) bool {
+	if i < 0 || i >= c.set.Size() {
+		var zero T
+		c.value = zero
+		c.state = fallback
+		return false
+	}
+
+	c.value = c.set.At(i)
+	c.state = atValue
+	return true
+}
+
+// indexed is the index-based access to the elements of a set shared by [Ordered] and [Custom].
+type indexed[T any] interface {
+	Size() int
+	At(i int) T
+	Find(e T) (int, bool)
+}
@@ -0,0 +1,77 @@
+package smallset
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestCursorIteration(t *testing.T) {
+	s := From(1, 3, 5, 7)
+
+	var asc []int
+	for c := s.Cursor(); c.Next(); {
+		asc = append(asc, c.Value())
+	}
+	if !slices.Equal(asc, s.items) {
+		t.Errorf("Expected %v, got %v", s.items, asc)
+	}
+
+	c := s.Cursor()
+	for c.Next() {
+	}
+
+	var desc []int
+	for c.Prev() {
+		desc = append(desc, c.Value())
+	}
+	if !slices.Equal(desc, []int{7, 5, 3, 1}) {
+		t.Errorf("Expected %v, got %v", []int{7, 5, 3, 1}, desc)
+	}
+	if c.Valid() {
+		t.Errorf("expected cursor before the start to be invalid")
+	}
+}
+
+func TestCursorMutations(t *testing.T) {
+	s := From(10, 20, 30, 40)
+	c := s.Cursor()
+
+	var seen []int
+	for c.Next() {
+		v := c.Value()
+		seen = append(seen, v)
+
+		switch v {
+		case 10:
+			s.Add(5)     // before the cursor, must not be seen
+			s.Add(15)    // after the cursor, must be seen
+			s.Remove(10) // the element under the cursor
+		case 20:
+			s.Remove(30)
+			s.Add(25)
+		}
+	}
+
+	expected := []int{10, 15, 20, 25, 40}
+	if !slices.Equal(seen, expected) {
+		t.Errorf("Expected %v, got %v", expected, seen)
+	}
+}
+
+func TestCursorSeek(t *testing.T) {
+	s := CustomFrom(PersonCmp, people2...)
+	c := s.Cursor()
+
+	if !c.Seek(Person{ID: 35}) || c.Value().ID != 40 {
+		t.Errorf("expected Seek(35) to move to 40")
+	}
+	if !c.Prev() || c.Value().ID != 30 {
+		t.Errorf("expected Prev to move to 30")
+	}
+	if c.Seek(Person{ID: 51}) || c.Valid() {
+		t.Errorf("expected Seek(51) to move past the end")
+	}
+	if !c.Prev() || c.Value().ID != 50 {
+		t.Errorf("expected Prev past the end to move to 50")
+	}
+}
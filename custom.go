@@ -2,9 +2,15 @@ package smallset
 
 import (
 	"cmp"
+	"container/heap"
+	"encoding/binary"
 	"fmt"
+	"hash"
 	"iter"
+	"math/bits"
+	"math/rand/v2"
 	"slices"
+	"sort"
 )
 
 // Custom is a slice-based set sorted in ascending order, as determined by the
@@ -15,8 +21,18 @@ import (
 // The capacity of the set can dynamically grow, but the performance would start to deteriorate.
 // Not safe for concurrent use.
 type Custom[T any] struct {
-	items []T
-	cmp   compareFunc[T]
+	items      []T
+	cmp        compareFunc[T]
+	tombstones []T       // elements marked for removal, see [Custom.MarkRemove]
+	shared     bool      // whether items is shared with a snapshot, see [Custom.Snapshot]
+	owner      owner     // goroutine that owns the set in debug builds, see [Custom.Handoff]
+	shrink     float64   // shrink threshold of len/cap, see [Custom.WithShrink]
+	growth     Growth    // see [Custom.WithGrowth]
+	store      *store[T] // nil unless the set is backed by a [Storage], see [NewStoredCustom]
+
+	// optional structures, nil unless enabled
+	fingerprint *fingerprint[T] // see [Custom.WithFingerprint]
+	changelog   *changelog[T]   // see [Custom.WithChangeLog]
 }
 
 // The three-way comparison function:
@@ -78,19 +94,217 @@ func CustomFrom[T any](cmp func(a, b T) int, items ...T) *Custom[T] {
 	return &Custom[T]{cmp: compare, items: copy}
 }
 
+// AdoptCustom returns a set with the provided compare function that takes ownership of the
+// provided slice, sorting and deduplicating it in place. Unlike [CustomFrom], it doesn't copy
+// the elements, which keeps big bulk loads allocation-free.
+// The set aliases the slice: the caller must not use it after the call, because its order,
+// its length and the elements past the new length are changed, and later set operations
+// change it further.
+//
+// It panics if cmp is nil.
+func AdoptCustom[T any](cmp func(a, b T) int, items []T) *Custom[T] {
+	if cmp == nil {
+		panic("smallset.AdoptCustom: cmp cannot be nil")
+	}
+
+	compare := compareFunc[T](cmp)
+	slices.SortFunc(items, compare)
+	return &Custom[T]{cmp: compare, items: slices.CompactFunc(items, compare.equal)}
+}
+
+// CustomFromSeq returns an initialized set that contains the elements of the sequence,
+// sorted by the provided compare function cmp.
+// The elements are sorted once at the end, which is skipped altogether if the sequence
+// is already in ascending order (e.g. when draining a min-heap).
+//
+// It panics if cmp is nil.
+func CustomFromSeq[T any](cmp func(a, b T) int, seq iter.Seq[T]) *Custom[T] {
+	if cmp == nil {
+		panic("smallset.CustomFromSeq: cmp cannot be nil")
+	}
+
+	items := slices.Collect(seq)
+	if len(items) == 0 {
+		return NewCustom(cmp, defaultCapacity)
+	}
+
+	compare := compareFunc[T](cmp)
+	if !slices.IsSortedFunc(items, compare) {
+		slices.SortFunc(items, compare)
+	}
+	items = slices.CompactFunc(items, compare.equal)
+	return &Custom[T]{cmp: compare, items: items}
+}
+
+// CustomFromHeap returns an initialized set that contains the elements of the heap,
+// sorted by the provided compare function cmp.
+// The heap is drained by repeatedly calling [heap.Pop].
+//
+// It panics if cmp is nil or an element of the heap is not of type T.
+func CustomFromHeap[T any](cmp func(a, b T) int, h heap.Interface) *Custom[T] {
+	if cmp == nil {
+		panic("smallset.CustomFromHeap: cmp cannot be nil")
+	}
+	return CustomFromSeq(cmp, drain[T](h))
+}
+
+// CustomFromChan returns an initialized set that contains all the elements received from the channel,
+// sorted by the provided compare function cmp.
+// It blocks until the channel is closed, then sorts the elements once.
+//
+// It panics if cmp is nil.
+func CustomFromChan[T any](cmp func(a, b T) int, ch <-chan T) *Custom[T] {
+	if cmp == nil {
+		panic("smallset.CustomFromChan: cmp cannot be nil")
+	}
+	return CustomFromSeq(cmp, receive(ch))
+}
+
+// WithFingerprint enables the incremental maintenance of the [Custom.Fingerprint] of the set,
+// using the provided hash function. Elements that compare equal must have the same hash.
+// It returns s, to allow chaining with the constructor.
+// It panics if hash is nil.
+func (s *Custom[T]) WithFingerprint(hash func(T) uint64) *Custom[T] {
+	if hash == nil {
+		panic("smallset.Custom.WithFingerprint: hash cannot be nil")
+	}
+
+	defer s.pin()()
+	s.fingerprint = newFingerprint(hash, s.items)
+	return s
+}
+
+// WithShrink enables an auto-compaction policy, which reallocates the underlying slice to
+// twice the size of the set whenever a removal leaves its length below fraction * capacity.
+// It avoids stranding large capacity on long-lived sets pruned with range removals,
+// like [Custom.RemoveBefore] or [Custom.RemoveBetween], at the cost of a copy when it triggers.
+// It returns s, to allow chaining with the constructor.
+// It panics if fraction is not in (0, 0.5].
+func (s *Custom[T]) WithShrink(fraction float64) *Custom[T] {
+	if !(fraction > 0 && fraction <= 0.5) {
+		panic("smallset.Custom.WithShrink: fraction must be in (0, 0.5]")
+	}
+
+	s.shrink = fraction
+	return s
+}
+
+// WithGrowth sets the strategy used to grow the underlying slice when an insertion exceeds
+// its capacity. By default it grows like the built-in append, which may over-allocate
+// for memory-tight workloads or reallocate too often for insert-heavy ones.
+// It returns s, to allow chaining with the constructor.
+func (s *Custom[T]) WithGrowth(g Growth) *Custom[T] {
+	s.growth = g
+	return s
+}
+
+// WithChangeLog enables the recording of every addition and removal to the set, which can be
+// replayed with [Custom.Changes] instead of diffing snapshots of the set.
+// Operations that rebuild the set, like Load or ApplyDiff, record the net changes in ascending order.
+// The log grows with every change until it's trimmed with [Custom.TrimChanges].
+// It returns s, to allow chaining with the constructor.
+func (s *Custom[T]) WithChangeLog() *Custom[T] {
+	if s.changelog == nil {
+		s.changelog = &changelog[T]{}
+	}
+	s.changelog.retain = true
+	return s
+}
+
+// ChangeSeq returns the sequence number of the last change recorded, or 0 if there is none.
+// It panics if the change log is not enabled with [Custom.WithChangeLog].
+func (s *Custom[T]) ChangeSeq() uint64 {
+	if !s.changelog.enabled() {
+		panic("smallset.Custom.ChangeSeq: change log is not enabled")
+	}
+	return s.changelog.seq
+}
+
+// Changes returns an iterator over the recorded changes with a sequence number bigger than since,
+// in the order they happened. Use since = 0 to get all the changes that haven't been trimmed.
+// It panics if the change log is not enabled with [Custom.WithChangeLog].
+func (s *Custom[T]) Changes(since uint64) iter.Seq[Change[T]] {
+	if !s.changelog.enabled() {
+		panic("smallset.Custom.Changes: change log is not enabled")
+	}
+	return s.changelog.since(since)
+}
+
+// TrimChanges discards the recorded changes with a sequence number smaller or equal than seq,
+// typically after all consumers have processed them.
+// It panics if the change log is not enabled with [Custom.WithChangeLog].
+func (s *Custom[T]) TrimChanges(seq uint64) {
+	if !s.changelog.enabled() {
+		panic("smallset.Custom.TrimChanges: change log is not enabled")
+	}
+	s.changelog.trim(seq)
+}
+
+// Comparator returns the compare function of the set, so that code outside the package
+// can build compatible sets without threading the original function through every layer.
+func (s *Custom[T]) Comparator() func(a, b T) int {
+	return s.cmp
+}
+
+// DeltaSince returns the net changes recorded after seq, for replicas to pull incremental updates
+// instead of full snapshots: the elements added and the ones removed, both in ascending order,
+// and the sequence number to pass to the next call. Elements added and then removed, or vice versa,
+// are omitted.
+// An element removed and then added again, possibly with a different payload, is in both lists:
+// replicas must apply the removals before the additions.
+// A replica starts from a copy of the set and its [Custom.ChangeSeq].
+// It returns [ErrChangesTrimmed] if some changes after seq have been trimmed, in which case the replica
+// must start over. It panics if the change log is not enabled with [Custom.WithChangeLog].
+func (s *Custom[T]) DeltaSince(seq uint64) (adds, removes []T, newSeq uint64, err error) {
+	if !s.changelog.enabled() {
+		panic("smallset.Custom.DeltaSince: change log is not enabled")
+	}
+
+	adds, removes, err = s.changelog.delta(seq, s.cmp, true)
+	if err != nil {
+		return nil, nil, seq, err
+	}
+	return adds, removes, s.changelog.seq, nil
+}
+
+// Subscribe returns a channel that receives every addition and removal to the set, numbered
+// like the entries of the change log, so that caches or UI layers can react to changes without
+// polling the set. The channel has the provided buffer size, and policy decides what happens when
+// it's full. Subscribing doesn't enable the retention of the changes of [Custom.WithChangeLog].
+//
+// The cancel function stops the subscription and closes the channel. Like the other methods,
+// it must be called by the goroutine that uses the set, and calling it more than once is a no-op.
+// Clones of the set don't inherit its subscriptions. It panics if buffer is < 0.
+func (s *Custom[T]) Subscribe(buffer int, policy Backpressure) (changes <-chan Change[T], cancel func()) {
+	if buffer < 0 {
+		panic("smallset.Custom.Subscribe: buffer must be >= 0")
+	}
+	if s.changelog == nil {
+		s.changelog = &changelog[T]{}
+	}
+	return s.changelog.subscribe(buffer, policy)
+}
+
 // Size returns the number of elements in the set.
 func (s *Custom[T]) Size() int {
+	if s.stored() {
+		return s.store.storage.Len()
+	}
 	return len(s.items)
 }
 
-// Capacity returns the capacity of the underlying slice.
+// Capacity returns the capacity of the underlying slice,
+// or the size of the set if it's backed by a [Storage].
 func (s *Custom[T]) Capacity() int {
+	if s.store != nil {
+		return s.Size()
+	}
 	return cap(s.items)
 }
 
 // IsEmpty returns whether the set has no elements.
 func (s *Custom[T]) IsEmpty() bool {
-	return len(s.items) == 0
+	return s.Size() == 0
 }
 
 // Clear removes all elements from the set.
@@ -99,85 +313,799 @@ func (s *Custom[T]) IsEmpty() bool {
 // and resets the length to 0. The underlying array capacity is preserved
 // to minimize allocations during future insertions.
 func (s *Custom[T]) Clear() {
-	clear(s.items)
-	s.items = s.items[:0]
+	defer s.pin()()
+	if s.changelog != nil {
+		for _, e := range s.items {
+			s.changelog.record(OpRemove, e)
+		}
+	}
+	s.owner.check("Custom")
+	s.store.touch()
+	if s.shared {
+		// the snapshots still read the elements, so they can't be zeroed
+		s.items = make([]T, 0, cap(s.items))
+		s.shared = false
+	} else {
+		clear(s.items)
+		s.items = s.items[:0]
+	}
+	clear(s.tombstones)
+	s.tombstones = s.tombstones[:0]
+	if s.fingerprint != nil {
+		s.fingerprint.sum = 0
+	}
+}
+
+// ClearAndShrink removes all elements from the set like [Custom.Clear], and reallocates the
+// underlying slice if its capacity exceeds the provided one. It's meant for resetting pooled
+// sets to a known memory footprint, after they grew to serve a big request.
+// It panics if the capacity is <= 0.
+func (s *Custom[T]) ClearAndShrink(capacity int) {
+	if capacity <= 0 {
+		panic("smallset.Custom.ClearAndShrink: capacity must be > 0")
+	}
+
+	s.Clear()
+	if cap(s.items) > capacity {
+		s.items = make([]T, 0, capacity)
+	}
+	s.tombstones = nil
+}
+
+// insert e at index i, keeping the optional structures of the set up to date.
+func (s *Custom[T]) insert(i int, e T) {
+	s.own()
+	if s.stored() {
+		s.store.storage.Insert(i, e)
+	} else {
+		s.items = insertGrow(s.growth, s.items, i, e)
+	}
+	s.inserted(e)
+}
+
+// Handoff releases the ownership of the set by the current goroutine, which is only tracked in
+// debug builds (built with -tags smallsetdebug), so that another goroutine can modify it.
+// In debug builds, the first goroutine that modifies a set becomes its owner, and modifications
+// from other goroutines panic. Call Handoff before passing the set to another goroutine,
+// for example over a channel. It's a no-op in normal builds.
+func (s *Custom[T]) Handoff() {
+	s.owner.release()
+}
+
+// own takes ownership of the items before they are modified, by copying them if they are shared
+// with a snapshot. In debug builds, it also panics if the set is owned by another goroutine.
+func (s *Custom[T]) own() {
+	s.owner.check("Custom")
+	s.store.touch()
+	if !s.shared {
+		return
+	}
+	items := make([]T, len(s.items), cap(s.items))
+	copy(items, s.items)
+	s.items = items
+	s.shared = false
+}
+
+// inserted updates the optional structures of the set after the insertion of e.
+func (s *Custom[T]) inserted(e T) {
+	if s.changelog != nil {
+		s.changelog.record(OpAdd, e)
+	}
+	if s.fingerprint != nil {
+		s.fingerprint.add(e)
+	}
+}
+
+// removed updates the optional structures of the set after the removal of e.
+func (s *Custom[T]) removed(e T) {
+	if s.fingerprint != nil {
+		s.fingerprint.remove(e)
+	}
+	if s.changelog != nil {
+		s.changelog.record(OpRemove, e)
+	}
+}
+
+// replace the element at index i with e, keeping the optional structures of the set up to date.
+// It assumes e preserves the sort order.
+func (s *Custom[T]) replace(i int, e T) {
+	s.own()
+	s.removed(s.at(i))
+	s.inserted(e)
+	if s.stored() {
+		s.store.storage.DeleteRange(i, i+1)
+		s.store.storage.Insert(i, e)
+		return
+	}
+	s.items[i] = e
+}
+
+// delete removes the elements in [i, j), keeping the optional structures of the set up to date.
+func (s *Custom[T]) delete(i, j int) {
+	s.own()
+	if s.fingerprint != nil || s.changelog != nil {
+		for k := i; k < j; k++ {
+			s.removed(s.at(k))
+		}
+	}
+	if s.stored() {
+		s.store.storage.DeleteRange(i, j)
+		return
+	}
+	s.items = slices.Delete(s.items, i, j)
+	s.maybeShrink()
+}
+
+// deleteFunc removes the elements for which del returns true in a single pass, keeping the
+// optional structures of the set up to date. The elements are passed to del in ascending order.
+// Returns num removed.
+func (s *Custom[T]) deleteFunc(del func(e T) bool) int {
+	defer s.pin()()
+	s.own()
+	size := len(s.items)
+	s.items = slices.DeleteFunc(s.items, func(e T) bool {
+		if !del(e) {
+			return false
+		}
+		s.removed(e)
+		return true
+	})
+	s.maybeShrink()
+	return size - len(s.items)
+}
+
+// pin makes the elements of a set backed by a [Storage] available in its slice until the returned
+// function is called, for the methods that work on the whole set. See [store].
+// It's a no-op for the other sets, and for the sets that are already pinned.
+func (s *Custom[T]) pin() (unpin func()) {
+	if s.store == nil || s.store.pinned {
+		return noop
+	}
+
+	s.items = s.store.pin()
+	return func() {
+		s.store.unpin(s.items)
+		s.items = nil
+	}
+}
+
+// stored reports whether the elements of the set are in its storage, rather than in its slice.
+func (s *Custom[T]) stored() bool {
+	return s.store != nil && !s.store.pinned
+}
+
+// search returns the index of e, or the index where it would be inserted, and whether it's present.
+func (s *Custom[T]) search(e T) (int, bool) {
+	if s.stored() {
+		return s.store.storage.Search(e, s.store.cmp)
+	}
+	return slices.BinarySearchFunc(s.items, e, s.cmp)
+}
+
+// at returns the element at index i.
+func (s *Custom[T]) at(i int) T {
+	if s.stored() {
+		return s.store.storage.At(i)
+	}
+	return s.items[i]
+}
+
+// appendRange appends to dst the elements with index in [i, j).
+func (s *Custom[T]) appendRange(dst []T, i, j int) []T {
+	if s.stored() {
+		for k := i; k < j; k++ {
+			dst = append(dst, s.store.storage.At(k))
+		}
+		return dst
+	}
+	return append(dst, s.items[i:j]...)
+}
+
+// maybeShrink reallocates the items to twice their length if the shrink policy is enabled
+// and the length is below the shrink fraction of the capacity.
+func (s *Custom[T]) maybeShrink() {
+	if s.shrink == 0 || float64(len(s.items)) >= s.shrink*float64(cap(s.items)) {
+		return
+	}
+
+	capacity := max(2*len(s.items), 1)
+	if capacity >= cap(s.items) {
+		return
+	}
+
+	items := make([]T, len(s.items), capacity)
+	copy(items, s.items)
+	s.items = items
+}
+
+// reset replaces the elements of the set with items, which must be sorted and unique,
+// keeping the optional structures of the set up to date.
+func (s *Custom[T]) reset(items []T) {
+	defer s.pin()()
+	if s.changelog != nil {
+		s.changelog.recordDiff(s.items, items, s.cmp)
+	}
+	s.owner.check("Custom")
+	s.store.touch()
+	if !s.shared {
+		clear(s.items)
+	}
+	s.items = items
+	s.shared = false
+	if s.fingerprint != nil {
+		s.fingerprint = newFingerprint(s.fingerprint.hash, items)
+	}
 }
 
 // Clone returns a clone of the set, that shares the cmp comparator function.
+// It includes the optional structures of the set, which the sets returned by the set operations,
+// like [Custom.Union], never include.
+// The clone of a set backed by a [Storage] has a clone of the storage, if it can be cloned.
 func (s *Custom[T]) Clone() *Custom[T] {
-	return &Custom[T]{
-		items: slices.Clone(s.items),
-		cmp:   s.cmp,
+	clone := &Custom[T]{
+		cmp:         s.cmp,
+		fingerprint: s.fingerprint.clone(),
+		changelog:   s.changelog.clone(),
+		shrink:      s.shrink,
+		growth:      s.growth,
+	}
+
+	if s.stored() {
+		if clone.store = s.store.clone(); clone.store != nil {
+			return clone
+		}
 	}
+
+	defer s.pin()()
+	clone.items = slices.Clone(s.items)
+	return clone
+}
+
+// cloneItems returns a new set with a copy of the elements of the set and its comparator, but none
+// of its optional structures, like the results of the set operations.
+func (s *Custom[T]) cloneItems() *Custom[T] {
+	return &Custom[T]{items: s.Items(), cmp: s.cmp}
+}
+
+// Snapshot returns an immutable view of the current elements of the set in O(1), whose iterators
+// stay consistent while the set keeps changing. See [Snapshot].
+// The set copies its elements before its next modification, so a snapshot costs a single copy
+// if the set is modified afterwards. Like the other methods, it must not be called concurrently
+// with modifications, but the returned snapshot can be used from any goroutine.
+func (s *Custom[T]) Snapshot() *Snapshot[T] {
+	defer s.pin()()
+	if s.store == nil {
+		// the pinned elements of a stored set are already a copy
+		s.shared = true
+	}
+	return &Snapshot[T]{items: s.items[:len(s.items):len(s.items)], cmp: s.cmp}
 }
 
 // Items returns a copy of the internal slice of the set.
 func (s *Custom[T]) Items() []T {
+	if s.stored() {
+		return collectStorage(s.store.storage)
+	}
 	return slices.Clone(s.items)
 }
 
+// Release returns the internal sorted slice of the set without copying it, and leaves the set empty.
+// It's meant for terminal consumers, like encoders and batch writers, that would otherwise copy
+// the elements with [Custom.Items] right before discarding the set. The caller owns the returned slice,
+// and the set allocates a new one on the next insertion.
+//
+// After a call to [Custom.Snapshot], the slice is shared with the snapshot, so Release copies it
+// like [Custom.Items] does, in order for the caller to be free to modify it.
+func (s *Custom[T]) Release() []T {
+	defer s.pin()()
+	s.own()
+	items := s.items
+	if s.changelog != nil {
+		for _, e := range items {
+			s.changelog.record(OpRemove, e)
+		}
+	}
+
+	s.items = nil
+	s.Clear()
+	return items
+}
+
+// Hash returns a deterministic digest of the set, computed by resetting h and writing to it
+// the binary encoding of the elements in ascending order, as produced by the encode function.
+// Each encoding is prefixed with its length, so that the digest is unambiguous.
+//
+// The encode function must append the encoding of e to dst and return the extended slice.
+// Elements that compare equal must have the same encoding, for equal sets to have equal digests.
+// When h doesn't depend on the process (e.g. [fnv.New64a]), digests can be compared
+// across processes and used as cache keys.
+func (s *Custom[T]) Hash(h hash.Hash64, encode func(dst []byte, e T) []byte) uint64 {
+	defer s.pin()()
+	h.Reset()
+	var buf, enc []byte
+	for _, e := range s.items {
+		enc = encode(enc[:0], e)
+		buf = binary.AppendUvarint(buf[:0], uint64(len(enc)))
+		h.Write(buf)
+		h.Write(enc)
+	}
+	return h.Sum64()
+}
+
+// RangeDigest returns a hierarchical digest of the set with the provided number of levels,
+// for identifying which ranges of elements differ from another replica. See [RangeDigest].
+//
+// The encode function must append the encoding of e to dst and return the extended slice.
+// Elements that compare equal must have the same encoding, and all replicas must use the same one.
+// It panics if levels is not in [1, 24] or encode is nil. O(N) complexity.
+func (s *Custom[T]) RangeDigest(levels int, encode func(dst []byte, e T) []byte) *RangeDigest {
+	if levels < 1 || levels > maxDigestLevels {
+		panic("smallset.Custom.RangeDigest: levels must be in [1, 24]")
+	}
+	if encode == nil {
+		panic("smallset.Custom.RangeDigest: encode cannot be nil")
+	}
+
+	defer s.pin()()
+	hash := stableHashFunc(encode)
+	return newRangeDigest(levels, func(yield func(uint64) bool) {
+		for _, e := range s.items {
+			if !yield(hash(e)) {
+				return
+			}
+		}
+	})
+}
+
+// RangeItems returns the elements of the set in the provided ranges of the last level of a
+// [RangeDigest] with the provided number of levels, like the ones returned by [RangeDigest.Diff].
+// The encode function must be the same used for computing the digest.
+// It panics if levels is not in [1, 24] or encode is nil. O(N*log(R)) complexity.
+func (s *Custom[T]) RangeItems(levels int, ranges []int, encode func(dst []byte, e T) []byte) []T {
+	if levels < 1 || levels > maxDigestLevels {
+		panic("smallset.Custom.RangeItems: levels must be in [1, 24]")
+	}
+	if encode == nil {
+		panic("smallset.Custom.RangeItems: encode cannot be nil")
+	}
+
+	defer s.pin()()
+	return rangeItems(s.items, levels, ranges, stableHashFunc(encode))
+}
+
+// Sketch returns the [MinHash] signature of the set with k hashes derived from the seed,
+// for estimating its Jaccard similarity with other sets.
+//
+// The encode function must append the encoding of e to dst and return the extended slice.
+// Elements that compare equal must have the same encoding, and all sets must use the same one.
+// It panics if k is <= 0 or encode is nil. O(N*k) complexity.
+func (s *Custom[T]) Sketch(k int, seed uint64, encode func(dst []byte, e T) []byte) MinHash {
+	if k <= 0 {
+		panic("smallset.Custom.Sketch: k must be > 0")
+	}
+	if encode == nil {
+		panic("smallset.Custom.Sketch: encode cannot be nil")
+	}
+
+	defer s.pin()()
+	hash := stableHashFunc(encode)
+	return newMinHash(k, seed, func(yield func(uint64) bool) {
+		for _, e := range s.items {
+			if !yield(hash(e)) {
+				return
+			}
+		}
+	})
+}
+
+// stableHashFunc returns the hash function used by [Custom.RangeDigest] and [Custom.Sketch],
+// which reuses its buffer.
+func stableHashFunc[T any](encode func(dst []byte, e T) []byte) func(T) uint64 {
+	var buf []byte
+	return func(e T) uint64 {
+		buf = encode(buf[:0], e)
+		return stableHash(buf)
+	}
+}
+
+// Key returns a compact canonical encoding of the set, suitable as a map key, made of
+// the binary encoding of the elements in ascending order, as produced by the encode function.
+// Each encoding is prefixed with its length, so that the key is unambiguous.
+//
+// The encode function must append the encoding of e to dst and return the extended slice.
+// Elements that compare equal must have the same encoding, for equal sets to have equal keys.
+func (s *Custom[T]) Key(encode func(dst []byte, e T) []byte) string {
+	defer s.pin()()
+	var buf, enc []byte
+	for _, e := range s.items {
+		enc = encode(enc[:0], e)
+		buf = binary.AppendUvarint(buf, uint64(len(enc)))
+		buf = append(buf, enc...)
+	}
+	return string(buf)
+}
+
+// Fingerprint returns an order-independent hash of the elements of the set, maintained
+// incrementally on every insertion and removal. It can be used to cheaply check whether
+// a set has changed since a previous call. O(1) complexity.
+// It panics if the set was not configured with [Custom.WithFingerprint].
+func (s *Custom[T]) Fingerprint() uint64 {
+	if s.fingerprint == nil {
+		panic("smallset.Custom.Fingerprint: fingerprint is not enabled")
+	}
+	return s.fingerprint.sum
+}
+
 // Contains returns whether the element is in the set. Operation is O(log(N))
 func (s *Custom[T]) Contains(e T) bool {
-	_, found := slices.BinarySearchFunc(s.items, e, s.cmp)
+	_, found := s.search(e)
+	return found
+}
+
+// ContainsCount returns how many of the probes are in the set, counting repeated probes
+// multiple times. O(P*log(N)) complexity.
+func (s *Custom[T]) ContainsCount(probes ...T) int {
+	count := 0
+	for _, e := range probes {
+		if s.Contains(e) {
+			count++
+		}
+	}
+	return count
+}
+
+// ContainsMany returns whether each of the probes is in the set, in the order of the probes.
+// The probes are sorted and answered in a single merge pass over the set, which is faster than
+// independent calls to [Custom.Contains] for medium batch sizes. O(P*log(P) + N) complexity.
+func (s *Custom[T]) ContainsMany(probes []T) []bool {
+	defer s.pin()()
+	found := make([]bool, len(probes))
+	j := 0
+	for _, p := range sortedIndices(probes, s.cmp) {
+		for j < len(s.items) && s.cmp.less(s.items[j], probes[p]) {
+			j++
+		}
+		found[p] = j < len(s.items) && s.cmp.equal(s.items[j], probes[p])
+	}
 	return found
 }
 
 // At returns the element at index i or panics if out of range.
 func (s *Custom[T]) At(i int) T {
-	if i < 0 || i >= len(s.items) {
+	if i < 0 || i >= s.Size() {
 		panic("smallset.Custom.At: index out of range")
 	}
-	return s.items[i]
+	return s.at(i)
 }
 
 // Find returns the index of an element, or the position where target would appear
 // in the sort order. It also returns a bool saying whether the target is really found in the slice.
 func (s *Custom[T]) Find(e T) (int, bool) {
-	return slices.BinarySearchFunc(s.items, e, s.cmp)
+	return s.search(e)
+}
+
+// FindBy searches the set by key, without building a dummy element to pass to [Custom.Find].
+// The cmp function compares an element with the key, and *must* order the elements like the
+// comparison function of the set. It returns the element with the key, its index or the position
+// where it would appear in the sort order, and whether it's found. O(log(N)) complexity.
+// It panics if cmp is nil.
+//
+//	p, i, found := smallset.FindBy(people, 42, func(p Person, id int) int { return cmp.Compare(p.ID, id) })
+func FindBy[T, K any](s *Custom[T], key K, cmp func(T, K) int) (T, int, bool) {
+	if cmp == nil {
+		panic("smallset.FindBy: cmp cannot be nil")
+	}
+
+	i, found := searchBy(s, key, cmp)
+	if !found {
+		var zero T
+		return zero, i, false
+	}
+	return s.at(i), i, true
+}
+
+// FindMany is the batch version of [Custom.Find]. It returns, in the order of the probes, the index
+// of each probe or the position where it would appear in the sort order, and whether it's found.
+// The probes are sorted and resolved in a single merge pass over the set. O(P*log(P) + N) complexity.
+func (s *Custom[T]) FindMany(probes []T) ([]int, []bool) {
+	defer s.pin()()
+	idxs := make([]int, len(probes))
+	found := make([]bool, len(probes))
+	j := 0
+	for _, p := range sortedIndices(probes, s.cmp) {
+		for j < len(s.items) && s.cmp.less(s.items[j], probes[p]) {
+			j++
+		}
+		idxs[p] = j
+		found[p] = j < len(s.items) && s.cmp.equal(s.items[j], probes[p])
+	}
+	return idxs, found
+}
+
+// ContainsFunc returns whether at least one element of the set satisfies pred.
+// It's meant for queries that can't be expressed with the ordering of the set,
+// and it's O(N) since every element may be checked.
+func (s *Custom[T]) ContainsFunc(pred func(T) bool) bool {
+	defer s.pin()()
+	return slices.ContainsFunc(s.items, pred)
+}
+
+// IndexFunc returns the index of the first element in ascending order that satisfies pred,
+// or -1 if none does. Like [Custom.ContainsFunc], it's O(N).
+func (s *Custom[T]) IndexFunc(pred func(T) bool) int {
+	defer s.pin()()
+	return slices.IndexFunc(s.items, pred)
+}
+
+// IndexRange returns the indices [start, end) of the elements e such that min <= e < max,
+// as the positions where min and max would appear in the sort order.
+// It allows slicing, counting or iterating a range without repeating the binary searches.
+// Panics if max < min.
+func (s *Custom[T]) IndexRange(min, max T) (start, end int) {
+	if s.cmp.less(max, min) {
+		panic("smallset.Custom.IndexRange: invalid range (max < min)")
+	}
+	return s.indexRange(min, max)
+}
+
+// indexRange returns the indices [start, end) of the elements e such that min <= e < max.
+// It assumes min <= max.
+func (s *Custom[T]) indexRange(min, max T) (start, end int) {
+	start, _ = s.search(min)
+	end, _ = s.search(max)
+	return start, end
 }
 
 // Add an element and returns whether is was added (true), or was already present (false).
 func (s *Custom[T]) Add(e T) bool {
-	i, found := slices.BinarySearchFunc(s.items, e, s.cmp)
+	i, found := s.search(e)
 	if found {
 		return false
 	}
 
-	s.items = slices.Insert(s.items, i, e)
+	s.insert(i, e)
 	return true
 }
 
+// AddSorted adds the elements of items, which must be sorted in ascending order, and returns
+// the number of elements added. Unlike adding them one by one, the elements are merged into
+// the set with a single backward pass, without sorting them nor shifting the set once per element.
+// Duplicates in items are allowed. O(N+M) complexity. It panics if items are not sorted.
+func (s *Custom[T]) AddSorted(items []T) int {
+	if !slices.IsSortedFunc(items, s.cmp) {
+		panic("smallset.Custom.AddSorted: items must be sorted")
+	}
+
+	defer s.pin()()
+
+	// collect the new elements, skipping the duplicates in items
+	var added []T
+	i := 0
+	for j, e := range items {
+		if j > 0 && s.cmp.equal(items[j-1], e) {
+			continue
+		}
+		for i < len(s.items) && s.cmp.less(s.items[i], e) {
+			i++
+		}
+		if i == len(s.items) || !s.cmp.equal(s.items[i], e) {
+			added = append(added, e)
+		}
+	}
+
+	if len(added) == 0 {
+		return 0
+	}
+
+	s.own()
+	for _, e := range added {
+		s.inserted(e)
+	}
+
+	// merge from the back, moving every element of the set at most once
+	n := len(s.items)
+	s.items = grow(s.growth, s.items, len(added))[:n+len(added)]
+	w := len(s.items) - 1
+	i = n - 1
+	for j := len(added) - 1; j >= 0; j-- {
+		e := added[j]
+		for i >= 0 && s.cmp.less(e, s.items[i]) {
+			s.items[w] = s.items[i]
+			w--
+			i--
+		}
+		s.items[w] = e
+		w--
+	}
+	return len(added)
+}
+
 // Remove an element if present, and returns whether is was removed (true), or was never present (false).
 func (s *Custom[T]) Remove(e T) bool {
-	i, found := slices.BinarySearchFunc(s.items, e, s.cmp)
+	i, found := s.search(e)
 	if !found {
 		return false
 	}
 
-	s.items = slices.Delete(s.items, i, i+1)
+	s.delete(i, i+1)
+	return true
+}
+
+// ReplaceAt replaces the element at index i with e if it preserves the sort order, meaning e is
+// strictly between the neighbors of index i. It returns whether the element was replaced (true),
+// or would break the sort order (false). It's an O(1) alternative to a Remove followed by an Add.
+// It panics if i is out of range.
+func (s *Custom[T]) ReplaceAt(i int, e T) bool {
+	n := s.Size()
+	if i < 0 || i >= n {
+		panic("smallset.Custom.ReplaceAt: index out of range")
+	}
+
+	if i > 0 && !s.cmp.less(s.at(i-1), e) {
+		return false
+	}
+	if i < n-1 && !s.cmp.less(e, s.at(i+1)) {
+		return false
+	}
+
+	s.replace(i, e)
 	return true
 }
 
+// MarkRemove marks an element for removal, and returns whether it's present in the set (true)
+// or not (false). Marked elements stay in the set until [Custom.Compact] is called, which
+// removes all of them in a single O(N) pass.
+// It allows to remove elements while iterating the set, e.g. with [Custom.Ascend],
+// without collecting them into a temporary slice.
+func (s *Custom[T]) MarkRemove(e T) bool {
+	if _, found := s.search(e); !found {
+		return false
+	}
+	s.tombstones = append(s.tombstones, e)
+	return true
+}
+
+// Compact removes all the elements marked with [Custom.MarkRemove] in a single pass.
+// Returns num removed.
+func (s *Custom[T]) Compact() int {
+	if len(s.tombstones) == 0 {
+		return 0
+	}
+
+	slices.SortFunc(s.tombstones, s.cmp)
+	j := 0
+	removed := s.deleteFunc(func(e T) bool {
+		for j < len(s.tombstones) && s.cmp.less(s.tombstones[j], e) {
+			j++
+		}
+		return j < len(s.tombstones) && s.cmp.equal(s.tombstones[j], e)
+	})
+
+	clear(s.tombstones)
+	s.tombstones = s.tombstones[:0]
+	return removed
+}
+
+// RemoveIndices removes the elements at the provided indices in a single O(N) pass.
+// Indices refer to the positions before any removal, and can be unsorted or repeated.
+// Returns num removed. It panics if any index is out of range.
+func (s *Custom[T]) RemoveIndices(idxs ...int) int {
+	if len(idxs) == 0 {
+		return 0
+	}
+
+	idxs = slices.Clone(idxs)
+	slices.Sort(idxs)
+	idxs = slices.Compact(idxs)
+	if idxs[0] < 0 || idxs[len(idxs)-1] >= s.Size() {
+		panic("smallset.Custom.RemoveIndices: index out of range")
+	}
+
+	i := 0
+	return s.deleteFunc(func(T) bool {
+		del := len(idxs) > 0 && idxs[0] == i
+		if del {
+			idxs = idxs[1:]
+		}
+		i++
+		return del
+	})
+}
+
+// ExtractFunc removes the elements for which pred returns true, and returns them as a new set,
+// in a single pass over the set. It's the "move these elements elsewhere" pattern, without
+// filtering the set and then removing the matches one by one. O(N) complexity.
+// It panics if pred is nil.
+func (s *Custom[T]) ExtractFunc(pred func(T) bool) *Custom[T] {
+	if pred == nil {
+		panic("smallset.Custom.ExtractFunc: pred cannot be nil")
+	}
+
+	var extracted []T
+	s.deleteFunc(func(e T) bool {
+		if !pred(e) {
+			return false
+		}
+		extracted = append(extracted, e)
+		return true
+	})
+	return &Custom[T]{items: extracted, cmp: s.cmp}
+}
+
+// ApplyDiff removes the elements of remove and adds the elements of add in a single merge pass,
+// instead of shifting the elements once per insertion or removal. The lists can be unsorted.
+// An element in both lists is treated as removed and then added again, so it ends up in the set.
+// Returns the number of elements that were added or removed as a result.
+// O(A*log(A) + R*log(R) + N) complexity.
+func (s *Custom[T]) ApplyDiff(add, remove []T) (added, removed int) {
+	if len(add) == 0 && len(remove) == 0 {
+		return 0, 0
+	}
+
+	defer s.pin()()
+	add = slices.CompactFunc(slices.SortedFunc(slices.Values(add), s.cmp), s.cmp.equal)
+	remove = slices.CompactFunc(slices.SortedFunc(slices.Values(remove), s.cmp), s.cmp.equal)
+	items := make([]T, 0, len(s.items)+len(add))
+
+	// i, j, k are the indices of the next element of s.items, add and remove respectively
+	i, j, k := 0, 0, 0
+	for i < len(s.items) || j < len(add) {
+		switch {
+		case j == len(add) || (i < len(s.items) && s.cmp.less(s.items[i], add[j])):
+			// element in s not in add
+			e := s.items[i]
+			i++
+
+			for k < len(remove) && s.cmp.less(remove[k], e) {
+				k++
+			}
+			if k < len(remove) && s.cmp.equal(remove[k], e) {
+				removed++
+				continue
+			}
+			items = append(items, e)
+
+		case i == len(s.items) || s.cmp.less(add[j], s.items[i]):
+			// element in add not in s
+			items = append(items, add[j])
+			added++
+			j++
+
+		default:
+			// element in both
+			items = append(items, s.items[i])
+			i++
+			j++
+		}
+	}
+
+	if added > 0 || removed > 0 {
+		s.reset(items)
+	}
+	return added, removed
+}
+
 // RemoveBefore removes all elements e such that e < max. Returns num removed.
 func (s *Custom[T]) RemoveBefore(max T) int {
-	end, _ := slices.BinarySearchFunc(s.items, max, s.cmp)
+	end, _ := s.search(max)
 	if end == 0 {
 		return 0
 	}
 
-	s.items = slices.Delete(s.items, 0, end)
+	s.delete(0, end)
 	return end
 }
 
 // RemoveFrom removed all elements e such that e >= min. Returns num removed.
 func (s *Custom[T]) RemoveFrom(min T) int {
-	start, _ := slices.BinarySearchFunc(s.items, min, s.cmp)
-	if start == len(s.items) {
+	start, _ := s.search(min)
+	n := s.Size()
+	if start == n {
 		return 0
 	}
 
-	removed := len(s.items) - start
-	s.items = slices.Delete(s.items, start, len(s.items))
+	removed := n - start
+	s.delete(start, n)
 	return removed
 }
 
@@ -187,32 +1115,151 @@ func (s *Custom[T]) RemoveBetween(min, max T) int {
 		panic("smallset.Custom.RemoveBetween: invalid range (max < min)")
 	}
 
-	start, _ := slices.BinarySearchFunc(s.items, min, s.cmp)
-	end, _ := slices.BinarySearchFunc(s.items, max, s.cmp)
+	start, end := s.indexRange(min, max)
 	if start == end {
 		return 0
 	}
 
-	s.items = slices.Delete(s.items, start, end)
+	s.delete(start, end)
+	return end - start
+}
+
+// RemoveBy removes the element with the key if present, without building a dummy element to pass
+// to [Custom.Remove], and returns whether it was removed (true), or was never present (false).
+// The cmp function compares an element with the key, and *must* order the elements like the
+// comparison function of the set. It panics if cmp is nil.
+func RemoveBy[T, K any](s *Custom[T], key K, cmp func(T, K) int) bool {
+	if cmp == nil {
+		panic("smallset.RemoveBy: cmp cannot be nil")
+	}
+
+	i, found := searchBy(s, key, cmp)
+	if !found {
+		return false
+	}
+
+	s.delete(i, i+1)
+	return true
+}
+
+// RemoveRangeBy removes all elements e whose key is such that min <= key < max, like
+// [Custom.RemoveBetween] but with keys instead of dummy elements. Returns num removed.
+// The cmp function compares an element with a key, and *must* order the elements like the
+// comparison function of the set. For example, the elements whose name starts with "user:"
+// are removed by RemoveRangeBy(s, "user:", "user;", byName), since ';' follows ':'.
+// It panics if cmp is nil.
+func RemoveRangeBy[T, K any](s *Custom[T], min, max K, cmp func(T, K) int) int {
+	if cmp == nil {
+		panic("smallset.RemoveRangeBy: cmp cannot be nil")
+	}
+
+	start, _ := searchBy(s, min, cmp)
+	end, _ := searchBy(s, max, cmp)
+	if start >= end {
+		return 0
+	}
+
+	s.delete(start, end)
 	return end - start
 }
 
+// KeepBetween removes all elements e outside of min <= e < max, the inverse of [Custom.RemoveBetween],
+// with at most two range deletions. It's meant for clamping a retention window on both ends.
+// Returns num removed. Panics if max < min.
+func (s *Custom[T]) KeepBetween(min, max T) int {
+	if s.cmp.less(max, min) {
+		panic("smallset.Custom.KeepBetween: invalid range (max < min)")
+	}
+
+	start, end := s.indexRange(min, max)
+	n := s.Size()
+	removed := n - (end - start)
+
+	// delete the tail first, so that the indices of the head don't change
+	if end < n {
+		s.delete(end, n)
+	}
+	if start > 0 {
+		s.delete(0, start)
+	}
+	return removed
+}
+
+// TrimPercentile removes the lowest fraction low and the highest fraction high of the elements,
+// rounded down, with at most two range deletions. Returns num removed.
+// It's meant for removing outliers before computing robust statistics.
+// It panics if low or high are not in [0, 1], or if low + high > 1.
+func (s *Custom[T]) TrimPercentile(low, high float64) int {
+	if !(low >= 0 && low <= 1) || !(high >= 0 && high <= 1) || low+high > 1 {
+		panic("smallset.Custom.TrimPercentile: invalid fractions")
+	}
+
+	n := s.Size()
+	lo := int(low * float64(n))
+	hi := int(high * float64(n))
+
+	// delete the highest elements first, so that they are not shifted
+	if hi > 0 {
+		s.delete(n-hi, n)
+	}
+	if lo > 0 {
+		s.delete(0, lo)
+	}
+	return lo + hi
+}
+
 // Min returns the smallest element in the set.
 // It panics if the set is empty.
 func (s *Custom[T]) Min() T {
 	if s.IsEmpty() {
 		panic("smallset.Custom.Min: set is empty")
 	}
-	return s.items[0]
+	return s.at(0)
 }
 
 // Max returns the biggest element in the sets.
 // It panics if the set is empty.
 func (s *Custom[T]) Max() T {
 	if s.IsEmpty() {
-		panic("smallset.Custom.Max: set is empty")
+		panic("smallset.Custom.Max: set is empty")
+	}
+	return s.at(s.Size() - 1)
+}
+
+// Summary returns the count, min, max and median of the set in a single call. O(1) complexity.
+// Use [Summarize] for the sum and mean of numeric sets.
+func (s *Custom[T]) Summary() Summary[T] {
+	defer s.pin()()
+	return summarize(s.items)
+}
+
+// PopMin removes and returns the smallest element in the set. O(N) complexity.
+// It panics if the set is empty.
+func (s *Custom[T]) PopMin() T {
+	if s.IsEmpty() {
+		panic("smallset.Custom.PopMin: set is empty")
+	}
+	min := s.at(0)
+	s.delete(0, 1)
+	return min
+}
+
+// PopMax removes and returns the biggest element in the set. O(1) complexity.
+// It panics if the set is empty.
+func (s *Custom[T]) PopMax() T {
+	if s.IsEmpty() {
+		panic("smallset.Custom.PopMax: set is empty")
 	}
-	return s.items[len(s.items)-1]
+	last := s.Size() - 1
+	max := s.at(last)
+	s.delete(last, last+1)
+	return max
+}
+
+// Queue returns a [PriorityQueue] view over the set.
+// The view shares the set, so changes made through one are visible in the other.
+func (s *Custom[T]) Queue() *PriorityQueue[T] {
+	return &PriorityQueue[T]{set: s}
 }
 
 // MinK returns the k smallest elements in s, sorted in ascending order. O(k) complexity.
@@ -222,7 +1269,7 @@ func (s *Custom[T]) MinK(k int) []T {
 		panic(fmt.Sprintf("smallset.Custom.MinK: k must be positive: %d", k))
 	}
 	k = min(k, s.Size())
-	return slices.Clone(s.items[:k])
+	return s.appendRange(make([]T, 0, k), 0, k)
 }
 
 // MaxK returns the k biggest elements in s, sorted in ascending order. O(k) complexity.
@@ -232,16 +1279,86 @@ func (s *Custom[T]) MaxK(k int) []T {
 		panic(fmt.Sprintf("smallset.Custom.MaxK: k must be positive: %d", k))
 	}
 	k = min(k, s.Size())
-	return slices.Clone(s.items[len(s.items)-k:])
+	n := s.Size()
+	return s.appendRange(make([]T, 0, k), n-k, n)
+}
+
+// Page returns a copy of up to limit elements starting at index offset, in ascending order,
+// together with the total number of elements in the set. Offsets past the end return no elements.
+// It panics if offset or limit are negative.
+func (s *Custom[T]) Page(offset, limit int) ([]T, int) {
+	if offset < 0 || limit < 0 {
+		panic(fmt.Sprintf("smallset.Custom.Page: offset and limit must be positive: %d, %d", offset, limit))
+	}
+
+	n := s.Size()
+	start := min(offset, n)
+	end := start + min(limit, n-start)
+	return s.appendRange(make([]T, 0, end-start), start, end), n
+}
+
+// MinKAppend appends the k smallest elements in s to dst in ascending order,
+// and returns the extended slice. Unlike [Custom.MinK], it doesn't allocate if dst has enough capacity.
+// It panics if k is negative. If k is bigger than the set size, it appends all the items.
+func (s *Custom[T]) MinKAppend(dst []T, k int) []T {
+	if k < 0 {
+		panic(fmt.Sprintf("smallset.Custom.MinKAppend: k must be positive: %d", k))
+	}
+	k = min(k, s.Size())
+	return s.appendRange(dst, 0, k)
+}
+
+// MaxKAppend appends the k biggest elements in s to dst in ascending order,
+// and returns the extended slice. Unlike [Custom.MaxK], it doesn't allocate if dst has enough capacity.
+// It panics if k is negative. If k is bigger than the set size, it appends all the items.
+func (s *Custom[T]) MaxKAppend(dst []T, k int) []T {
+	if k < 0 {
+		panic(fmt.Sprintf("smallset.Custom.MaxKAppend: k must be positive: %d", k))
+	}
+	k = min(k, s.Size())
+	n := s.Size()
+	return s.appendRange(dst, n-k, n)
+}
+
+// SampleWeighted returns k elements sampled without replacement, with probabilities proportional
+// to the provided weight function. Elements with a non-positive weight are never sampled.
+// The sampled elements are returned in ascending order. O(N*log(N)) complexity.
+// It panics if k is negative. If k is bigger than the number of elements with a positive weight,
+// it returns all of them. If rng is nil, the global random source is used.
+func (s *Custom[T]) SampleWeighted(k int, weight func(T) float64, rng *rand.Rand) []T {
+	if k < 0 {
+		panic(fmt.Sprintf("smallset.Custom.SampleWeighted: k must be positive: %d", k))
+	}
+
+	defer s.pin()()
+	return sampleWeighted(s.items, k, weight, rng)
+}
+
+// SortInterface returns a read-only [sort.Interface] view over the set, for APIs
+// that take a sort.Interface or need index-based access. Len and Less reflect
+// the current state of the set, while Swap panics unless i == j.
+func (s *Custom[T]) SortInterface() sort.Interface {
+	return sorter[T]{set: s, less: s.cmp.less}
+}
+
+// Cursor returns a [Cursor] over the set, positioned before its smallest element.
+func (s *Custom[T]) Cursor() *Cursor[T] {
+	return &Cursor[T]{set: s}
 }
 
 // Ascend returns an iterator over the set in ascending order.
 func (s *Custom[T]) Ascend() iter.Seq2[int, T] {
+	if s.stored() {
+		return s.store.storage.All()
+	}
 	return slices.All(s.items)
 }
 
 // Descend returns an iterator over the set in descending order.
 func (s *Custom[T]) Descend() iter.Seq2[int, T] {
+	if s.stored() {
+		return backward(s.store.storage)
+	}
 	return slices.Backward(s.items)
 }
 
@@ -252,11 +1369,11 @@ func (s *Custom[T]) BetweenAsc(min, max T) iter.Seq2[int, T] {
 	if s.cmp.less(max, min) {
 		panic("smallset.Custom.BetweenAsc: invalid range (max < min)")
 	}
-	start, _ := slices.BinarySearchFunc(s.items, min, s.cmp)
+	start, _ := s.search(min)
 
 	return func(yield func(int, T) bool) {
-		for i := start; i < len(s.items); i++ {
-			v := s.items[i]
+		for i := start; i < s.Size(); i++ {
+			v := s.at(i)
 			if !s.cmp.less(v, max) {
 				return
 			}
@@ -275,14 +1392,14 @@ func (s *Custom[T]) BetweenDesc(max, min T) iter.Seq2[int, T] {
 		panic("smallset.Custom.BetweenDesc: invalid range (max < min)")
 	}
 
-	end, found := slices.BinarySearchFunc(s.items, max, s.cmp)
+	end, found := s.search(max)
 	if !found && end > 0 {
 		end--
 	}
 
 	return func(yield func(int, T) bool) {
 		for i := end; i >= 0; i-- {
-			v := s.items[i]
+			v := s.at(i)
 			if !s.cmp.less(min, v) {
 				return
 			}
@@ -293,11 +1410,79 @@ func (s *Custom[T]) BetweenDesc(max, min T) iter.Seq2[int, T] {
 	}
 }
 
+// BetweenValues iterates the values from min (inclusive) to max (exclusive) in ascending order.
+// It's like [Custom.BetweenAsc] without the index, to compose with [iter.Seq] based pipelines.
+// Panics if max < min.
+func (s *Custom[T]) BetweenValues(min, max T) iter.Seq[T] {
+	if s.cmp.less(max, min) {
+		panic("smallset.Custom.BetweenValues: invalid range (max < min)")
+	}
+	start, _ := s.search(min)
+
+	return func(yield func(T) bool) {
+		for i := start; i < s.Size(); i++ {
+			v := s.at(i)
+			if !s.cmp.less(v, max) {
+				return
+			}
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Pairs iterates over each pair of adjacent elements in ascending order, yielding (s[0], s[1]),
+// (s[1], s[2]) and so on. It's the natural primitive for computing the gaps between consecutive
+// elements, like the deltas between timestamps. Sets with less than two elements yield nothing.
+func (s *Custom[T]) Pairs() iter.Seq2[T, T] {
+	return func(yield func(T, T) bool) {
+		for i := 1; i < s.Size(); i++ {
+			if !yield(s.at(i-1), s.at(i)) {
+				return
+			}
+		}
+	}
+}
+
 // IsEqual returns whether the two sets have the same elements.
 func (s *Custom[T]) IsEqual(other *Custom[T]) bool {
+	defer s.pin()()
+	defer other.pin()()
 	return slices.EqualFunc(s.items, other.items, s.cmp.equal)
 }
 
+// EqualOrderedCustom returns whether an [Ordered] and a [Custom] set have the same elements,
+// according to the comparison function of c. It's meant for tests and for migrating from one type
+// to the other. It works with any comparison function, even one that sorts c in a different order.
+// O(N*log(N)) complexity.
+func EqualOrderedCustom[T cmp.Ordered](o *Ordered[T], c *Custom[T]) bool {
+	if o.Size() != c.Size() {
+		return false
+	}
+	for _, e := range o.Ascend() {
+		if !c.Contains(e) {
+			return false
+		}
+	}
+	return true
+}
+
+// EqualFuncCustom returns whether two sets of possibly different element types have the same size
+// and eq returns true for each pair of elements at the same position, like a set of IDs and a set
+// of the records with those IDs. The elements are compared in the order of each set, so eq *must*
+// match elements that have the same position in both orders. O(N) complexity.
+// It panics if eq is nil.
+func EqualFuncCustom[A, B any](a *Custom[A], b *Custom[B], eq func(A, B) bool) bool {
+	if eq == nil {
+		panic("smallset.EqualFuncCustom: eq cannot be nil")
+	}
+
+	defer a.pin()()
+	defer b.pin()()
+	return slices.EqualFunc(a.items, b.items, eq)
+}
+
 // Intersect returns the intersection of two sets, returning a NewCustom set
 // containing only the common elements. O(N+M) complexity.
 // s1 and s2 must use the same (or equivalent) comparison functions.
@@ -307,6 +1492,8 @@ func (s *Custom[T]) Intersect(other *Custom[T]) *Custom[T] {
 		return NewCustom[T](s.cmp, defaultCapacity)
 	}
 
+	defer s.pin()()
+	defer other.pin()()
 	inter := NewCustom[T](s.cmp, size)
 
 	i := 0
@@ -333,6 +1520,34 @@ func (s *Custom[T]) Intersect(other *Custom[T]) *Custom[T] {
 	return inter
 }
 
+// IntersectCount returns the number of elements in common between the two sets,
+// without allocating their intersection. O(N+M) complexity.
+// s1 and s2 must use the same (or equivalent) comparison functions.
+func (s *Custom[T]) IntersectCount(other *Custom[T]) int {
+	defer s.pin()()
+	defer other.pin()()
+	count := 0
+	i := 0
+	j := 0
+
+	for i < s.Size() && j < other.Size() {
+		s_i := s.items[i]
+		o_j := other.items[j]
+
+		if s.cmp.less(s_i, o_j) {
+			i++
+		} else if s.cmp.less(o_j, s_i) {
+			j++
+		} else {
+			count++
+			i++
+			j++
+		}
+	}
+
+	return count
+}
+
 // Difference returns the difference between this set and other. The returned set will contain
 // all elements of this set that are not elements of other. O(N+M) complexity.
 // s1 and s2 must use the same (or equivalent) comparison functions.
@@ -341,9 +1556,11 @@ func (s *Custom[T]) Difference(other *Custom[T]) *Custom[T] {
 		return NewCustom[T](s.cmp, defaultCapacity)
 	}
 	if other.IsEmpty() {
-		return s.Clone()
+		return s.cloneItems()
 	}
 
+	defer s.pin()()
+	defer other.pin()()
 	diff := NewCustom[T](s.cmp, s.Size())
 
 	i := 0
@@ -371,17 +1588,57 @@ func (s *Custom[T]) Difference(other *Custom[T]) *Custom[T] {
 	return diff
 }
 
+// Complement returns the elements of universe that are not elements of this set, which is
+// universe.Difference(s) without having to remember the order of the operands, for queries like
+// "which shards are not assigned". Elements of this set outside of universe are ignored.
+// O(N+M) complexity.
+// s and universe must use the same (or equivalent) comparison functions.
+func (s *Custom[T]) Complement(universe *Custom[T]) *Custom[T] {
+	return universe.Difference(s)
+}
+
+// DifferenceCount returns the number of elements of this set that are not elements of other,
+// without allocating their difference. O(N+M) complexity.
+// s1 and s2 must use the same (or equivalent) comparison functions.
+func (s *Custom[T]) DifferenceCount(other *Custom[T]) int {
+	return s.Size() - s.IntersectCount(other)
+}
+
+// DifferenceSeq returns a new set with the elements of this set that are not produced by seq,
+// which can be in any order. The sequence is streamed rather than materialized, which makes it
+// suitable for subtracting a large lazily-produced sequence from a small set.
+// O(M*log(N) + N) complexity.
+func (s *Custom[T]) DifferenceSeq(seq iter.Seq[T]) *Custom[T] {
+	defer s.pin()()
+	removed := make([]bool, len(s.items))
+	for e := range seq {
+		if i, found := s.search(e); found {
+			removed[i] = true
+		}
+	}
+
+	diff := NewCustom(s.cmp, max(s.Size(), 1))
+	for i, e := range s.items {
+		if !removed[i] {
+			diff.items = append(diff.items, e)
+		}
+	}
+	return diff
+}
+
 // SymmetricDifference returns a NewCustom set with all elements which are
 // in either this set or the other set but not in both. O(N+M) complexity.
 // s1 and s2 must use the same (or equivalent) comparison functions.
 func (s *Custom[T]) SymmetricDifference(other *Custom[T]) *Custom[T] {
 	if s.IsEmpty() {
-		return other.Clone()
+		return other.cloneItems()
 	}
 	if other.IsEmpty() {
-		return s.Clone()
+		return s.cloneItems()
 	}
 
+	defer s.pin()()
+	defer other.pin()()
 	sdiff := NewCustom[T](s.cmp, s.Size()+other.Size())
 
 	i := 0
@@ -415,12 +1672,14 @@ func (s *Custom[T]) SymmetricDifference(other *Custom[T]) *Custom[T] {
 // s1 and s2 must use the same (or equivalent) comparison functions.
 func (s *Custom[T]) Union(other *Custom[T]) *Custom[T] {
 	if s.IsEmpty() {
-		return other.Clone()
+		return other.cloneItems()
 	}
 	if other.IsEmpty() {
-		return s.Clone()
+		return s.cloneItems()
 	}
 
+	defer s.pin()()
+	defer other.pin()()
 	union := NewCustom[T](s.cmp, s.Size()+other.Size())
 
 	i := 0
@@ -451,6 +1710,70 @@ func (s *Custom[T]) Union(other *Custom[T]) *Custom[T] {
 	return union
 }
 
+// UnionFunc is like [Custom.Union], but elements in both sets are combined by calling
+// resolve(a, b), with a from s and b from other, instead of keeping the one of s.
+// It allows policies like newest-wins or merging fields of elements that compare equal but
+// carry different payloads. The result of resolve *must* compare equal to a and b.
+// To error-out on conflicts, record the error in the closure of resolve.
+// O(N+M) complexity. It panics if resolve is nil.
+func (s *Custom[T]) UnionFunc(other *Custom[T], resolve func(a, b T) T) *Custom[T] {
+	if resolve == nil {
+		panic("smallset.Custom.UnionFunc: resolve cannot be nil")
+	}
+
+	defer s.pin()()
+	defer other.pin()()
+	union := NewCustom[T](s.cmp, max(s.Size()+other.Size(), 1))
+
+	i := 0
+	j := 0
+
+	for i < s.Size() && j < other.Size() {
+		s_i := s.items[i]
+		o_j := other.items[j]
+
+		if s.cmp.less(s_i, o_j) {
+			// element in s not in other
+			union.items = append(union.items, s_i)
+			i++
+		} else if s.cmp.less(o_j, s_i) {
+			// element in other not in s
+			union.items = append(union.items, o_j)
+			j++
+		} else {
+			// element in both
+			union.items = append(union.items, resolve(s_i, o_j))
+			i++
+			j++
+		}
+	}
+
+	union.items = append(union.items, s.items[i:]...)
+	union.items = append(union.items, other.items[j:]...)
+	return union
+}
+
+// UnionCount returns the number of elements in either set, without allocating their union.
+// It's useful to pre-size the result of a union. O(N+M) complexity.
+// s1 and s2 must use the same (or equivalent) comparison functions.
+func (s *Custom[T]) UnionCount(other *Custom[T]) int {
+	return s.Size() + other.Size() - s.IntersectCount(other)
+}
+
+// UnionSeq returns a new set with the elements of this set and the ones produced by seq,
+// which can be in any order. The sequence is streamed, and only the elements that are not
+// already in the set are collected and sorted. O(M*log(N) + K*log(K) + N) complexity,
+// where K is the number of collected elements.
+func (s *Custom[T]) UnionSeq(seq iter.Seq[T]) *Custom[T] {
+	var extra []T
+	for e := range seq {
+		if _, found := s.search(e); !found {
+			extra = append(extra, e)
+		}
+	}
+	return s.Union(CustomFrom(s.cmp, extra...))
+}
+
 // Partition returns three NewCustom sets:
 // - d12: elements in s1 not in s2
 // - inter: elements in both sets
@@ -460,12 +1783,14 @@ func (s *Custom[T]) Union(other *Custom[T]) *Custom[T] {
 // s1 and s2 must use the same (or equivalent) comparison functions.
 func (s1 *Custom[T]) Partition(s2 *Custom[T]) (d12, inter, d21 *Custom[T]) {
 	if s1.IsEmpty() {
-		return NewCustom[T](s1.cmp, defaultCapacity), NewCustom[T](s1.cmp, defaultCapacity), s2.Clone()
+		return NewCustom[T](s1.cmp, defaultCapacity), NewCustom[T](s1.cmp, defaultCapacity), s2.cloneItems()
 	}
 	if s2.IsEmpty() {
-		return s1.Clone(), NewCustom[T](s1.cmp, defaultCapacity), NewCustom[T](s1.cmp, defaultCapacity)
+		return s1.cloneItems(), NewCustom[T](s1.cmp, defaultCapacity), NewCustom[T](s1.cmp, defaultCapacity)
 	}
 
+	defer s1.pin()()
+	defer s2.pin()()
 	d12 = NewCustom[T](s1.cmp, s1.Size())
 	inter = NewCustom[T](s1.cmp, min(s1.Size(), s2.Size()))
 	d21 = NewCustom[T](s1.cmp, s2.Size())
@@ -501,7 +1826,8 @@ func (s1 *Custom[T]) Partition(s2 *Custom[T]) (d12, inter, d21 *Custom[T]) {
 // MergeCustom efficiently combines multiple [Custom] sets into a single new set
 // with the specified comparison function cmp.
 // This is significantly more efficient than chaining s1.Union(s2).Union(s3)...
-// as it performs only a single sort and compact operation on the combined data.
+// as it performs a k-way merge that exploits the sortedness of the sets, which is
+// O(N*log(K)) where N is the total number of elements and K the number of sets.
 func MergeCustom[T any](compare func(a, b T) int, sets ...*Custom[T]) *Custom[T] {
 	if compare == nil {
 		panic("smallset.MergeCustom: cmp cannot be nil")
@@ -511,7 +1837,7 @@ func MergeCustom[T any](compare func(a, b T) int, sets ...*Custom[T]) *Custom[T]
 	}
 	if len(sets) == 1 {
 		return &Custom[T]{
-			items: slices.Clone(sets[0].items),
+			items: sets[0].Items(),
 			cmp:   compare,
 		}
 	}
@@ -525,20 +1851,77 @@ func MergeCustom[T any](compare func(a, b T) int, sets ...*Custom[T]) *Custom[T]
 		return NewCustom[T](compare, defaultCapacity)
 	}
 
-	cmp := compareFunc[T](compare)
-	combined := make([]T, 0, size)
-	for _, s := range sets {
-		combined = append(combined, s.items...)
+	defer pinAllCustom(sets)()
+	lists := make([][]T, len(sets))
+	for i, s := range sets {
+		lists[i] = s.items
 	}
-
-	slices.SortFunc(combined, compare)
-	combined = slices.CompactFunc(combined, cmp.equal)
 	return &Custom[T]{
-		items: combined,
+		items: mergeK(lists, compare),
 		cmp:   compare,
 	}
 }
 
+// MergeResolve is like [MergeCustom], but elements present in more than one set are combined
+// with resolve, folding them in the order of the sets: for an element in sets 0, 2 and 3,
+// the result is resolve(resolve(e0, e2), e3). The result of resolve *must* compare equal to
+// its arguments. O(N*K) complexity, where N is the total number of elements and K the number of sets.
+// It panics if cmp or resolve are nil.
+//
+// The 'cmp' function *must* be the same as the comparison functions of all sets.
+func MergeResolve[T any](compare func(a, b T) int, resolve func(a, b T) T, sets ...*Custom[T]) *Custom[T] {
+	if compare == nil {
+		panic("smallset.MergeResolve: cmp cannot be nil")
+	}
+	if resolve == nil {
+		panic("smallset.MergeResolve: resolve cannot be nil")
+	}
+
+	size := 0
+	for _, s := range sets {
+		size += s.Size()
+	}
+
+	defer pinAllCustom(sets)()
+	cmp := compareFunc[T](compare)
+	merged := NewCustom[T](compare, max(size, defaultCapacity))
+
+	// next[k] is the index of the next element of sets[k] to be merged
+	next := make([]int, len(sets))
+	for {
+		var min T
+		found := false
+		for k, set := range sets {
+			if next[k] == set.Size() {
+				continue
+			}
+			if e := set.items[next[k]]; !found || cmp.less(e, min) {
+				min, found = e, true
+			}
+		}
+
+		if !found {
+			return merged
+		}
+
+		var acc T
+		first := true
+		for k, set := range sets {
+			if next[k] == set.Size() || !cmp.equal(set.items[next[k]], min) {
+				continue
+			}
+
+			if first {
+				acc, first = set.items[next[k]], false
+			} else {
+				acc = resolve(acc, set.items[next[k]])
+			}
+			next[k]++
+		}
+		merged.items = append(merged.items, acc)
+	}
+}
+
 // IntersectCustom efficiently finds the common elements present in *all* provided [Custom] sets.
 // The 'cmp' function defines the ordering for the resulting set, and *must* be the same as the
 // comparison functions of all sets.
@@ -552,7 +1935,10 @@ func IntersectCustom[T any](compare func(a, b T) int, sets ...*Custom[T]) *Custo
 		return NewCustom[T](compare, defaultCapacity)
 	}
 	if len(sets) == 1 {
-		return sets[0].Clone()
+		return &Custom[T]{
+			items: sets[0].Items(),
+			cmp:   compare,
+		}
 	}
 
 	// sort the sets from smallest to biggest
@@ -560,7 +1946,8 @@ func IntersectCustom[T any](compare func(a, b T) int, sets ...*Custom[T]) *Custo
 		return cmp.Compare(s1.Size(), s2.Size())
 	})
 
-	inter := sets[0].Clone()
+	defer pinAllCustom(sets)()
+	inter := &Custom[T]{items: slices.Clone(sets[0].items), cmp: sets[0].cmp}
 	if inter.IsEmpty() {
 		return inter
 	}
@@ -599,3 +1986,261 @@ func IntersectCustom[T any](compare func(a, b T) int, sets ...*Custom[T]) *Custo
 	}
 	return inter
 }
+
+// PowerSetCustom returns an iterator that lazily yields every subset of s, including the
+// empty set and s itself. Each subset is a new set that shares the cmp comparator function.
+// The 2^N subsets are yielded in the order given by the binary representation of their index,
+// where the i-th bit selects the i-th element.
+// It panics if the set has more than 62 elements.
+func PowerSetCustom[T any](s *Custom[T]) iter.Seq[*Custom[T]] {
+	if s.Size() > 62 {
+		panic(fmt.Sprintf("smallset.PowerSetCustom: set is too big: %d", s.Size()))
+	}
+
+	return func(yield func(*Custom[T]) bool) {
+		defer s.pin()()
+		n := s.Size()
+		for mask := uint64(0); mask < 1<<n; mask++ {
+			subset := &Custom[T]{items: make([]T, 0, bits.OnesCount64(mask)), cmp: s.cmp}
+			for i := range n {
+				if mask&(1<<i) != 0 {
+					subset.items = append(subset.items, s.items[i])
+				}
+			}
+
+			if !yield(subset) {
+				return
+			}
+		}
+	}
+}
+
+// MergeJoinCustom returns an iterator over the union of a and b in ascending order,
+// reporting for each element whether it's in a, in b, or in both.
+// It allows implementing custom reconciliation logic in a single pass, without
+// calling [Custom.Partition] and walking the three resulting sets. O(N+M) complexity.
+//
+// a and b must use the same (or equivalent) comparison functions.
+func MergeJoinCustom[T any](a, b *Custom[T]) iter.Seq[Joined[T]] {
+	return func(yield func(Joined[T]) bool) {
+		defer a.pin()()
+		defer b.pin()()
+		i := 0
+		j := 0
+
+		for i < a.Size() && j < b.Size() {
+			a_i := a.items[i]
+			b_j := b.items[j]
+
+			var join Joined[T]
+			if a.cmp.less(a_i, b_j) {
+				// element in a not in b
+				join = Joined[T]{Value: a_i, InA: true}
+				i++
+			} else if a.cmp.less(b_j, a_i) {
+				// element in b not in a
+				join = Joined[T]{Value: b_j, InB: true}
+				j++
+			} else {
+				// element in both
+				join = Joined[T]{Value: a_i, InA: true, InB: true}
+				i++
+				j++
+			}
+
+			if !yield(join) {
+				return
+			}
+		}
+
+		for ; i < a.Size(); i++ {
+			if !yield(Joined[T]{Value: a.items[i], InA: true}) {
+				return
+			}
+		}
+		for ; j < b.Size(); j++ {
+			if !yield(Joined[T]{Value: b.items[j], InB: true}) {
+				return
+			}
+		}
+	}
+}
+
+// AreDisjointCustom returns whether no element appears in more than one of the provided sets.
+// It performs a k-way merge of the sets, returning as soon as a shared element is found.
+// O(N*k) complexity, where N is the total number of elements and k the number of sets.
+//
+// The 'cmp' function *must* be the same as the comparison functions of all sets.
+func AreDisjointCustom[T any](compare func(a, b T) int, sets ...*Custom[T]) bool {
+	if compare == nil {
+		panic("smallset.AreDisjointCustom: cmp cannot be nil")
+	}
+	if len(sets) < 2 {
+		return true
+	}
+
+	defer pinAllCustom(sets)()
+	cmp := compareFunc[T](compare)
+
+	// next[k] is the index of the next element of sets[k] to be merged
+	next := make([]int, len(sets))
+	for {
+		// m is the index of the set with the smallest next element, or -1 if all are exhausted
+		m := -1
+		for k, set := range sets {
+			if next[k] == set.Size() {
+				continue
+			}
+			if m == -1 {
+				m = k
+				continue
+			}
+
+			e := set.items[next[k]]
+			min := sets[m].items[next[m]]
+			if cmp.equal(e, min) {
+				return false
+			}
+			if cmp.less(e, min) {
+				m = k
+			}
+		}
+
+		if m == -1 {
+			return true
+		}
+		next[m]++
+	}
+}
+
+// MultiPartitionCustom returns an iterator over the union of the sets in ascending order, yielding
+// each element with a bitmask of the sets that contain it, where bit k is set if sets[k] does.
+// It generalizes [Custom.Partition] to any number of sets with a single k-way merge,
+// instead of a partition per pair of sets. O(N*k) complexity, where N is the total number
+// of elements and k the number of sets. It panics if cmp is nil or there are more than 64 sets.
+//
+// The 'cmp' function *must* be the same as the comparison functions of all sets.
+func MultiPartitionCustom[T any](compare func(a, b T) int, sets ...*Custom[T]) iter.Seq2[T, uint64] {
+	if compare == nil {
+		panic("smallset.MultiPartitionCustom: cmp cannot be nil")
+	}
+	if len(sets) > 64 {
+		panic("smallset.MultiPartitionCustom: more than 64 sets")
+	}
+
+	cmp := compareFunc[T](compare)
+	return func(yield func(T, uint64) bool) {
+		defer pinAllCustom(sets)()
+		// next[k] is the index of the next element of sets[k] to be merged
+		next := make([]int, len(sets))
+		for {
+			// mask has the bits of the sets whose next element is the smallest one
+			var min T
+			var mask uint64
+			for k, set := range sets {
+				if next[k] == set.Size() {
+					continue
+				}
+
+				e := set.items[next[k]]
+				switch {
+				case mask == 0 || cmp.less(e, min):
+					min, mask = e, 1<<k
+				case cmp.equal(e, min):
+					mask |= 1 << k
+				}
+			}
+
+			if mask == 0 {
+				return
+			}
+			if !yield(min, mask) {
+				return
+			}
+
+			for k := range sets {
+				if mask&(1<<k) != 0 {
+					next[k]++
+				}
+			}
+		}
+	}
+}
+
+// CountsCustom returns an iterator over the union of the sets in ascending order, yielding
+// each element with the number of sets that contain it, for quorum or voting logic.
+// Unlike [MultiPartitionCustom], it accepts any number of sets.
+// O(N*k) complexity, where N is the total number of elements and k the number of sets.
+// It panics if cmp is nil.
+//
+// The 'cmp' function *must* be the same as the comparison functions of all sets.
+func CountsCustom[T any](compare func(a, b T) int, sets ...*Custom[T]) iter.Seq2[T, int] {
+	if compare == nil {
+		panic("smallset.CountsCustom: cmp cannot be nil")
+	}
+
+	cmp := compareFunc[T](compare)
+	return func(yield func(T, int) bool) {
+		defer pinAllCustom(sets)()
+		// next[k] is the index of the next element of sets[k] to be merged
+		next := make([]int, len(sets))
+		for {
+			var min T
+			found := false
+			for k, set := range sets {
+				if next[k] == set.Size() {
+					continue
+				}
+				if e := set.items[next[k]]; !found || cmp.less(e, min) {
+					min, found = e, true
+				}
+			}
+
+			if !found {
+				return
+			}
+
+			count := 0
+			for k, set := range sets {
+				if next[k] < set.Size() && cmp.equal(set.items[next[k]], min) {
+					next[k]++
+					count++
+				}
+			}
+
+			if !yield(min, count) {
+				return
+			}
+		}
+	}
+}
+
+// pinAllCustom pins all the sets, see [Custom.pin].
+func pinAllCustom[T any](sets []*Custom[T]) (unpin func()) {
+	var unpins []func()
+	for _, s := range sets {
+		if s.store != nil && !s.store.pinned {
+			unpins = append(unpins, s.pin())
+		}
+	}
+
+	if len(unpins) == 0 {
+		return noop
+	}
+	return func() {
+		for _, unpin := range unpins {
+			unpin()
+		}
+	}
+}
+
+// searchBy is like [Custom.search], but with a key and a function that compares an element with it.
+func searchBy[T, K any](s *Custom[T], key K, cmp func(T, K) int) (int, bool) {
+	if !s.stored() {
+		return slices.BinarySearchFunc(s.items, key, cmp)
+	}
+
+	n := s.Size()
+	i := sort.Search(n, func(i int) bool { return cmp(s.at(i), key) >= 0 })
+	return i, i < n && cmp(s.at(i), key) == 0
+}
@@ -2,8 +2,13 @@ package smallset
 
 import (
 	"cmp"
+	"container/heap"
+	"encoding/binary"
 	"fmt"
+	"hash/fnv"
 	"slices"
+	"sort"
+	"strings"
 	"testing"
 )
 
@@ -59,6 +64,143 @@ var (
 	}
 )
 
+func TestAdoptCustom(t *testing.T) {
+	items := slices.Clone(people1)
+	s := AdoptCustom(PersonCmp, items)
+
+	if !s.IsEqual(CustomFrom(PersonCmp, people1...)) {
+		t.Errorf("expected %v, got %v", unique1, s.items)
+	}
+	if &s.items[0] != &items[0] {
+		t.Errorf("expected the set to alias the adopted slice")
+	}
+}
+
+func TestCustomComparator(t *testing.T) {
+	s := CustomFrom(PersonCmp, people1...)
+	compatible := CustomFrom(s.Comparator(), people2...)
+
+	merged := MergeCustom(s.Comparator(), s, compatible)
+	if !merged.IsEqual(s.Union(compatible)) {
+		t.Errorf("expected the sets built with Comparator to be compatible")
+	}
+}
+
+func TestCustomFromSeq(t *testing.T) {
+	s := CustomFromSeq(PersonCmp, slices.Values(people1))
+	if !slices.Equal(s.items, unique1) {
+		t.Errorf("Expected %v, got %v", unique1, s.items)
+	}
+}
+
+func TestCustomFromHeap(t *testing.T) {
+	h := &intHeap{}
+	for _, e := range []int{7, 3, 9, 3, 1} {
+		heap.Push(h, e)
+	}
+
+	s := CustomFromHeap(cmp.Compare[int], h)
+	if !slices.Equal(s.items, []int{1, 3, 7, 9}) {
+		t.Errorf("Expected %v, got %v", []int{1, 3, 7, 9}, s.items)
+	}
+}
+
+func TestCustomFromChan(t *testing.T) {
+	ch := make(chan Person, len(people2))
+	for _, p := range people2 {
+		ch <- p
+	}
+	close(ch)
+
+	s := CustomFromChan(PersonCmp, ch)
+	if !slices.Equal(s.items, unique2) {
+		t.Errorf("Expected %v, got %v", unique2, s.items)
+	}
+}
+
+func TestCustomHash(t *testing.T) {
+	h := fnv.New64a()
+	encode := func(dst []byte, p Person) []byte {
+		return binary.BigEndian.AppendUint64(dst, uint64(p.ID))
+	}
+
+	s1 := CustomFrom(PersonCmp, people1...)
+	s2 := CustomFrom(PersonCmp, unique1...)
+	s3 := CustomFrom(PersonCmp, unique2...)
+
+	if s1.Hash(h, encode) != s2.Hash(h, encode) {
+		t.Errorf("expected equal sets to have equal hashes")
+	}
+	if s1.Hash(h, encode) == s3.Hash(h, encode) {
+		t.Errorf("expected different sets to have different hashes")
+	}
+}
+
+func TestCustomKey(t *testing.T) {
+	encode := func(dst []byte, p Person) []byte {
+		return binary.AppendVarint(dst, int64(p.ID))
+	}
+
+	memo := map[string]int{}
+	memo[CustomFrom(PersonCmp, people1...).Key(encode)] = 1
+	memo[CustomFrom(PersonCmp, people2...).Key(encode)] = 2
+
+	if v := memo[CustomFrom(PersonCmp, unique1...).Key(encode)]; v != 1 {
+		t.Errorf("expected to find the key of an equal set, got %d", v)
+	}
+	if len(memo) != 2 {
+		t.Errorf("expected different sets to have different keys")
+	}
+}
+
+func TestCustomFingerprint(t *testing.T) {
+	hash := func(p Person) uint64 { return uint64(p.ID) }
+	s := CustomFrom(PersonCmp, people1...).WithFingerprint(hash)
+	initial := s.Fingerprint()
+
+	s.Add(Person{ID: 10})
+	s.RemoveBefore(Person{ID: 3})
+	s.Add(Person{ID: 1})
+	s.Add(Person{ID: 2})
+	s.PopMax()
+
+	if s.Fingerprint() != initial {
+		t.Errorf("expected the fingerprint to go back to the initial value")
+	}
+
+	other := CustomFrom(PersonCmp, Person{ID: 1}, Person{ID: 4}).WithFingerprint(hash)
+	if other.Fingerprint() == CustomFrom(PersonCmp, Person{ID: 2}, Person{ID: 3}).WithFingerprint(hash).Fingerprint() {
+		t.Errorf("expected different sets to have different fingerprints")
+	}
+}
+
+func TestCustomRelease(t *testing.T) {
+	s := CustomFrom(PersonCmp, people2...)
+	items := s.Release()
+
+	if len(items) != 4 || !s.IsEmpty() {
+		t.Errorf("expected 4 released items and an empty set, got %v and %v", items, s.items)
+	}
+}
+
+func TestCustomClearAndShrink(t *testing.T) {
+	s := CustomFrom(PersonCmp, people1...)
+	backing := s.items[:cap(s.items)]
+
+	s.ClearAndShrink(2)
+	if !s.IsEmpty() || s.Capacity() > 2 {
+		t.Errorf("expected an empty set with capacity at most 2, got size %d and capacity %d", s.Size(), s.Capacity())
+	}
+
+	// the old elements are zeroed, so that their pointers are released
+	for _, p := range backing {
+		if p != (Person{}) {
+			t.Errorf("expected the old elements to be zeroed, got %v", backing)
+			break
+		}
+	}
+}
+
 func TestCustomContains(t *testing.T) {
 	s := CustomFrom(PersonCmp, people1...)
 
@@ -119,6 +261,27 @@ func TestCustomAdd(t *testing.T) {
 	}
 }
 
+func TestCustomAddSorted(t *testing.T) {
+	s := CustomFrom(PersonCmp, Person{ID: 30, Name: "Gamma"})
+	items := []Person{{ID: 20, Name: "Delta"}, {ID: 30, Name: "Other"}, {ID: 40, Name: "Beta"}, {ID: 40, Name: "Duplicate"}}
+
+	if res := s.AddSorted(items); res != 2 {
+		t.Errorf("AddSorted expected 2, got %d", res)
+	}
+
+	expected := []Person{{ID: 20, Name: "Delta"}, {ID: 30, Name: "Gamma"}, {ID: 40, Name: "Beta"}}
+	if !slices.Equal(s.items, expected) {
+		t.Errorf("Items mismatch.\nExpected: %v\nActual: %v", expected, s.items)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected AddSorted to panic on unsorted items")
+		}
+	}()
+	s.AddSorted([]Person{{ID: 2}, {ID: 1}})
+}
+
 func TestCustomRemove(t *testing.T) {
 	cases := []struct {
 		initial  []Person
@@ -158,6 +321,87 @@ func TestCustomRemove(t *testing.T) {
 		})
 	}
 }
+func TestCustomReplaceAt(t *testing.T) {
+	s := CustomFrom(PersonCmp, people2...)
+	items := s.Items()
+
+	if s.ReplaceAt(1, Person{ID: 20, Name: "Dup"}) {
+		t.Errorf("expected ReplaceAt to reject a duplicate of the previous element")
+	}
+
+	renamed := Person{ID: items[1].ID, Name: "Renamed"}
+	if !s.ReplaceAt(1, renamed) {
+		t.Errorf("expected ReplaceAt to accept an equivalent element")
+	}
+
+	items[1] = renamed
+	if !slices.Equal(s.items, items) {
+		t.Errorf("Items mismatch.\nExpected: %v\nActual: %v", items, s.items)
+	}
+}
+
+func TestCustomMarkRemove(t *testing.T) {
+	s := CustomFrom(PersonCmp, people1...)
+	for _, p := range s.Ascend() {
+		if p.Age > 30 {
+			s.MarkRemove(p)
+		}
+	}
+
+	if removed := s.Compact(); removed != 2 {
+		t.Errorf("expected 2 elements removed, got %d", removed)
+	}
+
+	expected := []Person{unique1[1], unique1[2]}
+	if !slices.Equal(s.items, expected) {
+		t.Errorf("Items mismatch.\nExpected: %v\nActual: %v", expected, s.items)
+	}
+}
+
+func TestCustomRemoveIndices(t *testing.T) {
+	s := CustomFrom(PersonCmp, people2...)
+	items := s.Items()
+
+	if res := s.RemoveIndices(3, 1, 3); res != 2 {
+		t.Errorf("RemoveIndices expected 2 got %d", res)
+	}
+
+	expected := []Person{items[0], items[2]}
+	if !slices.Equal(s.items, expected) {
+		t.Errorf("Items mismatch.\nExpected: %v\nActual: %v", expected, s.items)
+	}
+}
+
+func TestCustomExtractFunc(t *testing.T) {
+	s := CustomFrom(PersonCmp, people2...)
+	items := s.Items()
+
+	extracted := s.ExtractFunc(func(p Person) bool { return p.ID >= 40 })
+	if expected := items[2:]; !slices.Equal(extracted.items, expected) {
+		t.Errorf("Extracted mismatch.\nExpected: %v\nActual: %v", expected, extracted.items)
+	}
+	if expected := items[:2]; !slices.Equal(s.items, expected) {
+		t.Errorf("Items mismatch.\nExpected: %v\nActual: %v", expected, s.items)
+	}
+
+	if !extracted.Add(Person{ID: 45}) || extracted.Min().ID != 40 {
+		t.Errorf("expected the extracted set to keep the comparison function, got %v", extracted.items)
+	}
+}
+
+func TestCustomApplyDiff(t *testing.T) {
+	s := CustomFrom(cmp.Compare[int], 10, 20, 30)
+	added, removed := s.ApplyDiff([]int{35, 20}, []int{35, 30, 20})
+
+	if added != 1 || removed != 1 {
+		t.Errorf("ApplyDiff expected (1, 1), got (%d, %d)", added, removed)
+	}
+
+	if expected := []int{10, 20, 35}; !slices.Equal(s.items, expected) {
+		t.Errorf("Items mismatch.\nExpected: %v\nActual: %v", expected, s.items)
+	}
+}
+
 func TestCustomRemoveBefore(t *testing.T) {
 	cases := []struct {
 		initial  []Person
@@ -232,6 +476,17 @@ func TestCustomRemoveFrom(t *testing.T) {
 	}
 }
 
+func TestCustomTrimPercentile(t *testing.T) {
+	s := CustomFrom(PersonCmp, people2...)
+	if removed := s.TrimPercentile(0.25, 0.25); removed != 2 {
+		t.Errorf("expected 2 removed, got %d", removed)
+	}
+
+	if s.Min().ID != 30 || s.Max().ID != 40 {
+		t.Errorf("expected IDs 30 and 40 to remain, got %v", s.items)
+	}
+}
+
 func TestCustomRemoveBetween(t *testing.T) {
 	cases := []struct {
 		initial  []Person
@@ -269,6 +524,201 @@ func TestCustomRemoveBetween(t *testing.T) {
 	}
 }
 
+func TestCustomKeepBetween(t *testing.T) {
+	s := CustomFrom(PersonCmp, people2...)
+	items := s.Items()
+
+	if res := s.KeepBetween(Person{ID: 25}, Person{ID: 50}); res != 2 {
+		t.Errorf("KeepBetween expected 2 got %d", res)
+	}
+	if expected := items[1:3]; !slices.Equal(s.items, expected) {
+		t.Errorf("Items mismatch.\nExpected: %v\nActual: %v", expected, s.items)
+	}
+}
+
+func TestCustomWithShrink(t *testing.T) {
+	s := NewCustom(cmp.Compare[int], 200).WithShrink(0.25)
+	for i := range 100 {
+		s.Add(i)
+	}
+
+	s.RemoveBetween(0, 40)
+	if s.Capacity() != 200 {
+		t.Errorf("expected capacity 200, got %d", s.Capacity())
+	}
+
+	s.RemoveBetween(40, 90)
+	if s.Capacity() != 20 {
+		t.Errorf("expected capacity 20, got %d", s.Capacity())
+	}
+
+	if items := s.Items(); !slices.Equal(items, []int{90, 91, 92, 93, 94, 95, 96, 97, 98, 99}) {
+		t.Errorf("unexpected items after shrinking: %v", items)
+	}
+}
+
+func TestFindBy(t *testing.T) {
+	s := CustomFrom(PersonCmp, people1...)
+	byID := func(p Person, id int) int { return cmp.Compare(p.ID, id) }
+
+	cases := []struct {
+		id       int
+		expected Person
+		index    int
+		found    bool
+	}{
+		{id: 0, index: 0, found: false},
+		{id: 1, expected: Person{ID: 1, Name: "Bob", Age: 50}, index: 0, found: true},
+		{id: 3, expected: Person{ID: 3, Name: "Alice", Age: 25}, index: 2, found: true},
+		{id: 5, index: 4, found: false},
+	}
+
+	for i, test := range cases {
+		t.Run(fmt.Sprintf("Case_%d", i), func(t *testing.T) {
+			p, index, found := FindBy(s, test.id, byID)
+			if p != test.expected || index != test.index || found != test.found {
+				t.Errorf("Expected (%v, %d, %t), got (%v, %d, %t)", test.expected, test.index, test.found, p, index, found)
+			}
+		})
+	}
+}
+
+func TestRemoveBy(t *testing.T) {
+	s := CustomFrom(PersonCmp, people1...)
+	byID := func(p Person, id int) int { return cmp.Compare(p.ID, id) }
+
+	if !RemoveBy(s, 2, byID) || RemoveBy(s, 2, byID) || RemoveBy(s, 9, byID) {
+		t.Error("unexpected result of RemoveBy")
+	}
+
+	expected := []Person{unique1[0], unique1[2], unique1[3]}
+	if !slices.Equal(s.items, expected) {
+		t.Errorf("Expected %v, got %v", expected, s.items)
+	}
+}
+
+func TestRemoveRangeBy(t *testing.T) {
+	type account struct {
+		name    string
+		balance int
+	}
+	byName := func(a account, name string) int { return strings.Compare(a.name, name) }
+
+	cases := []struct {
+		min, max string
+		expected []string
+	}{
+		{min: "user:", max: "user;", expected: []string{"admin:1", "userx:1"}},
+		{min: "a", max: "b", expected: []string{"user:1", "user:2", "userx:1"}},
+		{min: "z", max: "a", expected: []string{"admin:1", "user:1", "user:2", "userx:1"}},
+	}
+
+	for i, test := range cases {
+		t.Run(fmt.Sprintf("Case_%d", i), func(t *testing.T) {
+			s := CustomFrom(func(a, b account) int { return strings.Compare(a.name, b.name) },
+				account{name: "user:2"}, account{name: "admin:1"}, account{name: "user:1"}, account{name: "userx:1"})
+			removed := RemoveRangeBy(s, test.min, test.max, byName)
+
+			var names []string
+			for _, a := range s.items {
+				names = append(names, a.name)
+			}
+
+			if !slices.Equal(names, test.expected) {
+				t.Errorf("Expected %v, got %v", test.expected, names)
+			}
+			if removed != 4-len(test.expected) {
+				t.Errorf("Expected %d removed, got %d", 4-len(test.expected), removed)
+			}
+		})
+	}
+}
+
+func TestCustomFindMany(t *testing.T) {
+	s := CustomFrom(PersonCmp, people2...)
+	probes := []Person{{ID: 60}, {ID: 30}, {ID: 5}, {ID: 45}, {ID: 30}}
+
+	idxs, found := s.FindMany(probes)
+	for i, p := range probes {
+		idx, ok := s.Find(p)
+		if idxs[i] != idx || found[i] != ok {
+			t.Errorf("FindMany(%v) expected (%d, %t), got (%d, %t)", p, idx, ok, idxs[i], found[i])
+		}
+	}
+}
+
+func TestCustomIndexFunc(t *testing.T) {
+	s := CustomFrom(PersonCmp, people1...)
+	named := func(name string) func(Person) bool {
+		return func(p Person) bool { return p.Name == name }
+	}
+
+	if !s.ContainsFunc(named("Eve")) || s.ContainsFunc(named("Mallory")) {
+		t.Errorf("ContainsFunc mismatch")
+	}
+
+	if i := s.IndexFunc(named("Eve")); i == -1 || s.items[i].Name != "Eve" {
+		t.Errorf("IndexFunc returned %d", i)
+	}
+	if i := s.IndexFunc(named("Mallory")); i != -1 {
+		t.Errorf("IndexFunc expected -1, got %d", i)
+	}
+}
+
+func TestCustomIndexRange(t *testing.T) {
+	s := CustomFrom(PersonCmp, people2...)
+
+	start, end := s.IndexRange(Person{ID: 25}, Person{ID: 50})
+	if start != 1 || end != 3 {
+		t.Errorf("IndexRange expected [1, 3), got [%d, %d)", start, end)
+	}
+}
+
+func TestEqualOrderedCustom(t *testing.T) {
+	cases := []struct {
+		ordered  []int
+		custom   *Custom[int]
+		expected bool
+	}{
+		{ordered: []int{}, custom: NewCustom(cmp.Compare[int], 1), expected: true},
+		{ordered: []int{1, 2, 3}, custom: CustomFrom(cmp.Compare[int], 3, 2, 1), expected: true},
+		{ordered: []int{1, 2, 3}, custom: CustomFrom(Reverse(cmp.Compare[int]), 1, 2, 3), expected: true},
+		{ordered: []int{1, 2, 3}, custom: CustomFrom(cmp.Compare[int], 1, 2, 4), expected: false},
+		{ordered: []int{1, 2}, custom: CustomFrom(cmp.Compare[int], 1, 2, 3), expected: false},
+	}
+
+	for i, test := range cases {
+		t.Run(fmt.Sprintf("Case_%d", i), func(t *testing.T) {
+			if equal := EqualOrderedCustom(From(test.ordered...), test.custom); equal != test.expected {
+				t.Errorf("Expected %v, got %v", test.expected, equal)
+			}
+		})
+	}
+}
+
+func TestEqualFuncCustom(t *testing.T) {
+	sameID := func(id int, p Person) bool { return id == p.ID }
+	records := CustomFrom(PersonCmp, people1...)
+
+	cases := []struct {
+		ids      []int
+		expected bool
+	}{
+		{ids: []int{4, 3, 2, 1}, expected: true},
+		{ids: []int{1, 2, 3}, expected: false},
+		{ids: []int{1, 2, 3, 5}, expected: false},
+	}
+
+	for i, test := range cases {
+		t.Run(fmt.Sprintf("Case_%d", i), func(t *testing.T) {
+			ids := CustomFrom(cmp.Compare[int], test.ids...)
+			if res := EqualFuncCustom(ids, records, sameID); res != test.expected {
+				t.Errorf("EqualFuncCustom expected %t, got %t", test.expected, res)
+			}
+		})
+	}
+}
+
 func TestCustomIsEqual(t *testing.T) {
 	s1 := CustomFrom(cmp.Compare[int], 1, 2, 3)
 	s2 := CustomFrom(cmp.Compare[int], 3, 2, 1)
@@ -335,6 +785,34 @@ func TestCustomMax(t *testing.T) {
 	}
 }
 
+func TestCustomPopMin(t *testing.T) {
+	s := CustomFrom(PersonCmp, people1...)
+
+	for i, exp := range unique1 {
+		if res := s.PopMin(); res != exp {
+			t.Errorf("PopMin() %d failed.\nExpected: %v\nActual:   %v", i, exp, res)
+		}
+	}
+
+	if !s.IsEmpty() {
+		t.Errorf("expected empty set, got %v", s.items)
+	}
+}
+
+func TestCustomPopMax(t *testing.T) {
+	s := CustomFrom(PersonCmp, people2...)
+
+	for i, exp := range slices.Backward(unique2) {
+		if res := s.PopMax(); res != exp {
+			t.Errorf("PopMax() %d failed.\nExpected: %v\nActual:   %v", i, exp, res)
+		}
+	}
+
+	if !s.IsEmpty() {
+		t.Errorf("expected empty set, got %v", s.items)
+	}
+}
+
 func TestCustomMinK(t *testing.T) {
 	cases := []struct {
 		set      *Custom[int]
@@ -381,6 +859,51 @@ func TestCustomMaxK(t *testing.T) {
 	}
 }
 
+func TestCustomPage(t *testing.T) {
+	s := CustomFrom(PersonCmp, people1...)
+
+	page, total := s.Page(1, 2)
+	if !slices.Equal(page, unique1[1:3]) {
+		t.Errorf("Page failed.\nExpected: %v\nActual: %v", unique1[1:3], page)
+	}
+	if total != len(unique1) {
+		t.Errorf("expected total %d, got %d", len(unique1), total)
+	}
+}
+
+func TestCustomMinKAppend(t *testing.T) {
+	s := CustomFrom(PersonCmp, people1...)
+	buf := s.MinKAppend(nil, 2)
+
+	if !slices.Equal(buf, unique1[:2]) {
+		t.Errorf("MinKAppend failed.\nExpected: %v\nActual: %v", unique1[:2], buf)
+	}
+}
+
+func TestCustomMaxKAppend(t *testing.T) {
+	s := CustomFrom(PersonCmp, people1...)
+	buf := s.MaxKAppend(nil, 2)
+
+	if !slices.Equal(buf, unique1[2:]) {
+		t.Errorf("MaxKAppend failed.\nExpected: %v\nActual: %v", unique1[2:], buf)
+	}
+}
+
+func TestCustomSortInterface(t *testing.T) {
+	s := CustomFrom(PersonCmp, people2...)
+	view := s.SortInterface()
+
+	if view.Len() != len(unique2) {
+		t.Errorf("expected Len %d, got %d", len(unique2), view.Len())
+	}
+	if !sort.IsSorted(view) {
+		t.Errorf("expected view to be sorted")
+	}
+	if view.Less(1, 1) {
+		t.Errorf("expected an element to not be less than itself")
+	}
+}
+
 func TestCustomBetweenAsc(t *testing.T) {
 	s := CustomFrom(cmp.Compare[int], 1, 3, 5, 7, 9)
 
@@ -433,6 +956,31 @@ func TestCustomBetweenDesc(t *testing.T) {
 
 // --- Binary Set Operation TestCustoms ---
 
+func TestCustomBetweenValues(t *testing.T) {
+	s := CustomFrom(PersonCmp, people1...)
+
+	result := slices.Collect(s.BetweenValues(Person{ID: 2}, Person{ID: 4}))
+	if !slices.Equal(result, unique1[1:3]) {
+		t.Errorf("BetweenValues failed.\nExpected: %v\nActual: %v", unique1[1:3], result)
+	}
+}
+
+func TestCustomPairs(t *testing.T) {
+	s := CustomFrom(PersonCmp, people2...)
+
+	i := 0
+	for a, b := range s.Pairs() {
+		if a != s.items[i] || b != s.items[i+1] {
+			t.Errorf("pair %d: expected (%v, %v), got (%v, %v)", i, s.items[i], s.items[i+1], a, b)
+		}
+		i++
+	}
+
+	if i != s.Size()-1 {
+		t.Errorf("expected %d pairs, got %d", s.Size()-1, i)
+	}
+}
+
 func TestCustomIntersect(t *testing.T) {
 	cases := []struct {
 		s1       []int
@@ -458,6 +1006,10 @@ func TestCustomIntersect(t *testing.T) {
 				t.Errorf("Expected %v, got %v", test.expected, inter.items)
 			}
 
+			if count := s1.IntersectCount(s2); count != len(test.expected) {
+				t.Errorf("IntersectCount expected %d, got %d", len(test.expected), count)
+			}
+
 			if !s1.IsEqual(o1) {
 				t.Errorf("s1 mutated. before %v, after %v", o1, s1)
 			}
@@ -468,6 +1020,28 @@ func TestCustomIntersect(t *testing.T) {
 	}
 }
 
+func TestCustomContainsCount(t *testing.T) {
+	s := CustomFrom(cmp.Compare[int], 1, 3, 5, 7)
+	if count := s.ContainsCount(0, 1, 1, 4, 7, 8); count != 3 {
+		t.Errorf("ContainsCount expected 3, got %d", count)
+	}
+
+	allocs := testing.AllocsPerRun(100, func() { s.IntersectCount(s) })
+	if allocs != 0 {
+		t.Errorf("IntersectCount expected 0 allocations, got %v", allocs)
+	}
+}
+
+func TestCustomContainsMany(t *testing.T) {
+	s := CustomFrom(PersonCmp, people2...)
+	probes := []Person{{ID: 50}, {ID: 10}, {ID: 30}, {ID: 60}, {ID: 20}}
+
+	expected := []bool{true, false, true, false, true}
+	if res := s.ContainsMany(probes); !slices.Equal(res, expected) {
+		t.Errorf("Expected %v, got %v", expected, res)
+	}
+}
+
 func TestCustomDifference(t *testing.T) {
 	cases := []struct {
 		s1       []int
@@ -495,6 +1069,14 @@ func TestCustomDifference(t *testing.T) {
 				t.Errorf("Expected %v, got %v", test.expected, diff.items)
 			}
 
+			if count := s1.DifferenceCount(s2); count != len(test.expected) {
+				t.Errorf("DifferenceCount expected %d, got %d", len(test.expected), count)
+			}
+
+			if complement := s2.Complement(s1); !slices.Equal(complement.items, test.expected) {
+				t.Errorf("Complement expected %v, got %v", test.expected, complement.items)
+			}
+
 			if !s1.IsEqual(o1) {
 				t.Errorf("s1 mutated. before %v, after %v", o1, s1)
 			}
@@ -531,6 +1113,10 @@ func TestCustomUnion(t *testing.T) {
 				t.Errorf("Expected %v, got %v", test.expected, union.items)
 			}
 
+			if count := s1.UnionCount(s2); count != len(test.expected) {
+				t.Errorf("UnionCount expected %d, got %d", len(test.expected), count)
+			}
+
 			if !s1.IsEqual(o1) {
 				t.Errorf("s1 mutated. before %v, after %v", o1, s1)
 			}
@@ -541,6 +1127,73 @@ func TestCustomUnion(t *testing.T) {
 	}
 }
 
+func TestCustomUnionFunc(t *testing.T) {
+	// newest wins, where the age acts as a version
+	newest := func(a, b Person) Person {
+		if b.Age > a.Age {
+			return b
+		}
+		return a
+	}
+
+	s1 := CustomFrom(PersonCmp, Person{ID: 1, Name: "Bob", Age: 1}, Person{ID: 2, Name: "Carl", Age: 5})
+	s2 := CustomFrom(PersonCmp, Person{ID: 2, Name: "Carly", Age: 6}, Person{ID: 3, Name: "Dan", Age: 1})
+
+	union := s1.UnionFunc(s2, newest)
+	expected := []Person{{ID: 1, Name: "Bob", Age: 1}, {ID: 2, Name: "Carly", Age: 6}, {ID: 3, Name: "Dan", Age: 1}}
+	if !slices.Equal(union.items, expected) {
+		t.Errorf("Expected %v, got %v", expected, union.items)
+	}
+
+	union = s2.UnionFunc(s1, newest)
+	if !slices.Equal(union.items, expected) {
+		t.Errorf("Expected %v, got %v", expected, union.items)
+	}
+
+	empty := NewCustom(PersonCmp, 1)
+	if union := empty.UnionFunc(empty, newest); !union.IsEmpty() {
+		t.Errorf("Expected an empty union, got %v", union.items)
+	}
+}
+
+func TestMergeResolve(t *testing.T) {
+	// concatenate the names to check the order of the calls
+	concat := func(a, b Person) Person {
+		a.Name += "+" + b.Name
+		return a
+	}
+
+	sets := []*Custom[Person]{
+		CustomFrom(PersonCmp, Person{ID: 1, Name: "a1"}, Person{ID: 2, Name: "a2"}),
+		CustomFrom(PersonCmp, Person{ID: 2, Name: "b2"}),
+		NewCustom(PersonCmp, 1),
+		CustomFrom(PersonCmp, Person{ID: 2, Name: "d2"}, Person{ID: 3, Name: "d3"}),
+	}
+
+	merged := MergeResolve(PersonCmp, concat, sets...)
+	expected := []Person{{ID: 1, Name: "a1"}, {ID: 2, Name: "a2+b2+d2"}, {ID: 3, Name: "d3"}}
+	if !slices.Equal(merged.items, expected) {
+		t.Errorf("Expected %v, got %v", expected, merged.items)
+	}
+
+	if merged := MergeResolve(PersonCmp, concat); !merged.IsEmpty() {
+		t.Errorf("Expected an empty merge, got %v", merged.items)
+	}
+}
+
+func TestCustomSeqOperations(t *testing.T) {
+	s := CustomFrom(cmp.Compare[int], 1, 3, 5)
+	seq := slices.Values([]int{6, 3, 0, 3, 6})
+
+	if union := s.UnionSeq(seq); !slices.Equal(union.items, []int{0, 1, 3, 5, 6}) {
+		t.Errorf("UnionSeq expected [0 1 3 5 6], got %v", union.items)
+	}
+
+	if diff := s.DifferenceSeq(seq); !slices.Equal(diff.items, []int{1, 5}) {
+		t.Errorf("DifferenceSeq expected [1 5], got %v", diff.items)
+	}
+}
+
 func TestCustomSymmetricDifference(t *testing.T) {
 	cases := []struct {
 		s1       []int
@@ -691,3 +1344,137 @@ func TestCustomIntersectMulti(t *testing.T) {
 		})
 	}
 }
+
+func TestCustomPowerSet(t *testing.T) {
+	s := CustomFrom(PersonCmp, people1...)
+
+	count := 0
+	for subset := range PowerSetCustom(s) {
+		if !slices.IsSortedFunc(subset.items, PersonCmp) {
+			t.Errorf("subset %v is not sorted", subset.items)
+		}
+		if subset.Difference(s).Size() != 0 {
+			t.Errorf("subset %v is not a subset of %v", subset.items, s.items)
+		}
+		count++
+	}
+
+	if count != 1<<len(unique1) {
+		t.Errorf("expected %d subsets, got %d", 1<<len(unique1), count)
+	}
+}
+
+func TestCustomMergeJoin(t *testing.T) {
+	s1 := CustomFrom(PersonCmp, unique1...)
+	s2 := CustomFrom(PersonCmp, Person{ID: 3}, Person{ID: 20})
+
+	expected := []Joined[Person]{
+		{Value: unique1[0], InA: true},
+		{Value: unique1[1], InA: true},
+		{Value: unique1[2], InA: true, InB: true},
+		{Value: unique1[3], InA: true},
+		{Value: Person{ID: 20}, InB: true},
+	}
+
+	joins := slices.Collect(MergeJoinCustom(s1, s2))
+	if !slices.Equal(joins, expected) {
+		t.Errorf("Expected %v, got %v", expected, joins)
+	}
+}
+
+func TestCustomAreDisjoint(t *testing.T) {
+	cases := []struct {
+		sets     [][]int
+		expected bool
+	}{
+		{sets: nil, expected: true},
+		{sets: [][]int{{1, 2, 3}, {}, nil, {5, 4}}, expected: true},
+		{sets: [][]int{{1, 5, 9}, {2, 6}, {3, 9}}, expected: false},
+		{sets: [][]int{{5}, {3}, {5}}, expected: false},
+	}
+
+	for i, test := range cases {
+		t.Run(fmt.Sprintf("Case_%d", i), func(t *testing.T) {
+			sets := make([]*Custom[int], len(test.sets))
+			for i := range test.sets {
+				sets[i] = CustomFrom(cmp.Compare[int], test.sets[i]...)
+			}
+
+			if res := AreDisjointCustom(cmp.Compare[int], sets...); res != test.expected {
+				t.Errorf("Expected %t, got %t", test.expected, res)
+			}
+		})
+	}
+}
+
+func TestCustomCounts(t *testing.T) {
+	type counted struct {
+		e     int
+		count int
+	}
+
+	cases := []struct {
+		sets     [][]int
+		expected []counted
+	}{
+		{sets: nil, expected: nil},
+		{sets: [][]int{{}, nil}, expected: nil},
+		{sets: [][]int{{1, 2}}, expected: []counted{{1, 1}, {2, 1}}},
+		{sets: [][]int{{1, 3}, {}, {3, 2}}, expected: []counted{{1, 1}, {2, 1}, {3, 2}}},
+		{sets: [][]int{{5, 6}, {5}, {4, 5, 6}}, expected: []counted{{4, 1}, {5, 3}, {6, 2}}},
+	}
+
+	for i, test := range cases {
+		t.Run(fmt.Sprintf("Case_%d", i), func(t *testing.T) {
+			sets := make([]*Custom[int], len(test.sets))
+			for i := range test.sets {
+				sets[i] = CustomFrom(cmp.Compare[int], test.sets[i]...)
+			}
+
+			var res []counted
+			for e, count := range CountsCustom(cmp.Compare[int], sets...) {
+				res = append(res, counted{e, count})
+			}
+
+			if !slices.Equal(res, test.expected) {
+				t.Errorf("Expected %v, got %v", test.expected, res)
+			}
+		})
+	}
+}
+
+func TestCustomMultiPartition(t *testing.T) {
+	type tagged struct {
+		e    int
+		mask uint64
+	}
+
+	cases := []struct {
+		sets     [][]int
+		expected []tagged
+	}{
+		{sets: nil, expected: nil},
+		{sets: [][]int{{1, 2}}, expected: []tagged{{1, 0b1}, {2, 0b1}}},
+		{sets: [][]int{{1, 3}, {}, {3, 2}}, expected: []tagged{{1, 0b001}, {2, 0b100}, {3, 0b101}}},
+		{sets: [][]int{{5}, {5}, {5}}, expected: []tagged{{5, 0b111}}},
+		{sets: [][]int{{1, 4}, {2, 4}, {3, 4}, {4}}, expected: []tagged{{1, 0b0001}, {2, 0b0010}, {3, 0b0100}, {4, 0b1111}}},
+	}
+
+	for i, test := range cases {
+		t.Run(fmt.Sprintf("Case_%d", i), func(t *testing.T) {
+			sets := make([]*Custom[int], len(test.sets))
+			for i := range test.sets {
+				sets[i] = CustomFrom(cmp.Compare[int], test.sets[i]...)
+			}
+
+			var res []tagged
+			for e, mask := range MultiPartitionCustom(cmp.Compare[int], sets...) {
+				res = append(res, tagged{e, mask})
+			}
+
+			if !slices.Equal(res, test.expected) {
+				t.Errorf("Expected %v, got %v", test.expected, res)
+			}
+		})
+	}
+}
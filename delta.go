@@ -0,0 +1,80 @@
+package smallset
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// EncodeDelta returns a compact encoding of the integer set, made of the uvarint count,
+// the varint of the smallest element and the uvarint differences between consecutive elements.
+// Since the elements are sorted and unique, the differences are small for dense sets and usually
+// fit in a single byte, making it much smaller than fixed-width encodings.
+// The encoding can be decoded with [DecodeDelta].
+func EncodeDelta[T Integer](s *Ordered[T]) []byte {
+	defer s.pin()()
+	buf := make([]byte, 0, binary.MaxVarintLen64+2*len(s.items))
+	buf = binary.AppendUvarint(buf, uint64(len(s.items)))
+	if len(s.items) == 0 {
+		return buf
+	}
+
+	buf = binary.AppendVarint(buf, int64(s.items[0]))
+	for i := 1; i < len(s.items); i++ {
+		buf = binary.AppendUvarint(buf, uint64(s.items[i])-uint64(s.items[i-1]))
+	}
+	return buf
+}
+
+// DecodeDelta returns the set encoded in data by [EncodeDelta].
+// It returns [ErrInvalidFormat] if the data is malformed, or if an element overflows T.
+func DecodeDelta[T Integer](data []byte) (*Ordered[T], error) {
+	count, n := binary.Uvarint(data)
+	if n <= 0 {
+		return nil, fmt.Errorf("%w: invalid count", ErrInvalidFormat)
+	}
+	data = data[n:]
+
+	if count == 0 {
+		if len(data) > 0 {
+			return nil, fmt.Errorf("%w: trailing bytes", ErrInvalidFormat)
+		}
+		return New[T](defaultCapacity), nil
+	}
+
+	// every element takes at least one byte
+	if count > uint64(len(data)) {
+		return nil, fmt.Errorf("%w: count %d exceeds the data length", ErrInvalidFormat, count)
+	}
+
+	first, n := binary.Varint(data)
+	if n <= 0 || int64(T(first)) != first {
+		return nil, fmt.Errorf("%w: invalid first element", ErrInvalidFormat)
+	}
+	data = data[n:]
+
+	items := make([]T, 1, count)
+	items[0] = T(first)
+	prev := uint64(items[0])
+
+	for i := uint64(1); i < count; i++ {
+		delta, n := binary.Uvarint(data)
+		if n <= 0 || delta == 0 {
+			return nil, fmt.Errorf("%w: invalid delta of element %d", ErrInvalidFormat, i)
+		}
+		data = data[n:]
+
+		next := prev + delta
+		e := T(next)
+		if uint64(e) != next || e <= items[i-1] {
+			return nil, fmt.Errorf("%w: element %d overflows", ErrInvalidFormat, i)
+		}
+
+		items = append(items, e)
+		prev = next
+	}
+
+	if len(data) > 0 {
+		return nil, fmt.Errorf("%w: trailing bytes", ErrInvalidFormat)
+	}
+	return &Ordered[T]{items: items}, nil
+}
@@ -0,0 +1,89 @@
+package smallset
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestDeltaRoundTrip(t *testing.T) {
+	t.Run("int", func(t *testing.T) {
+		cases := [][]int{
+			{},
+			{0},
+			{-5, -1, 0, 3, 1000},
+			{math.MinInt, 0, math.MaxInt},
+		}
+
+		for i, items := range cases {
+			t.Run(fmt.Sprintf("Case_%d", i), func(t *testing.T) {
+				s, err := DecodeDelta[int](EncodeDelta(From(items...)))
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				if !slices.Equal(s.items, items) {
+					t.Errorf("Items mismatch.\nExpected: %v\nActual: %v", items, s.items)
+				}
+			})
+		}
+	})
+
+	t.Run("int8", func(t *testing.T) {
+		items := []int8{math.MinInt8, -1, 0, math.MaxInt8}
+		s, err := DecodeDelta[int8](EncodeDelta(From(items...)))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if !slices.Equal(s.items, items) {
+			t.Errorf("Items mismatch.\nExpected: %v\nActual: %v", items, s.items)
+		}
+	})
+
+	t.Run("uint64", func(t *testing.T) {
+		items := []uint64{0, 1, math.MaxUint64 - 1, math.MaxUint64}
+		s, err := DecodeDelta[uint64](EncodeDelta(From(items...)))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if !slices.Equal(s.items, items) {
+			t.Errorf("Items mismatch.\nExpected: %v\nActual: %v", items, s.items)
+		}
+	})
+}
+
+func TestDeltaSize(t *testing.T) {
+	s := NewRange(1_000_000, 1_001_000, 3)
+	data := EncodeDelta(s)
+
+	// 2 bytes for the count, 3 for the first element and 1 for each delta
+	if expected := 5 + s.Size() - 1; len(data) != expected {
+		t.Errorf("expected %d bytes, got %d", expected, len(data))
+	}
+}
+
+func TestDecodeDeltaInvalid(t *testing.T) {
+	cases := []struct {
+		name string
+		data []byte
+	}{
+		{name: "empty", data: nil},
+		{name: "trailing bytes", data: []byte{0, 1}},
+		{name: "truncated", data: []byte{3, 2, 1}},
+		{name: "zero delta", data: []byte{2, 2, 0}},
+		{name: "first overflows", data: []byte{1, 0x80, 0x02}},
+		{name: "element overflows", data: []byte{2, 0xfe, 0x01, 1}},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := DecodeDelta[int8](test.data); !errors.Is(err, ErrInvalidFormat) {
+				t.Errorf("expected ErrInvalidFormat, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package smallset
+
+import (
+	"encoding/binary"
+	"fmt"
+	"iter"
+	"slices"
+)
+
+// maxDigestLevels is the maximum number of levels of a [RangeDigest], whose last level has 2^23 ranges.
+const maxDigestLevels = 24
+
+// RangeDigest is a hierarchical digest of a set, like a Merkle tree, for reconciling replicas
+// of the same set over the network. Every element is assigned a 64-bit hash of its canonical
+// encoding, and level l of the digest splits the hash space into 2^l equal ranges, each summarized
+// by the sum of the hashes of its elements. The hashes don't depend on the process, so digests
+// computed on different machines can be compared.
+//
+// Two replicas exchange their digests (or walk them level by level), find the ranges that differ
+// with [RangeDigest.Diff], and exchange only the elements in those ranges, which they get
+// with [Ordered.RangeItems] or [Custom.RangeItems]. Digests are sent with [RangeDigest.MarshalBinary]
+// and received with [RangeDigest.UnmarshalBinary].
+type RangeDigest struct {
+	// levels[l] holds the sums of the 2^l ranges of level l.
+	levels [][]uint64
+}
+
+// newRangeDigest returns the digest with the provided number of levels of the elements
+// with the provided hashes.
+func newRangeDigest(levels int, hashes iter.Seq[uint64]) *RangeDigest {
+	d := &RangeDigest{levels: make([][]uint64, levels)}
+	for l := range levels {
+		d.levels[l] = make([]uint64, 1<<l)
+	}
+
+	leaves := d.levels[levels-1]
+	for h := range hashes {
+		leaves[leafOf(h, levels)] += h
+	}
+
+	d.sumLevels()
+	return d
+}
+
+// sumLevels computes the sums of the levels above the last one.
+func (d *RangeDigest) sumLevels() {
+	for l := len(d.levels) - 2; l >= 0; l-- {
+		for i := range d.levels[l] {
+			d.levels[l][i] = d.levels[l+1][2*i] + d.levels[l+1][2*i+1]
+		}
+	}
+}
+
+// MarshalBinary implements [encoding.BinaryMarshaler], encoding the digest as its number of levels
+// in one byte, followed by the 8 bytes big-endian sums of the ranges of the last level.
+// The other levels are recomputed by [RangeDigest.UnmarshalBinary]. The error is always nil.
+func (d *RangeDigest) MarshalBinary() ([]byte, error) {
+	leaves := d.levels[len(d.levels)-1]
+	data := make([]byte, 1, 1+8*len(leaves))
+	data[0] = byte(len(d.levels))
+	for _, sum := range leaves {
+		data = binary.BigEndian.AppendUint64(data, sum)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary implements [encoding.BinaryUnmarshaler], replacing the digest with the one
+// encoded by [RangeDigest.MarshalBinary], typically received from a replica to call [RangeDigest.Diff].
+// It returns [ErrInvalidFormat] if the data is malformed.
+func (d *RangeDigest) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 {
+		return fmt.Errorf("%w: missing digest levels", ErrInvalidFormat)
+	}
+
+	levels := int(data[0])
+	if levels < 1 || levels > maxDigestLevels {
+		return fmt.Errorf("%w: digest levels must be in [1, %d], got %d", ErrInvalidFormat, maxDigestLevels, levels)
+	}
+	if len(data) != 1+8*(1<<(levels-1)) {
+		return fmt.Errorf("%w: digest of %d levels has %d bytes", ErrInvalidFormat, levels, len(data))
+	}
+
+	d.levels = make([][]uint64, levels)
+	for l := range levels {
+		d.levels[l] = make([]uint64, 1<<l)
+	}
+
+	leaves := d.levels[levels-1]
+	for i := range leaves {
+		leaves[i] = binary.BigEndian.Uint64(data[1+8*i:])
+	}
+
+	d.sumLevels()
+	return nil
+}
+
+// Levels returns the number of levels of the digest.
+func (d *RangeDigest) Levels() int {
+	return len(d.levels)
+}
+
+// Range returns the digest of the i-th range of the level, which has 2^level ranges.
+// It panics if the level or the range are out of bounds.
+func (d *RangeDigest) Range(level, i int) uint64 {
+	return d.levels[level][i]
+}
+
+// Diff returns the indices of the ranges of the last level that differ between the two digests,
+// in ascending order. It walks the digests from the top, only descending into the ranges that differ.
+// It panics if the digests have a different number of levels.
+func (d *RangeDigest) Diff(other *RangeDigest) []int {
+	if len(d.levels) != len(other.levels) {
+		panic("smallset.RangeDigest.Diff: digests have a different number of levels")
+	}
+
+	if d.levels[0][0] == other.levels[0][0] {
+		return nil
+	}
+
+	diff := []int{0}
+	for l := 1; l < len(d.levels); l++ {
+		next := make([]int, 0, 2*len(diff))
+		for _, parent := range diff {
+			for i := 2 * parent; i <= 2*parent+1; i++ {
+				if d.levels[l][i] != other.levels[l][i] {
+					next = append(next, i)
+				}
+			}
+		}
+		diff = next
+	}
+	return diff
+}
+
+// leafOf returns the index of the range of the last level that contains the hash h.
+func leafOf(h uint64, levels int) int {
+	// shifting by 64 when there is a single level gives 0
+	return int(h >> (64 - (levels - 1)))
+}
+
+// rangeItems returns the items whose hash falls in one of the ranges of the last level.
+func rangeItems[T any](items []T, levels int, ranges []int, hash func(T) uint64) []T {
+	ranges = slices.Sorted(slices.Values(ranges))
+	var found []T
+	for _, e := range items {
+		if _, ok := slices.BinarySearch(ranges, leafOf(hash(e), levels)); ok {
+			found = append(found, e)
+		}
+	}
+	return found
+}
+
+// stableHash returns a hash of the encoding of an element that doesn't depend on the process.
+// It's FNV-1a, mixed to spread the bits of short encodings over the whole hash space.
+func stableHash(enc []byte) uint64 {
+	h := uint64(14695981039346656037)
+	for _, b := range enc {
+		h ^= uint64(b)
+		h *= 1099511628211
+	}
+	return mix(h)
+}
@@ -0,0 +1,121 @@
+package smallset
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestRangeDigest(t *testing.T) {
+	cases := []struct {
+		levels   int
+		s1, s2   []int
+		expected []int
+	}{
+		{levels: 1, s1: []int{1, 2, 3}, s2: []int{1, 2, 3}, expected: nil},
+		{levels: 1, s1: []int{1, 2, 3}, s2: []int{1, 2}, expected: []int{3}},
+		{levels: 8, s1: []int{}, s2: []int{}, expected: nil},
+		{levels: 8, s1: []int{1, 2, 3, 4, 5}, s2: []int{1, 2, 4, 5, 6}, expected: []int{3, 6}},
+		{levels: 12, s1: []int{10, 20, 30}, s2: []int{}, expected: []int{10, 20, 30}},
+	}
+
+	for i, test := range cases {
+		t.Run(fmt.Sprintf("Case_%d", i), func(t *testing.T) {
+			s1, s2 := From(test.s1...), From(test.s2...)
+			d1, d2 := s1.RangeDigest(test.levels), s2.RangeDigest(test.levels)
+
+			ranges := d1.Diff(d2)
+			if !slices.Equal(ranges, d2.Diff(d1)) {
+				t.Fatalf("Diff is not symmetric: %v and %v", ranges, d2.Diff(d1))
+			}
+
+			// the elements in the differing ranges must cover the symmetric difference
+			exchanged := Merge(From(s1.RangeItems(test.levels, ranges)...), From(s2.RangeItems(test.levels, ranges)...))
+			diff := s1.SymmetricDifference(s2)
+			if !diff.IsEqual(From(test.expected...)) {
+				t.Fatalf("expected symmetric difference %v, got %v", test.expected, diff.items)
+			}
+			if !diff.Difference(exchanged).IsEmpty() {
+				t.Errorf("expected the exchanged elements %v to cover %v", exchanged.items, diff.items)
+			}
+
+			if len(test.expected) == 0 && len(ranges) > 0 {
+				t.Errorf("expected no differing ranges, got %v", ranges)
+			}
+		})
+	}
+}
+
+func TestRangeDigestCustom(t *testing.T) {
+	encode := func(dst []byte, p Person) []byte {
+		return binary.BigEndian.AppendUint64(dst, uint64(p.ID))
+	}
+
+	s1 := CustomFrom(PersonCmp, people1...)
+	s2 := CustomFrom(PersonCmp, people2...)
+
+	d1, d2 := s1.RangeDigest(6, encode), s2.RangeDigest(6, encode)
+	ranges := d1.Diff(d2)
+	if len(ranges) == 0 {
+		t.Fatal("expected some differing ranges")
+	}
+
+	// applying the exchanged elements reconciles the two replicas
+	for _, p := range s2.RangeItems(6, ranges, encode) {
+		s1.Add(p)
+	}
+	for _, p := range s1.RangeItems(6, ranges, encode) {
+		s2.Add(p)
+	}
+	if !s1.IsEqual(s2) {
+		t.Errorf("expected the replicas to be reconciled, got %v and %v", s1.items, s2.items)
+	}
+
+	if d1, d2 := s1.RangeDigest(6, encode), s2.RangeDigest(6, encode); d1.Diff(d2) != nil {
+		t.Errorf("expected no differing ranges after reconciliation")
+	}
+}
+
+func TestRangeDigestMarshalBinary(t *testing.T) {
+	local := From(1, 2, 3, 4, 5).RangeDigest(8)
+	data, err := From(1, 2, 4, 5, 6).RangeDigest(8).MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var remote RangeDigest
+	if err := remote.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+
+	if remote.Levels() != 8 {
+		t.Fatalf("expected 8 levels, got %d", remote.Levels())
+	}
+	if ranges := local.Diff(&remote); len(ranges) == 0 || !slices.Equal(ranges, remote.Diff(local)) {
+		t.Errorf("expected the same non-empty differing ranges, got %v and %v", ranges, remote.Diff(local))
+	}
+	if ranges := From(1, 2, 4, 5, 6).RangeDigest(8).Diff(&remote); ranges != nil {
+		t.Errorf("expected no differing ranges with the original digest, got %v", ranges)
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		cases := [][]byte{
+			nil,
+			{0},
+			{maxDigestLevels + 1},
+			data[:len(data)-1],
+			append(slices.Clone(data), 0),
+		}
+
+		for i, data := range cases {
+			t.Run(fmt.Sprintf("Case_%d", i), func(t *testing.T) {
+				var d RangeDigest
+				if err := d.UnmarshalBinary(data); !errors.Is(err, ErrInvalidFormat) {
+					t.Errorf("expected error %v, got %v", ErrInvalidFormat, err)
+				}
+			})
+		}
+	})
+}
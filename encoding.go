@@ -0,0 +1,44 @@
+package smallset
+
+import (
+	"cmp"
+	"encoding/binary"
+	"math"
+	"reflect"
+)
+
+// appendOrdered appends the canonical binary encoding of e to dst, and returns the extended slice.
+//   - integers are encoded as 8 bytes in big-endian order.
+//   - floats are encoded as the 8 bytes of their IEEE 754 representation, with -0 normalized to 0
+//     and all NaNs normalized to the same NaN.
+//   - strings are encoded as their uvarint length followed by their bytes.
+//
+// Equal elements always have the same encoding.
+func appendOrdered[T cmp.Ordered](dst []byte, e T) []byte {
+	v := reflect.ValueOf(e)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return binary.BigEndian.AppendUint64(dst, uint64(v.Int()))
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return binary.BigEndian.AppendUint64(dst, v.Uint())
+
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+		switch {
+		case f == 0:
+			f = 0
+		case math.IsNaN(f):
+			f = math.NaN()
+		}
+		return binary.BigEndian.AppendUint64(dst, math.Float64bits(f))
+
+	case reflect.String:
+		s := v.String()
+		dst = binary.AppendUvarint(dst, uint64(len(s)))
+		return append(dst, s...)
+
+	default:
+		panic("smallset.appendOrdered: unsupported kind " + v.Kind().String())
+	}
+}
@@ -0,0 +1,143 @@
+package smallset
+
+import (
+	"slices"
+	"time"
+)
+
+// Expiring is a slice-based set whose elements expire after a deadline.
+// Elements are sorted by deadline, so the expired ones can be purged with a single
+// binary search and one deletion at the front of the slice.
+// Expired elements are purged lazily on access, or explicitly by calling [Expiring.Expire].
+//
+// Lookups are O(N), so it's meant for small collections (< 1000) of short-lived elements
+// like tokens or nonces, where it replaces the usual combination of a set plus a heap.
+// Not safe for concurrent use.
+type Expiring[T comparable] struct {
+	entries []expiringEntry[T]
+	now     func() time.Time
+}
+
+type expiringEntry[T comparable] struct {
+	item     T
+	deadline time.Time
+}
+
+// NewExpiring returns an initialized expiring set with the provided capacity.
+// It panics if the capacity is <= 0.
+func NewExpiring[T comparable](capacity int) *Expiring[T] {
+	if capacity <= 0 {
+		panic("smallset.NewExpiring: capacity must be > 0")
+	}
+
+	return &Expiring[T]{
+		entries: make([]expiringEntry[T], 0, capacity),
+		now:     time.Now,
+	}
+}
+
+// Size returns the number of elements in the set that are not expired.
+func (s *Expiring[T]) Size() int {
+	s.purge()
+	return len(s.entries)
+}
+
+// IsEmpty returns whether the set has no elements that are not expired.
+func (s *Expiring[T]) IsEmpty() bool {
+	return s.Size() == 0
+}
+
+// Clear removes all elements from the set.
+func (s *Expiring[T]) Clear() {
+	clear(s.entries)
+	s.entries = s.entries[:0]
+}
+
+// Items returns the elements of the set that are not expired, sorted by deadline.
+func (s *Expiring[T]) Items() []T {
+	s.purge()
+	items := make([]T, len(s.entries))
+	for i, entry := range s.entries {
+		items[i] = entry.item
+	}
+	return items
+}
+
+// Contains returns whether the element is in the set and not expired. Operation is O(N).
+func (s *Expiring[T]) Contains(e T) bool {
+	s.purge()
+	return s.index(e) != -1
+}
+
+// Deadline returns the deadline of the element, and whether it's in the set and not expired.
+func (s *Expiring[T]) Deadline(e T) (time.Time, bool) {
+	s.purge()
+	i := s.index(e)
+	if i == -1 {
+		return time.Time{}, false
+	}
+	return s.entries[i].deadline, true
+}
+
+// Add an element that expires at the deadline, and returns whether it was added (true),
+// or was already present (false). In the latter case, the deadline of the element is updated.
+func (s *Expiring[T]) Add(e T, deadline time.Time) bool {
+	s.purge()
+	added := true
+	if i := s.index(e); i != -1 {
+		s.entries = slices.Delete(s.entries, i, i+1)
+		added = false
+	}
+
+	i := s.expiredUntil(deadline)
+	s.entries = slices.Insert(s.entries, i, expiringEntry[T]{item: e, deadline: deadline})
+	return added
+}
+
+// Remove an element if present, and returns whether it was removed (true), or was never present (false).
+func (s *Expiring[T]) Remove(e T) bool {
+	s.purge()
+	i := s.index(e)
+	if i == -1 {
+		return false
+	}
+
+	s.entries = slices.Delete(s.entries, i, i+1)
+	return true
+}
+
+// Expire removes all elements whose deadline is not after now. Returns num removed.
+func (s *Expiring[T]) Expire(now time.Time) int {
+	end := s.expiredUntil(now)
+	if end == 0 {
+		return 0
+	}
+
+	s.entries = slices.Delete(s.entries, 0, end)
+	return end
+}
+
+// purge removes the elements that are expired according to the set clock.
+func (s *Expiring[T]) purge() {
+	if len(s.entries) > 0 {
+		s.Expire(s.now())
+	}
+}
+
+// index returns the index of the element, or -1 if not present.
+func (s *Expiring[T]) index(e T) int {
+	return slices.IndexFunc(s.entries, func(entry expiringEntry[T]) bool {
+		return entry.item == e
+	})
+}
+
+// expiredUntil returns the index of the first element whose deadline is after t.
+func (s *Expiring[T]) expiredUntil(t time.Time) int {
+	i, _ := slices.BinarySearchFunc(s.entries, t, func(entry expiringEntry[T], t time.Time) int {
+		if entry.deadline.After(t) {
+			return 1
+		}
+		return -1
+	})
+	return i
+}
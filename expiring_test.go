@@ -0,0 +1,108 @@
+package smallset
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+)
+
+var epoch = time.Unix(1_000_000, 0)
+
+func at(seconds int) time.Time {
+	return epoch.Add(time.Duration(seconds) * time.Second)
+}
+
+func TestExpiringAdd(t *testing.T) {
+	s := NewExpiring[string](10)
+	s.now = func() time.Time { return epoch }
+
+	cases := []struct {
+		element  string
+		deadline time.Time
+		expected bool
+		items    []string
+	}{
+		{element: "a", deadline: at(10), expected: true, items: []string{"a"}},
+		{element: "b", deadline: at(5), expected: true, items: []string{"b", "a"}},
+		{element: "c", deadline: at(5), expected: true, items: []string{"b", "c", "a"}},
+		{element: "b", deadline: at(20), expected: false, items: []string{"c", "a", "b"}},
+	}
+
+	for i, test := range cases {
+		t.Run(fmt.Sprintf("Case_%d", i), func(t *testing.T) {
+			if res := s.Add(test.element, test.deadline); res != test.expected {
+				t.Errorf("Add(%s) expected %t got %t", test.element, test.expected, res)
+			}
+
+			if items := s.Items(); !slices.Equal(items, test.items) {
+				t.Errorf("Items mismatch.\nExpected: %v\nActual: %v", test.items, items)
+			}
+		})
+	}
+}
+
+func TestExpiringExpire(t *testing.T) {
+	cases := []struct {
+		now      time.Time
+		expected int
+		items    []string
+	}{
+		{now: at(0), expected: 0, items: []string{"a", "b", "c"}},
+		{now: at(1), expected: 1, items: []string{"b", "c"}},
+		{now: at(2), expected: 2, items: []string{"c"}},
+		{now: at(10), expected: 3, items: []string{}},
+	}
+
+	for i, test := range cases {
+		t.Run(fmt.Sprintf("Case_%d", i), func(t *testing.T) {
+			s := NewExpiring[string](10)
+			s.now = func() time.Time { return epoch }
+			s.Add("a", at(1))
+			s.Add("b", at(2))
+			s.Add("c", at(3))
+
+			if res := s.Expire(test.now); res != test.expected {
+				t.Errorf("Expire expected %d got %d", test.expected, res)
+			}
+
+			if items := s.Items(); !slices.Equal(items, test.items) {
+				t.Errorf("Items mismatch.\nExpected: %v\nActual: %v", test.items, items)
+			}
+		})
+	}
+}
+
+func TestExpiringLazyPurge(t *testing.T) {
+	now := epoch
+	s := NewExpiring[int](10)
+	s.now = func() time.Time { return now }
+
+	s.Add(1, at(5))
+	s.Add(2, at(10))
+
+	if !s.Contains(1) || !s.Contains(2) {
+		t.Fatalf("expected both elements to be present")
+	}
+
+	now = at(5)
+	if s.Contains(1) {
+		t.Errorf("expected 1 to be expired")
+	}
+	if size := s.Size(); size != 1 {
+		t.Errorf("expected size 1, got %d", size)
+	}
+
+	deadline, found := s.Deadline(2)
+	if !found || !deadline.Equal(at(10)) {
+		t.Errorf("Deadline(2) expected (%v, true), got (%v, %t)", at(10), deadline, found)
+	}
+
+	now = at(11)
+	if !s.IsEmpty() {
+		t.Errorf("expected set to be empty, got %v", s.Items())
+	}
+	if s.Remove(2) {
+		t.Errorf("expected Remove(2) to be false after expiration")
+	}
+}
@@ -0,0 +1,60 @@
+package smallset
+
+import (
+	"cmp"
+	"fmt"
+	"iter"
+)
+
+// DiffReport describes how two sets differ, as returned by [ExplainDiff] and [ExplainDiffCustom].
+type DiffReport[T any] struct {
+	OnlyInA []T
+	OnlyInB []T
+}
+
+// IsEqual returns whether the two sets are equal, that is there are no differences.
+func (d DiffReport[T]) IsEqual() bool {
+	return len(d.OnlyInA) == 0 && len(d.OnlyInB) == 0
+}
+
+// String returns a human readable description of the differences, meant for test failures.
+func (d DiffReport[T]) String() string {
+	if d.IsEqual() {
+		return "sets are equal"
+	}
+	return fmt.Sprintf("sets differ: only in a %v, only in b %v", d.OnlyInA, d.OnlyInB)
+}
+
+// ExplainDiff returns the elements that are only in a and only in b, in ascending order.
+// It's meant for tests, where reporting why two sets are not equal beats dumping both of them.
+//
+//	if diff := smallset.ExplainDiff(got, want); !diff.IsEqual() {
+//		t.Error(diff)
+//	}
+//
+// O(N+M) complexity.
+func ExplainDiff[T cmp.Ordered](a, b *Ordered[T]) DiffReport[T] {
+	return explainDiff(MergeJoin(a, b))
+}
+
+// ExplainDiffCustom returns the elements that are only in a and only in b, in ascending order.
+// It's meant for tests, where reporting why two sets are not equal beats dumping both of them.
+// O(N+M) complexity.
+//
+// a and b must use the same (or equivalent) comparison functions.
+func ExplainDiffCustom[T any](a, b *Custom[T]) DiffReport[T] {
+	return explainDiff(MergeJoinCustom(a, b))
+}
+
+func explainDiff[T any](joins iter.Seq[Joined[T]]) DiffReport[T] {
+	var report DiffReport[T]
+	for j := range joins {
+		switch {
+		case !j.InB:
+			report.OnlyInA = append(report.OnlyInA, j.Value)
+		case !j.InA:
+			report.OnlyInB = append(report.OnlyInB, j.Value)
+		}
+	}
+	return report
+}
@@ -0,0 +1,44 @@
+package smallset
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestExplainDiff(t *testing.T) {
+	cases := []struct {
+		a, b     []int
+		onlyInA  []int
+		onlyInB  []int
+		expected string
+	}{
+		{a: []int{1, 2}, b: []int{2, 1}, expected: "sets are equal"},
+		{a: []int{}, b: []int{}, expected: "sets are equal"},
+		{a: []int{1, 2, 3}, b: []int{2, 4}, onlyInA: []int{1, 3}, onlyInB: []int{4}, expected: "sets differ: only in a [1 3], only in b [4]"},
+		{a: []int{}, b: []int{5}, onlyInB: []int{5}, expected: "sets differ: only in a [], only in b [5]"},
+	}
+
+	for i, test := range cases {
+		t.Run(fmt.Sprintf("Case_%d", i), func(t *testing.T) {
+			diff := ExplainDiff(From(test.a...), From(test.b...))
+			if !slices.Equal(diff.OnlyInA, test.onlyInA) || !slices.Equal(diff.OnlyInB, test.onlyInB) {
+				t.Errorf("Expected %v and %v, got %v and %v", test.onlyInA, test.onlyInB, diff.OnlyInA, diff.OnlyInB)
+			}
+			if diff.String() != test.expected {
+				t.Errorf("Expected %q, got %q", test.expected, diff.String())
+			}
+		})
+	}
+}
+
+func TestExplainDiffCustom(t *testing.T) {
+	a := CustomFrom(PersonCmp, people2...)
+	b := a.Clone()
+	b.Remove(Person{ID: 30})
+
+	diff := ExplainDiffCustom(a, b)
+	if len(diff.OnlyInA) != 1 || diff.OnlyInA[0].ID != 30 || len(diff.OnlyInB) != 0 {
+		t.Errorf("unexpected diff %v", diff)
+	}
+}
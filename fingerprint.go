@@ -0,0 +1,75 @@
+package smallset
+
+import (
+	"cmp"
+	"encoding/binary"
+	"hash/maphash"
+)
+
+// fingerprintSeed is the seed used to hash the elements of [Ordered] sets, shared by all sets
+// so that equal sets have equal fingerprints within the same process.
+var fingerprintSeed = maphash.MakeSeed()
+
+// fingerprint is an order-independent hash of the elements of a set, maintained incrementally
+// by adding the hash of every inserted element and subtracting the hash of every removed one.
+type fingerprint[T any] struct {
+	sum  uint64
+	hash func(T) uint64
+}
+
+func newFingerprint[T any](hash func(T) uint64, items []T) *fingerprint[T] {
+	f := &fingerprint[T]{hash: hash}
+	for _, e := range items {
+		f.add(e)
+	}
+	return f
+}
+
+func (f *fingerprint[T]) add(e T)    { f.sum += mix(f.hash(e)) }
+func (f *fingerprint[T]) remove(e T) { f.sum -= mix(f.hash(e)) }
+
+func (f *fingerprint[T]) clone() *fingerprint[T] {
+	if f == nil {
+		return nil
+	}
+	return &fingerprint[T]{sum: f.sum, hash: f.hash}
+}
+
+// mix is the finalizer of splitmix64, which spreads the bits of weak hashes (e.g. the identity)
+// so that their sums don't collide systematically.
+func mix(h uint64) uint64 {
+	h ^= h >> 30
+	h *= 0xbf58476d1ce4e5b9
+	h ^= h >> 27
+	h *= 0x94d049bb133111eb
+	h ^= h >> 31
+	return h
+}
+
+// hashOrdered returns the hash of e used by the fingerprint of [Ordered] sets.
+func hashOrdered[T cmp.Ordered](e T) uint64 {
+	return hashComparable(fingerprintSeed, e)
+}
+
+// hashComparable returns the hash of the canonical encoding of e with the seed,
+// which gives the same hash to all NaNs, and to 0 and -0.
+// The most common types skip the reflection of [appendOrdered].
+func hashComparable[T cmp.Ordered](seed maphash.Seed, e T) uint64 {
+	var buf [16]byte
+	switch v := any(e).(type) {
+	case int:
+		return maphash.Bytes(seed, binary.BigEndian.AppendUint64(buf[:0], uint64(v)))
+	case int32:
+		return maphash.Bytes(seed, binary.BigEndian.AppendUint64(buf[:0], uint64(v)))
+	case int64:
+		return maphash.Bytes(seed, binary.BigEndian.AppendUint64(buf[:0], uint64(v)))
+	case uint32:
+		return maphash.Bytes(seed, binary.BigEndian.AppendUint64(buf[:0], uint64(v)))
+	case uint64:
+		return maphash.Bytes(seed, binary.BigEndian.AppendUint64(buf[:0], v))
+	case string:
+		return maphash.String(seed, v)
+	default:
+		return maphash.Bytes(seed, appendOrdered(buf[:0], e))
+	}
+}
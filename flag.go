@@ -0,0 +1,70 @@
+package smallset
+
+import (
+	"cmp"
+	"strings"
+)
+
+// Flag is a [flag.Value], also implementing the Type method of pflag.Value, that accumulates
+// command line values into a set. Each value can be a comma-separated list, and the flag can be
+// repeated, so that -ids 3,1 -ids 2 produces the set {1, 2, 3}. Values are parsed like
+// [ReadCSVColumn] does, and [Flag.String] lists the elements in ascending order.
+//
+//	ids := smallset.New[int](10)
+//	flag.Var(smallset.NewFlag(ids), "ids", "comma-separated IDs")
+type Flag[T cmp.Ordered] struct {
+	set *Ordered[T]
+}
+
+// NewFlag returns a flag that adds the values to the provided set.
+// The elements already in the set act as defaults, and are shown in the usage message.
+// It panics if the set is nil.
+func NewFlag[T cmp.Ordered](set *Ordered[T]) *Flag[T] {
+	if set == nil {
+		panic("smallset.NewFlag: set cannot be nil")
+	}
+	return &Flag[T]{set: set}
+}
+
+// Set parses the comma-separated values, skipping empty ones, and adds them to the set.
+// Either all values are added, or none if one of them can't be parsed.
+func (f *Flag[T]) Set(value string) error {
+	var items []T
+	for _, v := range strings.Split(value, ",") {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+
+		e, err := parseOrdered[T](v)
+		if err != nil {
+			return err
+		}
+		items = append(items, e)
+	}
+
+	for _, e := range items {
+		f.set.Add(e)
+	}
+	return nil
+}
+
+// String returns the elements of the set separated by commas, in ascending order.
+func (f *Flag[T]) String() string {
+	if f == nil || f.set == nil {
+		// the flag package calls String on zero values to detect the default
+		return ""
+	}
+
+	values := make([]string, f.set.Size())
+	for i, e := range f.set.Ascend() {
+		values[i] = formatOrdered(e)
+	}
+	return strings.Join(values, ",")
+}
+
+// Type returns the name of the type of the values for the usage message, like "ints",
+// as required by pflag.Value.
+func (f *Flag[T]) Type() string {
+	return reflectKind[T]().String() + "s"
+}
@@ -0,0 +1,57 @@
+package smallset
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"testing"
+)
+
+var _ flag.Value = (*Flag[int])(nil)
+
+func TestFlag(t *testing.T) {
+	cases := []struct {
+		args     []string
+		expected []int
+		err      bool
+	}{
+		{args: nil, expected: []int{7}},
+		{args: []string{"-ids", "3,1"}, expected: []int{1, 3, 7}},
+		{args: []string{"-ids", "3, 1,,", "-ids", "2", "-ids", "3"}, expected: []int{1, 2, 3, 7}},
+		{args: []string{"-ids", "1,x"}, expected: []int{7}, err: true},
+	}
+
+	for i, test := range cases {
+		t.Run(fmt.Sprintf("Case_%d", i), func(t *testing.T) {
+			ids := From(7)
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+			fs.Var(NewFlag(ids), "ids", "IDs")
+
+			err := fs.Parse(test.args)
+			if (err != nil) != test.err {
+				t.Fatalf("expected error %v, got %v", test.err, err)
+			}
+
+			if !slices.Equal(ids.items, test.expected) {
+				t.Errorf("Expected %v, got %v", test.expected, ids.items)
+			}
+		})
+	}
+}
+
+func TestFlagString(t *testing.T) {
+	f := NewFlag(From("b", "a"))
+	if f.String() != "a,b" {
+		t.Errorf("expected \"a,b\", got %q", f.String())
+	}
+	if f.Type() != "strings" {
+		t.Errorf("expected type \"strings\", got %q", f.Type())
+	}
+
+	var zero *Flag[string]
+	if zero.String() != "" {
+		t.Errorf("expected the zero flag to be empty, got %q", zero.String())
+	}
+}
@@ -0,0 +1,278 @@
+package smallset
+
+import (
+	"encoding/binary"
+	"iter"
+	"slices"
+	"sort"
+)
+
+// frontCodedBlock is the number of strings in each block of a [FrontCodedStorage] filled in order.
+// The first string of every block is stored in full, to allow binary searching the blocks.
+// Blocks grow with the insertions until they are split in two at twice this size.
+const frontCodedBlock = 16
+
+// FrontCodedStorage is a [Storage] of strings with front coding: each string is encoded
+// as the length of the prefix it shares with the previous string, followed by the rest of it.
+// Since adjacent strings in sorted order tend to share long prefixes, like URLs or file paths,
+// it takes a fraction of the memory of a []string, at the cost of slower lookups.
+// Insertions and removals re-encode the affected blocks, but shift the bytes after them.
+//
+// Search ignores the compare function and uses the byte order of the strings, so the storage can
+// only back sets in ascending byte order, like the ones returned by [NewFrontCoded].
+type FrontCodedStorage struct {
+	// data is the sequence of the encoded strings, each made of the uvarint length of the
+	// prefix shared with the previous string, the uvarint length of the suffix and the suffix.
+	data []byte
+
+	// blocks are the offsets in data of the first string of each block, which shares no prefix,
+	// counts are the number of strings in each block, and starts the index of their first string.
+	blocks []int
+	counts []int
+	starts []int
+	size   int
+}
+
+// NewFrontCoded returns an empty set of strings backed by a [FrontCodedStorage].
+// See [NewStored] for which methods go straight to the storage, and access the storage
+// with [Ordered.Storage], e.g. for its MemoryUsage.
+func NewFrontCoded() *Ordered[string] {
+	return NewStored[string](&FrontCodedStorage{})
+}
+
+func (f *FrontCodedStorage) Len() int {
+	return f.size
+}
+
+// MemoryUsage returns the number of bytes used to store the strings.
+func (f *FrontCodedStorage) MemoryUsage() int {
+	return len(f.data)
+}
+
+// At decodes the strings of the block of index i up to it.
+func (f *FrontCodedStorage) At(i int) string {
+	b := f.blockOf(i)
+	pos := f.blocks[b]
+	var buf []byte
+	for range i - f.starts[b] + 1 {
+		buf, pos = f.next(buf, pos)
+	}
+	return string(buf)
+}
+
+// Search performs a binary search over the first strings of the blocks, and decodes the strings
+// of the block that may contain e, without allocating for strings up to 64 bytes.
+// The cmp function is ignored.
+// Operation is O(log(N)) plus the decoding of up to 32 strings.
+func (f *FrontCodedStorage) Search(e string, _ func(a, b string) int) (int, bool) {
+	b := f.find(e)
+	if b < 0 {
+		return 0, false
+	}
+
+	var arr [64]byte
+	buf, pos := arr[:0], f.blocks[b]
+	for i := range f.counts[b] {
+		buf, pos = f.next(buf, pos)
+		if string(buf) == e {
+			return f.starts[b] + i, true
+		}
+		if string(buf) > e {
+			return f.starts[b] + i, false
+		}
+	}
+	return f.starts[b] + f.counts[b], false
+}
+
+// Insert re-encodes the block of the element before index i, or the first block if i is 0,
+// splitting it if it grows past twice the block size.
+func (f *FrontCodedStorage) Insert(i int, e string) {
+	if f.size == 0 {
+		f.replace(0, 0, []string{e})
+		return
+	}
+
+	b := 0
+	if i > 0 {
+		b = f.blockOf(i - 1)
+	}
+
+	block := slices.Insert(f.decode(b), i-f.starts[b], e)
+	if len(block) > 2*frontCodedBlock {
+		half := len(block) / 2
+		f.replace(b, b+1, block[:half], block[half:])
+		return
+	}
+	f.replace(b, b+1, block)
+}
+
+// DeleteRange drops the blocks in the range and re-encodes the blocks at its ends as one block,
+// which is merged with the next one if it's left with less than half the block size.
+func (f *FrontCodedStorage) DeleteRange(i, j int) {
+	if i >= j {
+		return
+	}
+
+	first, last := f.blockOf(i), f.blockOf(j-1)
+	block := f.decode(first)[:i-f.starts[first]]
+	block = append(block, f.decode(last)[j-f.starts[last]:]...)
+
+	end := last + 1
+	if len(block) < frontCodedBlock/2 && end < len(f.blocks) {
+		block = append(block, f.decode(end)...)
+		end++
+	}
+
+	switch {
+	case len(block) == 0:
+		f.replace(first, end)
+	case len(block) > 2*frontCodedBlock:
+		half := len(block) / 2
+		f.replace(first, end, block[:half], block[half:])
+	default:
+		f.replace(first, end, block)
+	}
+}
+
+func (f *FrontCodedStorage) All() iter.Seq2[int, string] {
+	return func(yield func(int, string) bool) {
+		var buf []byte
+		pos := 0
+		for i := range f.size {
+			buf, pos = f.next(buf, pos)
+			if !yield(i, string(buf)) {
+				return
+			}
+		}
+	}
+}
+
+// Clone returns a deep copy of the storage.
+func (f *FrontCodedStorage) Clone() Storage[string] {
+	return &FrontCodedStorage{
+		data:   slices.Clone(f.data),
+		blocks: slices.Clone(f.blocks),
+		counts: slices.Clone(f.counts),
+		starts: slices.Clone(f.starts),
+		size:   f.size,
+	}
+}
+
+// find returns the index of the last block whose first string is <= e, or -1 if there is none.
+// The first strings are compared in place, without allocating.
+func (f *FrontCodedStorage) find(e string) int {
+	lo, hi := 0, len(f.blocks)
+	for lo < hi {
+		mid := int(uint(lo+hi) >> 1)
+		if string(f.head(mid)) <= e {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	return lo - 1
+}
+
+// blockOf returns the index of the block of the string at index i.
+func (f *FrontCodedStorage) blockOf(i int) int {
+	return sort.Search(len(f.starts), func(b int) bool { return f.starts[b] > i }) - 1
+}
+
+// head returns the encoding of the first string of block b, which is stored in full.
+func (f *FrontCodedStorage) head(b int) []byte {
+	pos := f.blocks[b]
+	_, n := binary.Uvarint(f.data[pos:])
+	pos += n
+	length, n := binary.Uvarint(f.data[pos:])
+	pos += n
+	return f.data[pos : pos+int(length)]
+}
+
+// next decodes the string at offset pos into buf, which must hold the previous string,
+// and returns it with the offset of the following one.
+func (f *FrontCodedStorage) next(buf []byte, pos int) ([]byte, int) {
+	shared, n := binary.Uvarint(f.data[pos:])
+	pos += n
+	length, n := binary.Uvarint(f.data[pos:])
+	pos += n
+
+	buf = append(buf[:shared], f.data[pos:pos+int(length)]...)
+	return buf, pos + int(length)
+}
+
+// decode returns the strings of block b.
+func (f *FrontCodedStorage) decode(b int) []string {
+	block := make([]string, 0, f.counts[b]+1)
+	var buf []byte
+	pos := f.blocks[b]
+	for range f.counts[b] {
+		buf, pos = f.next(buf, pos)
+		block = append(block, string(buf))
+	}
+	return block
+}
+
+// replace replaces the blocks in [b, end) with the encodings of the provided blocks, which may be
+// none, shifting the bytes and the offsets of the blocks after them.
+func (f *FrontCodedStorage) replace(b, end int, blocks ...[]string) {
+	start, stop := len(f.data), len(f.data)
+	if b < len(f.blocks) {
+		start = f.blocks[b]
+	}
+	if end < len(f.blocks) {
+		stop = f.blocks[end]
+	}
+
+	var encoded []byte
+	offsets := make([]int, len(blocks))
+	counts := make([]int, len(blocks))
+	for i, block := range blocks {
+		offsets[i] = start + len(encoded)
+		counts[i] = len(block)
+		encoded = appendBlock(encoded, block)
+	}
+
+	f.data = slices.Replace(f.data, start, stop, encoded...)
+	shift := len(encoded) - (stop - start)
+	for i := end; i < len(f.blocks); i++ {
+		f.blocks[i] += shift
+	}
+
+	f.blocks = slices.Replace(f.blocks, b, end, offsets...)
+	f.counts = slices.Replace(f.counts, b, end, counts...)
+
+	// recompute the starts of the blocks from b onwards
+	f.starts = f.starts[:b]
+	f.size = 0
+	if b > 0 {
+		f.size = f.starts[b-1] + f.counts[b-1]
+	}
+	for _, count := range f.counts[b:] {
+		f.starts = append(f.starts, f.size)
+		f.size += count
+	}
+}
+
+// appendBlock appends to dst the front coded block of the sorted strings.
+func appendBlock(dst []byte, block []string) []byte {
+	prev := ""
+	for _, e := range block {
+		shared := sharedPrefix(prev, e)
+		dst = binary.AppendUvarint(dst, uint64(shared))
+		dst = binary.AppendUvarint(dst, uint64(len(e)-shared))
+		dst = append(dst, e[shared:]...)
+		prev = e
+	}
+	return dst
+}
+
+// sharedPrefix returns the length in bytes of the longest common prefix of a and b.
+func sharedPrefix(a, b string) int {
+	n := min(len(a), len(b))
+	for i := range n {
+		if a[i] != b[i] {
+			return i
+		}
+	}
+	return n
+}
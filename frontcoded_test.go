@@ -0,0 +1,166 @@
+package smallset
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+// frontCodedOf returns the storage of a set created with [NewFrontCoded].
+func frontCodedOf(s *Ordered[string]) *FrontCodedStorage {
+	return s.Storage().(*FrontCodedStorage)
+}
+
+func profile(i int) string {
+	return fmt.Sprintf("https://example.com/users/%03d/profile", i)
+}
+
+func TestFrontCoded(t *testing.T) {
+	cases := [][]string{
+		{},
+		{""},
+		{"a", "ab", "abc", "b"},
+	}
+
+	var paths []string
+	for i := range 100 {
+		paths = append(paths, profile(i))
+	}
+	cases = append(cases, paths)
+
+	for i, items := range cases {
+		t.Run(fmt.Sprintf("Case_%d", i), func(t *testing.T) {
+			s := From(items...)
+			f := NewFrontCoded()
+			for _, e := range slices.Backward(items) {
+				f.Add(e)
+			}
+
+			if f.Size() != s.Size() {
+				t.Errorf("expected size %d, got %d", s.Size(), f.Size())
+			}
+			if res := f.Items(); !slices.Equal(res, s.items) {
+				t.Errorf("Items mismatch.\nExpected: %v\nActual: %v", s.items, res)
+			}
+			if !f.IsEqual(s) {
+				t.Errorf("expected the sets to be equal")
+			}
+
+			for i, e := range items {
+				if !f.Contains(e) {
+					t.Errorf("expected %q to be in the set", e)
+				}
+				if f.Contains(e+"x") != s.Contains(e+"x") {
+					t.Errorf("Contains(%q) expected %t", e+"x", s.Contains(e+"x"))
+				}
+				if f.At(i) != e {
+					t.Errorf("At(%d) expected %q, got %q", i, e, f.At(i))
+				}
+			}
+		})
+	}
+}
+
+func TestFrontCodedAddRemove(t *testing.T) {
+	f := NewFrontCoded()
+	expected := New[string](100)
+
+	rng := rand.New(rand.NewPCG(1, 2))
+	for step := range 2000 {
+		i := rng.IntN(300)
+		e := profile(i)
+		switch rng.IntN(6) {
+		case 0, 1:
+			if f.Remove(e) != expected.Remove(e) {
+				t.Fatalf("step %d: Remove(%q) mismatch", step, e)
+			}
+		case 2:
+			end := profile(i + rng.IntN(60))
+			if f.RemoveBetween(e, end) != expected.RemoveBetween(e, end) {
+				t.Fatalf("step %d: RemoveBetween(%q, %q) mismatch", step, e, end)
+			}
+		default:
+			if f.Add(e) != expected.Add(e) {
+				t.Fatalf("step %d: Add(%q) mismatch", step, e)
+			}
+		}
+
+		if f.Size() != expected.Size() {
+			t.Fatalf("step %d: expected size %d, got %d", step, expected.Size(), f.Size())
+		}
+		if f.Contains(e) != expected.Contains(e) {
+			t.Fatalf("step %d: Contains(%q) expected %t", step, e, expected.Contains(e))
+		}
+	}
+
+	if res := f.Items(); !slices.Equal(res, expected.items) {
+		t.Fatalf("Items mismatch.\nExpected: %v\nActual: %v", expected.items, res)
+	}
+	for i, e := range f.Ascend() {
+		if e != expected.items[i] {
+			t.Fatalf("Ascend mismatch at %d: expected %q, got %q", i, expected.items[i], e)
+		}
+	}
+
+	storage := frontCodedOf(f)
+	if len(storage.blocks) < expected.Size()/(2*frontCodedBlock) {
+		t.Errorf("expected the blocks to be split, got %d blocks for %d elements", len(storage.blocks), expected.Size())
+	}
+
+	for _, e := range expected.Items() {
+		f.Remove(e)
+	}
+	if !f.IsEmpty() || len(storage.data) != 0 || len(storage.blocks) != 0 {
+		t.Errorf("expected an empty set, got %d elements in %d bytes", f.Size(), len(storage.data))
+	}
+}
+
+func TestFrontCodedSetAlgebra(t *testing.T) {
+	s := NewFrontCoded()
+	other := New[string](50)
+	for i := range 100 {
+		s.Add(profile(i))
+		if i%2 == 0 {
+			other.Add(profile(i))
+		}
+	}
+
+	if inter := s.Intersect(other); !inter.IsEqual(other) {
+		t.Errorf("unexpected intersection %v", inter.Items())
+	}
+	if diff := s.Difference(other); diff.Size() != 50 || diff.Contains(profile(0)) {
+		t.Errorf("unexpected difference %v", diff.Items())
+	}
+
+	clone := s.Clone()
+	clone.Remove(profile(0))
+	if !s.Contains(profile(0)) || clone.Contains(profile(0)) {
+		t.Errorf("expected the clone to be independent of the set")
+	}
+}
+
+func TestFrontCodedMemory(t *testing.T) {
+	s := NewFrontCoded()
+	size := 0
+	for i := range 100 {
+		e := profile(i)
+		s.Add(e)
+		size += len(e)
+	}
+
+	if usage := frontCodedOf(s).MemoryUsage(); usage >= size/2 {
+		t.Errorf("expected front coding to at least halve the memory, got %d bytes from %d", usage, size)
+	}
+
+	for _, e := range []string{"", "a", profile(100), "zzz"} {
+		if s.Contains(e) {
+			t.Errorf("expected %q not to be in the set", e)
+		}
+	}
+
+	e := profile(50)
+	if allocs := testing.AllocsPerRun(100, func() { s.Contains(e) }); allocs != 0 {
+		t.Errorf("expected Contains not to allocate, got %v allocations", allocs)
+	}
+}
@@ -0,0 +1,249 @@
+package smallset
+
+import (
+	"cmp"
+	"iter"
+	"slices"
+)
+
+// Gapped is a sorted set stored in a gap buffer: a slice with a gap of free slots that is moved
+// to the position of every insertion or removal. Moving the gap only shifts the elements between
+// its old and new position, so workloads that edit nearby positions, like appending recent
+// timestamps or inserting clustered keys, shift far fewer elements than with an [Ordered] set.
+// Random insertions cost about the same as in an [Ordered] set.
+// It implements [SetOf]. Not safe for concurrent use.
+type Gapped[T any] struct {
+	// buf holds the elements in buf[:gapStart] and buf[gapEnd:], and the gap in between.
+	buf      []T
+	gapStart int
+	gapEnd   int
+	cmp      compareFunc[T]
+}
+
+// NewGapped returns an initialized gap buffer set for ordered types with the provided capacity.
+// It panics if the capacity is <= 0.
+func NewGapped[T cmp.Ordered](capacity int) *Gapped[T] {
+	if capacity <= 0 {
+		panic("smallset.NewGapped: capacity must be > 0")
+	}
+	return &Gapped[T]{buf: make([]T, capacity), gapEnd: capacity, cmp: cmp.Compare[T]}
+}
+
+// NewGappedCustom returns an initialized gap buffer set with the provided compare function and capacity.
+// It panics if cmp is nil or the capacity is <= 0.
+func NewGappedCustom[T any](cmp func(a, b T) int, capacity int) *Gapped[T] {
+	if cmp == nil {
+		panic("smallset.NewGappedCustom: cmp cannot be nil")
+	}
+	if capacity <= 0 {
+		panic("smallset.NewGappedCustom: capacity must be > 0")
+	}
+	return &Gapped[T]{buf: make([]T, capacity), gapEnd: capacity, cmp: cmp}
+}
+
+// Size returns the number of elements in the set.
+func (s *Gapped[T]) Size() int {
+	return len(s.buf) - (s.gapEnd - s.gapStart)
+}
+
+// IsEmpty returns whether the set has no elements.
+func (s *Gapped[T]) IsEmpty() bool {
+	return s.Size() == 0
+}
+
+// Clear removes all elements from the set, preserving its capacity.
+func (s *Gapped[T]) Clear() {
+	clear(s.buf)
+	s.gapStart = 0
+	s.gapEnd = len(s.buf)
+}
+
+// Items returns the elements of the set in ascending order.
+func (s *Gapped[T]) Items() []T {
+	items := make([]T, 0, s.Size())
+	items = append(items, s.buf[:s.gapStart]...)
+	return append(items, s.buf[s.gapEnd:]...)
+}
+
+// Ascend returns an iterator over the set in ascending order.
+func (s *Gapped[T]) Ascend() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for i, e := range s.buf[:s.gapStart] {
+			if !yield(i, e) {
+				return
+			}
+		}
+		for i, e := range s.buf[s.gapEnd:] {
+			if !yield(s.gapStart+i, e) {
+				return
+			}
+		}
+	}
+}
+
+// Contains returns whether the element is in the set. Operation is O(log(N)).
+func (s *Gapped[T]) Contains(e T) bool {
+	_, found := s.find(e)
+	return found
+}
+
+// Add an element and returns whether is was added (true), or was already present (false).
+// Operation is O(log(N) + D), where D is the distance from the previous edit.
+func (s *Gapped[T]) Add(e T) bool {
+	i, found := s.find(e)
+	if found {
+		return false
+	}
+
+	if s.gapStart == s.gapEnd {
+		s.grow()
+	}
+
+	s.moveGap(i)
+	s.buf[s.gapStart] = e
+	s.gapStart++
+	return true
+}
+
+// Remove an element if present, and returns whether is was removed (true), or was never present (false).
+// Operation is O(log(N) + D), where D is the distance from the previous edit.
+func (s *Gapped[T]) Remove(e T) bool {
+	i, found := s.find(e)
+	if !found {
+		return false
+	}
+
+	s.delete(i, i+1)
+	return true
+}
+
+// RemoveBefore removes all elements e such that e < max. Returns num removed.
+func (s *Gapped[T]) RemoveBefore(max T) int {
+	end, _ := s.find(max)
+	s.delete(0, end)
+	return end
+}
+
+// RemoveFrom removes all elements e such that e >= min. Returns num removed.
+func (s *Gapped[T]) RemoveFrom(min T) int {
+	start, _ := s.find(min)
+	size := s.Size()
+	s.delete(start, size)
+	return size - start
+}
+
+// RemoveBetween removes all elements e such that min <= e < max. Returns num removed.
+// Panics if max < min.
+func (s *Gapped[T]) RemoveBetween(min, max T) int {
+	if s.cmp.less(max, min) {
+		panic("smallset.Gapped.RemoveBetween: invalid range (max < min)")
+	}
+
+	start, _ := s.find(min)
+	end, _ := s.find(max)
+	s.delete(start, end)
+	return end - start
+}
+
+// Min returns the smallest element in the set, or panics if the set is empty.
+func (s *Gapped[T]) Min() T {
+	if s.IsEmpty() {
+		panic("smallset.Gapped.Min: set is empty")
+	}
+	return s.at(0)
+}
+
+// Max returns the biggest element in the set, or panics if the set is empty.
+func (s *Gapped[T]) Max() T {
+	if s.IsEmpty() {
+		panic("smallset.Gapped.Max: set is empty")
+	}
+	return s.at(s.Size() - 1)
+}
+
+// PopMin removes and returns the smallest element in the set, or panics if the set is empty.
+func (s *Gapped[T]) PopMin() T {
+	if s.IsEmpty() {
+		panic("smallset.Gapped.PopMin: set is empty")
+	}
+	e := s.at(0)
+	s.delete(0, 1)
+	return e
+}
+
+// PopMax removes and returns the biggest element in the set, or panics if the set is empty.
+func (s *Gapped[T]) PopMax() T {
+	if s.IsEmpty() {
+		panic("smallset.Gapped.PopMax: set is empty")
+	}
+	last := s.Size() - 1
+	e := s.at(last)
+	s.delete(last, last+1)
+	return e
+}
+
+// at returns the element at the logical index i.
+func (s *Gapped[T]) at(i int) T {
+	if i < s.gapStart {
+		return s.buf[i]
+	}
+	return s.buf[i+s.gapEnd-s.gapStart]
+}
+
+// find returns the logical index of the element, or the position where it would appear
+// in the sort order, and whether it's found.
+func (s *Gapped[T]) find(e T) (int, bool) {
+	left := s.buf[:s.gapStart]
+	if len(left) > 0 && !s.cmp.less(left[len(left)-1], e) {
+		return slices.BinarySearchFunc(left, e, s.cmp)
+	}
+
+	i, found := slices.BinarySearchFunc(s.buf[s.gapEnd:], e, s.cmp)
+	return s.gapStart + i, found
+}
+
+// delete removes the elements with logical index in [i, j), by moving the gap to i
+// and extending it over them.
+func (s *Gapped[T]) delete(i, j int) {
+	if i >= j {
+		return
+	}
+
+	s.moveGap(i)
+	clear(s.buf[s.gapEnd : s.gapEnd+j-i])
+	s.gapEnd += j - i
+}
+
+// moveGap moves the gap to start at the logical index i, shifting the elements in between.
+func (s *Gapped[T]) moveGap(i int) {
+	switch {
+	case i < s.gapStart:
+		// shift buf[i:gapStart] to the end of the gap
+		n := s.gapStart - i
+		copy(s.buf[s.gapEnd-n:s.gapEnd], s.buf[i:s.gapStart])
+		clear(s.buf[i:min(s.gapStart, s.gapEnd-n)])
+		s.gapStart -= n
+		s.gapEnd -= n
+
+	case i > s.gapStart:
+		// shift the first elements after the gap to its start
+		n := i - s.gapStart
+		copy(s.buf[s.gapStart:s.gapStart+n], s.buf[s.gapEnd:s.gapEnd+n])
+		clear(s.buf[max(s.gapEnd, s.gapStart+n) : s.gapEnd+n])
+		s.gapStart += n
+		s.gapEnd += n
+	}
+}
+
+// grow doubles the capacity of the buffer, keeping the gap at the same logical position.
+func (s *Gapped[T]) grow() {
+	size := s.Size()
+	buf := make([]T, max(2*len(s.buf), 1))
+	right := len(s.buf) - s.gapEnd
+
+	copy(buf, s.buf[:s.gapStart])
+	copy(buf[len(buf)-right:], s.buf[s.gapEnd:])
+
+	s.buf = buf
+	s.gapEnd = len(buf) - (size - s.gapStart)
+}
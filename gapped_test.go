@@ -0,0 +1,70 @@
+package smallset
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestGapped(t *testing.T) {
+	s := NewGapped[int](2)
+	for _, e := range []int{10, 20, 30, 15, 16, 17, 5, 40} {
+		s.Add(e)
+	}
+
+	expected := []int{5, 10, 15, 16, 17, 20, 30, 40}
+	if items := s.Items(); !slices.Equal(items, expected) {
+		t.Errorf("Items mismatch.\nExpected: %v\nActual: %v", expected, items)
+	}
+
+	if removed := s.RemoveBetween(11, 25); removed != 4 {
+		t.Errorf("RemoveBetween expected 4, got %d", removed)
+	}
+
+	// the gap is left at the position of the last edit
+	if s.gapStart != 2 {
+		t.Errorf("expected the gap to start at 2, got %d", s.gapStart)
+	}
+
+	expected = []int{5, 10, 30, 40}
+	if items := s.Items(); !slices.Equal(items, expected) {
+		t.Errorf("Items mismatch.\nExpected: %v\nActual: %v", expected, items)
+	}
+
+	for i, e := range s.Ascend() {
+		if e != expected[i] {
+			t.Errorf("Ascend expected %d at %d, got %d", expected[i], i, e)
+		}
+	}
+}
+
+func BenchmarkClusteredInsert(b *testing.B) {
+	const size = 10_000
+
+	// keys inserted in increasing order in the middle of the set, like recent timestamps
+	// arriving before a fixed set of future deadlines
+	b.Run("ordered", func(b *testing.B) {
+		b.ResetTimer()
+		for range b.N {
+			s := New[int](2 * size)
+			for i := range size {
+				s.Add(size + i)
+			}
+			for i := range size {
+				s.Add(i)
+			}
+		}
+	})
+
+	b.Run("gapped", func(b *testing.B) {
+		b.ResetTimer()
+		for range b.N {
+			s := NewGapped[int](2 * size)
+			for i := range size {
+				s.Add(size + i)
+			}
+			for i := range size {
+				s.Add(i)
+			}
+		}
+	})
+}
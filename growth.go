@@ -0,0 +1,65 @@
+package smallset
+
+import "slices"
+
+// Growth is the strategy used to grow the underlying slice of a set when it's full.
+// The zero value grows it like the built-in append. See [Ordered.WithGrowth].
+type Growth uint8
+
+const (
+	// Amortized doubles the capacity, so that insertions reallocate O(log N) times.
+	// It's meant for insert-heavy workloads.
+	Amortized Growth = iota + 1
+
+	// Exact grows the capacity by a single element, so that no memory is wasted
+	// at the cost of a reallocation on every insertion beyond the capacity.
+	// It's meant for memory-tight workloads, where the size is mostly known in advance.
+	Exact
+)
+
+// insertGrow inserts e at index i of items, growing the slice according to the strategy g.
+func insertGrow[T any](g Growth, items []T, i int, e T) []T {
+	if len(items) < cap(items) {
+		return slices.Insert(items, i, e)
+	}
+
+	capacity := g.capacity(len(items), cap(items), 1)
+	if capacity == 0 {
+		return slices.Insert(items, i, e)
+	}
+
+	grown := make([]T, len(items)+1, capacity)
+	copy(grown, items[:i])
+	grown[i] = e
+	copy(grown[i+1:], items[i:])
+	return grown
+}
+
+// grow returns items with room for n more elements, growing the slice according to the strategy g.
+func grow[T any](g Growth, items []T, n int) []T {
+	if len(items)+n <= cap(items) {
+		return items
+	}
+
+	capacity := g.capacity(len(items), cap(items), n)
+	if capacity == 0 {
+		return slices.Grow(items, n)
+	}
+
+	grown := make([]T, len(items), capacity)
+	copy(grown, items)
+	return grown
+}
+
+// capacity returns the capacity of a full slice of the provided length and capacity grown to fit
+// n more elements, or 0 if the strategy is the default, which grows like the built-in append.
+func (g Growth) capacity(length, capacity, n int) int {
+	switch g {
+	case Amortized:
+		return max(2*capacity, length+n)
+	case Exact:
+		return length + n
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,83 @@
+package smallset
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestWithGrowth(t *testing.T) {
+	cases := []struct {
+		growth   Growth
+		adds     int
+		capacity int
+	}{
+		{growth: Exact, adds: 1, capacity: 1},
+		{growth: Exact, adds: 5, capacity: 5},
+		{growth: Exact, adds: 100, capacity: 100},
+		{growth: Amortized, adds: 1, capacity: 1},
+		{growth: Amortized, adds: 5, capacity: 8},
+		{growth: Amortized, adds: 100, capacity: 128},
+	}
+
+	for i, test := range cases {
+		t.Run(fmt.Sprintf("Case_%d", i), func(t *testing.T) {
+			s := New[int](1).WithGrowth(test.growth)
+			c := NewCustom(cmp.Compare[int], 1).WithGrowth(test.growth)
+			expected := make([]int, 0, test.adds)
+
+			// adding in descending order exercises insertions at the front
+			for i := test.adds - 1; i >= 0; i-- {
+				s.Add(i)
+				c.Add(i)
+				expected = append(expected, i)
+			}
+			slices.Sort(expected)
+
+			if s.Capacity() != test.capacity || c.Capacity() != test.capacity {
+				t.Errorf("expected capacity %d, got %d and %d", test.capacity, s.Capacity(), c.Capacity())
+			}
+
+			if !slices.Equal(s.items, expected) || !slices.Equal(c.items, expected) {
+				t.Errorf("Items mismatch.\nExpected: %v\nActual: %v and %v", expected, s.items, c.items)
+			}
+		})
+	}
+}
+
+func TestAddSortedGrowth(t *testing.T) {
+	cases := []struct {
+		growth   Growth
+		items    []int
+		capacity int
+	}{
+		{growth: Exact, items: []int{1, 2, 3}, capacity: 5},
+		{growth: Exact, items: []int{0, 2, 4}, capacity: 3},
+		{growth: Amortized, items: []int{1, 2, 3}, capacity: 5},
+		{growth: Amortized, items: []int{5, 6, 7, 8, 9, 10}, capacity: 8},
+	}
+
+	for i, test := range cases {
+		t.Run(fmt.Sprintf("Case_%d", i), func(t *testing.T) {
+			s := New[int](2).WithGrowth(test.growth)
+			c := NewCustom(cmp.Compare[int], 2).WithGrowth(test.growth)
+			s.Add(0)
+			s.Add(4)
+			c.Add(0)
+			c.Add(4)
+
+			s.AddSorted(test.items)
+			c.AddSorted(test.items)
+
+			if s.Capacity() != test.capacity || c.Capacity() != test.capacity {
+				t.Errorf("expected capacity %d, got %d and %d", test.capacity, s.Capacity(), c.Capacity())
+			}
+
+			expected := Merge(From(0, 4), From(test.items...))
+			if !slices.Equal(s.items, expected.items) || !slices.Equal(c.items, expected.items) {
+				t.Errorf("Items mismatch.\nExpected: %v\nActual: %v and %v", expected.items, s.items, c.items)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package smallset
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"math/bits"
+)
+
+// HyperLogLog is a sketch that estimates the number of distinct elements added to it, with a
+// standard error of about 1.04/sqrt(2^precision) using 2^precision bytes. Sketches can be merged,
+// so the distinct count of the union of many sets can be estimated without materializing it.
+//
+//	h := smallset.NewHyperLogLog(14)
+//	for _, s := range sets {
+//		smallset.AddAll(h, s, nil)
+//	}
+//	distinct := h.Count()
+//
+// The hashes don't depend on the process, so sketches built on different machines with the same
+// precision and hash function can be merged, after being sent with [HyperLogLog.MarshalBinary]
+// and received with [HyperLogLog.UnmarshalBinary]. Not safe for concurrent use.
+type HyperLogLog struct {
+	precision uint8
+	registers []uint8
+}
+
+// NewHyperLogLog returns an empty sketch with 2^precision registers.
+// It panics if precision is not in [4, 18].
+func NewHyperLogLog(precision int) *HyperLogLog {
+	if precision < 4 || precision > 18 {
+		panic("smallset.NewHyperLogLog: precision must be in [4, 18]")
+	}
+	return &HyperLogLog{
+		precision: uint8(precision),
+		registers: make([]uint8, 1<<precision),
+	}
+}
+
+// Precision returns the precision of the sketch.
+func (h *HyperLogLog) Precision() int {
+	return int(h.precision)
+}
+
+// Add the hash of an element to the sketch.
+func (h *HyperLogLog) Add(hash uint64) {
+	// mix the hash, so that poorly distributed hashes don't skew the estimate
+	x := mix(hash)
+	i := x >> (64 - h.precision)
+	rank := uint8(bits.LeadingZeros64(x<<h.precision|1<<(h.precision-1))) + 1
+	h.registers[i] = max(h.registers[i], rank)
+}
+
+// Count returns the estimated number of distinct elements added to the sketch.
+func (h *HyperLogLog) Count() uint64 {
+	m := float64(len(h.registers))
+	sum := 0.0
+	zeros := 0
+	for _, r := range h.registers {
+		sum += math.Ldexp(1, -int(r))
+		if r == 0 {
+			zeros++
+		}
+	}
+
+	estimate := hllAlpha(len(h.registers)) * m * m / sum
+	if estimate <= 2.5*m && zeros > 0 {
+		// linear counting is more accurate for small cardinalities
+		estimate = m * math.Log(m/float64(zeros))
+	}
+	return uint64(estimate + 0.5)
+}
+
+// Merge adds the elements of other to the sketch, as if they had been added to it directly.
+// It panics if the sketches have a different precision.
+func (h *HyperLogLog) Merge(other *HyperLogLog) {
+	if h.precision != other.precision {
+		panic("smallset.HyperLogLog.Merge: sketches have a different precision")
+	}
+	for i, r := range other.registers {
+		h.registers[i] = max(h.registers[i], r)
+	}
+}
+
+// Clear removes all elements from the sketch.
+func (h *HyperLogLog) Clear() {
+	clear(h.registers)
+}
+
+// MarshalBinary implements [encoding.BinaryMarshaler], encoding the sketch as its precision
+// in one byte, followed by its 2^precision registers. The error is always nil.
+func (h *HyperLogLog) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 0, 1+len(h.registers))
+	data = append(data, h.precision)
+	return append(data, h.registers...), nil
+}
+
+// UnmarshalBinary implements [encoding.BinaryUnmarshaler], replacing the sketch with the one
+// encoded by [HyperLogLog.MarshalBinary]. If the sketch was created with [NewHyperLogLog],
+// the encoded precision must match its own, so that the two can be merged.
+// It returns [ErrInvalidFormat] if the data is malformed or the precision doesn't match.
+func (h *HyperLogLog) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 {
+		return fmt.Errorf("%w: missing sketch precision", ErrInvalidFormat)
+	}
+
+	precision := data[0]
+	if precision < 4 || precision > 18 {
+		return fmt.Errorf("%w: sketch precision must be in [4, 18], got %d", ErrInvalidFormat, precision)
+	}
+	if h.registers != nil && precision != h.precision {
+		return fmt.Errorf("%w: expected sketch precision %d, got %d", ErrInvalidFormat, h.precision, precision)
+	}
+
+	registers := data[1:]
+	if len(registers) != 1<<precision {
+		return fmt.Errorf("%w: sketch of precision %d has %d registers", ErrInvalidFormat, precision, len(registers))
+	}
+	for _, r := range registers {
+		if r > 64-precision+1 {
+			return fmt.Errorf("%w: register %d is out of range for precision %d", ErrInvalidFormat, r, precision)
+		}
+	}
+
+	h.precision = precision
+	h.registers = append(h.registers[:0], registers...)
+	return nil
+}
+
+func hllAlpha(m int) float64 {
+	switch m {
+	case 16:
+		return 0.673
+	case 32:
+		return 0.697
+	case 64:
+		return 0.709
+	default:
+		return 0.7213 / (1 + 1.079/float64(m))
+	}
+}
+
+// AddAll adds the elements of the set to the sketch, hashed with the provided function,
+// or with the stable hash of [Ordered.Sketch] if hash is nil. O(N) complexity.
+func AddAll[T cmp.Ordered](h *HyperLogLog, s *Ordered[T], hash func(T) uint64) {
+	if hash == nil {
+		hash = stableHashOrdered[T]
+	}
+	for _, e := range s.Ascend() {
+		h.Add(hash(e))
+	}
+}
+
+// AddAllCustom adds the elements of the set to the sketch, hashed with the provided function.
+// Elements that compare equal must have the same hash. O(N) complexity.
+// It panics if hash is nil.
+func AddAllCustom[T any](h *HyperLogLog, s *Custom[T], hash func(T) uint64) {
+	if hash == nil {
+		panic("smallset.AddAllCustom: hash cannot be nil")
+	}
+	for _, e := range s.Ascend() {
+		h.Add(hash(e))
+	}
+}
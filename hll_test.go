@@ -0,0 +1,133 @@
+package smallset
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"testing"
+)
+
+func TestHyperLogLog(t *testing.T) {
+	cases := []struct {
+		precision int
+		distinct  int
+	}{
+		{precision: 10, distinct: 0},
+		{precision: 10, distinct: 100},
+		{precision: 12, distinct: 10_000},
+		{precision: 14, distinct: 200_000},
+	}
+
+	for i, test := range cases {
+		t.Run(fmt.Sprintf("Case_%d", i), func(t *testing.T) {
+			// many small overlapping sets, whose union has the expected distinct elements
+			rng := rand.New(rand.NewPCG(1, uint64(i)))
+			h := NewHyperLogLog(test.precision)
+			for start := 0; start < test.distinct; start += 50 {
+				s := NewRange(start, min(start+100, test.distinct), 1)
+				s.RemoveBetween(start+50+rng.IntN(50), start+100)
+				AddAll(h, s, nil)
+			}
+
+			stdErr := 1.04 / math.Sqrt(float64(int(1)<<test.precision))
+			count := float64(h.Count())
+			if math.Abs(count-float64(test.distinct)) > 4*stdErr*float64(test.distinct) {
+				t.Errorf("expected about %d distinct elements, got %v", test.distinct, count)
+			}
+		})
+	}
+}
+
+func TestHyperLogLogMerge(t *testing.T) {
+	a := NewHyperLogLog(12)
+	b := NewHyperLogLog(12)
+	all := NewHyperLogLog(12)
+
+	s1 := NewRange(0, 6000, 1)
+	s2 := NewRange(3000, 9000, 1)
+	AddAll(a, s1, nil)
+	AddAll(b, s2, nil)
+	AddAll(all, s1, nil)
+	AddAll(all, s2, nil)
+
+	a.Merge(b)
+	if a.Count() != all.Count() {
+		t.Errorf("expected the merged count %d to match %d", a.Count(), all.Count())
+	}
+
+	a.Clear()
+	if a.Count() != 0 {
+		t.Errorf("expected an empty sketch, got %d", a.Count())
+	}
+}
+
+func TestHyperLogLogMarshalBinary(t *testing.T) {
+	local := NewHyperLogLog(10)
+	remote := NewHyperLogLog(10)
+	AddAll(local, NewRange(0, 500, 1), nil)
+	AddAll(remote, NewRange(250, 1000, 1), nil)
+
+	data, err := remote.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	received := NewHyperLogLog(10)
+	if err := received.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if received.Count() != remote.Count() {
+		t.Fatalf("expected count %d after the round trip, got %d", remote.Count(), received.Count())
+	}
+
+	all := NewHyperLogLog(10)
+	AddAll(all, NewRange(0, 1000, 1), nil)
+	local.Merge(received)
+	if local.Count() != all.Count() {
+		t.Errorf("expected the merged count %d to match %d", local.Count(), all.Count())
+	}
+
+	var zero HyperLogLog
+	if err := zero.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if zero.Precision() != 10 || zero.Count() != remote.Count() {
+		t.Errorf("expected precision 10 and count %d, got %d and %d", remote.Count(), zero.Precision(), zero.Count())
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		invalid := bytes.Clone(data)
+		invalid[1] = 64
+
+		cases := []struct {
+			h    *HyperLogLog
+			data []byte
+		}{
+			{h: NewHyperLogLog(10), data: nil},
+			{h: NewHyperLogLog(10), data: []byte{3}},
+			{h: NewHyperLogLog(10), data: data[:len(data)-1]},
+			{h: NewHyperLogLog(10), data: invalid},
+			{h: NewHyperLogLog(12), data: data},
+		}
+
+		for i, test := range cases {
+			t.Run(fmt.Sprintf("Case_%d", i), func(t *testing.T) {
+				if err := test.h.UnmarshalBinary(test.data); !errors.Is(err, ErrInvalidFormat) {
+					t.Errorf("expected error %v, got %v", ErrInvalidFormat, err)
+				}
+			})
+		}
+	})
+}
+
+func TestHyperLogLogCustom(t *testing.T) {
+	h := NewHyperLogLog(10)
+	AddAllCustom(h, CustomFrom(PersonCmp, people1...), func(p Person) uint64 { return uint64(p.ID) })
+	AddAllCustom(h, CustomFrom(PersonCmp, people1...), func(p Person) uint64 { return uint64(p.ID) })
+
+	if count := h.Count(); count != 4 {
+		t.Errorf("expected 4 distinct elements, got %d", count)
+	}
+}
@@ -0,0 +1,58 @@
+package smallset
+
+import (
+	"iter"
+	"slices"
+	"strings"
+)
+
+// Interner is a pool of canonical strings backed by an [Ordered] set, that deduplicates
+// equal strings so they share the same memory. Unlike a map, the intern table can be
+// iterated in a deterministic (sorted) order. Lookups are O(log(N)), insertions are O(N).
+// Not safe for concurrent use.
+type Interner struct {
+	set *Ordered[string]
+}
+
+// NewInterner returns an initialized interner with the provided capacity.
+// It panics if the capacity is <= 0.
+func NewInterner(capacity int) *Interner {
+	if capacity <= 0 {
+		panic("smallset.NewInterner: capacity must be > 0")
+	}
+	return &Interner{set: New[string](capacity)}
+}
+
+// Intern returns the canonical instance of the string, adding a copy of it to the pool if
+// it's not present. The copy ensures that the pool doesn't retain the memory of bigger
+// strings that s may be a substring of, like the buffer of a parser.
+func (in *Interner) Intern(s string) string {
+	i, found := slices.BinarySearch(in.set.items, s)
+	if found {
+		return in.set.items[i]
+	}
+
+	s = strings.Clone(s)
+	in.set.insert(i, s)
+	return s
+}
+
+// Lookup returns the canonical instance of the string and whether it's in the pool,
+// without adding it.
+func (in *Interner) Lookup(s string) (string, bool) {
+	i, found := slices.BinarySearch(in.set.items, s)
+	if !found {
+		return "", false
+	}
+	return in.set.items[i], true
+}
+
+// Size returns the number of strings in the pool.
+func (in *Interner) Size() int {
+	return in.set.Size()
+}
+
+// Ascend returns an iterator over the strings of the pool in ascending order.
+func (in *Interner) Ascend() iter.Seq2[int, string] {
+	return in.set.Ascend()
+}
@@ -0,0 +1,43 @@
+package smallset
+
+import (
+	"slices"
+	"testing"
+	"unsafe"
+)
+
+func TestInterner(t *testing.T) {
+	in := NewInterner(10)
+	buf := "alpha beta alpha gamma"
+
+	a1 := in.Intern(buf[0:5])
+	b := in.Intern(buf[6:10])
+	a2 := in.Intern(buf[11:16])
+	in.Intern(buf[17:])
+
+	if a1 != "alpha" || b != "beta" {
+		t.Fatalf("unexpected interned strings %q, %q", a1, b)
+	}
+
+	if unsafe.StringData(a1) != unsafe.StringData(a2) {
+		t.Errorf("expected equal strings to share the same memory")
+	}
+	if unsafe.StringData(a1) == unsafe.StringData(buf) {
+		t.Errorf("expected the interned string not to retain the buffer")
+	}
+
+	if s, found := in.Lookup("beta"); !found || unsafe.StringData(s) != unsafe.StringData(b) {
+		t.Errorf("Lookup(beta) expected the canonical instance")
+	}
+	if _, found := in.Lookup("delta"); found || in.Size() != 3 {
+		t.Errorf("Lookup must not add strings")
+	}
+
+	var table []string
+	for _, s := range in.Ascend() {
+		table = append(table, s)
+	}
+	if expected := []string{"alpha", "beta", "gamma"}; !slices.Equal(table, expected) {
+		t.Errorf("Ascend expected %v, got %v", expected, table)
+	}
+}
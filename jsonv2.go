@@ -0,0 +1,102 @@
+//go:build go1.27 && goexperiment.jsonv2
+
+// The go1.27 constraint raises the language version of this file above the one of go.mod,
+// which go vet requires for using the encoding/json/v2 API, added in Go 1.27.
+
+package smallset
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"errors"
+	"slices"
+)
+
+// MarshalJSONTo implements json.MarshalerTo, streaming the set as a JSON array
+// in ascending order, one element at a time.
+func (s *Ordered[T]) MarshalJSONTo(enc *jsontext.Encoder) error {
+	defer s.pin()()
+	return marshalItems(enc, s.items)
+}
+
+// UnmarshalJSONFrom implements json.UnmarshalerFrom, replacing the elements of the set
+// with the ones of the JSON array read from the decoder, one element at a time.
+// A JSON null clears the set. Elements can be in any order, and duplicates are discarded.
+func (s *Ordered[T]) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	items, err := unmarshalItems[T](dec)
+	if err != nil {
+		return err
+	}
+
+	slices.Sort(items)
+	s.reset(compact(items))
+	return nil
+}
+
+// MarshalJSONTo implements json.MarshalerTo, streaming the set as a JSON array
+// in the order of the set, one element at a time.
+func (s *Custom[T]) MarshalJSONTo(enc *jsontext.Encoder) error {
+	defer s.pin()()
+	return marshalItems(enc, s.items)
+}
+
+// UnmarshalJSONFrom implements json.UnmarshalerFrom, replacing the elements of the set
+// with the ones of the JSON array read from the decoder, one element at a time.
+// A JSON null clears the set. Elements can be in any order, and duplicates are discarded.
+// It returns an error if the set has no compare function, like the zero value.
+func (s *Custom[T]) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	if s.cmp == nil {
+		return errors.New("smallset.Custom.UnmarshalJSONFrom: set has no compare function, initialize it with NewCustom")
+	}
+
+	items, err := unmarshalItems[T](dec)
+	if err != nil {
+		return err
+	}
+
+	slices.SortFunc(items, s.cmp)
+	s.reset(slices.CompactFunc(items, s.cmp.equal))
+	return nil
+}
+
+func marshalItems[T any](enc *jsontext.Encoder, items []T) error {
+	if err := enc.WriteToken(jsontext.BeginArray); err != nil {
+		return err
+	}
+	for _, e := range items {
+		if err := json.MarshalEncode(enc, e); err != nil {
+			return err
+		}
+	}
+	return enc.WriteToken(jsontext.EndArray)
+}
+
+// unmarshalItems reads a JSON array or null from the decoder, returning its elements.
+func unmarshalItems[T any](dec *jsontext.Decoder) ([]T, error) {
+	tok, err := dec.ReadToken()
+	if err != nil {
+		return nil, err
+	}
+
+	switch tok.Kind() {
+	case 'n':
+		return nil, nil
+	case '[':
+	default:
+		return nil, errors.New("smallset: cannot unmarshal JSON " + tok.Kind().String() + " into a set")
+	}
+
+	var items []T
+	for dec.PeekKind() != ']' {
+		var e T
+		if err := json.UnmarshalDecode(dec, &e); err != nil {
+			return nil, err
+		}
+		items = append(items, e)
+	}
+
+	if _, err := dec.ReadToken(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,101 @@
+//go:build go1.27 && goexperiment.jsonv2
+
+// The go1.27 constraint raises the language version of this file above the one of go.mod,
+// which go vet requires for using the encoding/json/v2 API, added in Go 1.27.
+
+package smallset
+
+import (
+	"encoding/json/v2"
+	"fmt"
+	"slices"
+	"testing"
+)
+
+var (
+	_ json.MarshalerTo     = (*Ordered[int])(nil)
+	_ json.UnmarshalerFrom = (*Ordered[int])(nil)
+	_ json.MarshalerTo     = (*Custom[int])(nil)
+	_ json.UnmarshalerFrom = (*Custom[int])(nil)
+)
+
+func TestOrderedJSON(t *testing.T) {
+	cases := []struct {
+		data     string
+		expected []int
+		err      bool
+	}{
+		{data: `[]`, expected: nil},
+		{data: `null`, expected: nil},
+		{data: `[3, 1, 2, 3]`, expected: []int{1, 2, 3}},
+		{data: `{"a": 1}`, err: true},
+		{data: `[1, "a"]`, err: true},
+	}
+
+	for i, test := range cases {
+		t.Run(fmt.Sprintf("Case_%d", i), func(t *testing.T) {
+			set := From(7)
+			err := json.Unmarshal([]byte(test.data), set)
+			if (err != nil) != test.err {
+				t.Fatalf("expected error %v, got %v", test.err, err)
+			}
+			if err != nil {
+				return
+			}
+
+			if !slices.Equal(set.items, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, set.items)
+			}
+		})
+	}
+}
+
+func TestOrderedJSONRoundTrip(t *testing.T) {
+	doc := struct {
+		Tags *Ordered[string] `json:"tags"`
+	}{Tags: From("b", "c", "a")}
+
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"tags":["a","b","c"]}` {
+		t.Fatalf("unexpected encoding %s", data)
+	}
+
+	doc.Tags = New[string](10)
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(doc.Tags.items, []string{"a", "b", "c"}) {
+		t.Errorf("expected [a b c], got %v", doc.Tags.items)
+	}
+}
+
+func TestCustomJSON(t *testing.T) {
+	set := NewCustom(PersonCmp, 10)
+	if err := json.Unmarshal([]byte(`[{"Name": "b", "ID": 2}, {"Name": "a", "ID": 1}]`), set); err != nil {
+		t.Fatal(err)
+	}
+	if set.Size() != 2 || set.Min().ID != 1 {
+		t.Fatalf("unexpected set %v", set.items)
+	}
+
+	data, err := json.Marshal(set)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	other := NewCustom(PersonCmp, 10)
+	if err := json.Unmarshal(data, other); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(set.items, other.items) {
+		t.Errorf("expected %v, got %v", set.items, other.items)
+	}
+
+	var zero Custom[Person]
+	if err := json.Unmarshal(data, &zero); err == nil {
+		t.Error("expected an error for a set without compare function")
+	}
+}
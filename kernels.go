@@ -0,0 +1,81 @@
+package smallset
+
+// The merge loops of Intersect and Union branch on the comparison of the current elements,
+// which the CPU can't predict when the two sets interleave, like two big sets of random IDs.
+// For the common integer types, the kernels below advance the indices with arithmetic on the
+// comparison results instead, which compiles to conditional moves.
+
+// intersectKernel returns the intersection of a and b using a branchless kernel,
+// and whether T has one. The returned slice has capacity min(len(a), len(b)).
+func intersectKernel[T any](a, b []T) ([]T, bool) {
+	switch a := any(a).(type) {
+	case []int:
+		return any(intersectBranchless(a, any(b).([]int))).([]T), true
+	case []int32:
+		return any(intersectBranchless(a, any(b).([]int32))).([]T), true
+	case []int64:
+		return any(intersectBranchless(a, any(b).([]int64))).([]T), true
+	case []uint32:
+		return any(intersectBranchless(a, any(b).([]uint32))).([]T), true
+	case []uint64:
+		return any(intersectBranchless(a, any(b).([]uint64))).([]T), true
+	default:
+		return nil, false
+	}
+}
+
+// unionKernel returns the union of a and b using a branchless kernel,
+// and whether T has one. The returned slice has capacity len(a) + len(b).
+func unionKernel[T any](a, b []T) ([]T, bool) {
+	switch a := any(a).(type) {
+	case []int:
+		return any(unionBranchless(a, any(b).([]int))).([]T), true
+	case []int32:
+		return any(unionBranchless(a, any(b).([]int32))).([]T), true
+	case []int64:
+		return any(unionBranchless(a, any(b).([]int64))).([]T), true
+	case []uint32:
+		return any(unionBranchless(a, any(b).([]uint32))).([]T), true
+	case []uint64:
+		return any(unionBranchless(a, any(b).([]uint64))).([]T), true
+	default:
+		return nil, false
+	}
+}
+
+func intersectBranchless[T Integer](a, b []T) []T {
+	inter := make([]T, min(len(a), len(b)))
+	i, j, k := 0, 0, 0
+	for i < len(a) && j < len(b) {
+		x, y := a[i], b[j]
+		// k <= min(i, j), so the write is always in bounds and overwritten unless x == y
+		inter[k] = x
+		k += b2i(x == y)
+		i += b2i(x <= y)
+		j += b2i(y <= x)
+	}
+	return inter[:k]
+}
+
+func unionBranchless[T Integer](a, b []T) []T {
+	union := make([]T, len(a)+len(b))
+	i, j, k := 0, 0, 0
+	for i < len(a) && j < len(b) {
+		x, y := a[i], b[j]
+		union[k] = min(x, y)
+		k++
+		i += b2i(x <= y)
+		j += b2i(y <= x)
+	}
+	k += copy(union[k:], a[i:])
+	k += copy(union[k:], b[j:])
+	return union[:k]
+}
+
+// b2i converts a bool to 0 or 1. The compiler turns it into a SETcc instruction without branches.
+func b2i(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
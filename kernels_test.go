@@ -0,0 +1,101 @@
+package smallset
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestKernels(t *testing.T) {
+	rng := rand.New(rand.NewPCG(5, 6))
+	cases := []struct {
+		size1, size2 int
+		max          int
+	}{
+		{size1: 0, size2: 0, max: 10},
+		{size1: 0, size2: 10, max: 10},
+		{size1: 10, size2: 0, max: 10},
+		{size1: 100, size2: 100, max: 150},
+		{size1: 5, size2: 500, max: 1000},
+		{size1: 500, size2: 500, max: 1_000_000},
+	}
+
+	for i, test := range cases {
+		t.Run(fmt.Sprintf("Case_%d", i), func(t *testing.T) {
+			var a, b []int64
+			for range test.size1 {
+				a = append(a, rng.Int64N(int64(test.max))-int64(test.max/2))
+			}
+			for range test.size2 {
+				b = append(b, rng.Int64N(int64(test.max))-int64(test.max/2))
+			}
+			a, b = From(a...).items, From(b...).items
+
+			inter, ok := intersectKernel(a, b)
+			if !ok {
+				t.Fatal("expected a kernel for int64")
+			}
+			if expected := From(a...).Intersect(From(b...)).items; !slices.Equal(inter, expected) {
+				t.Errorf("intersection: expected %v, got %v", expected, inter)
+			}
+
+			union, _ := unionKernel(a, b)
+			if expected := From(append(slices.Clone(a), b...)...).items; !slices.Equal(union, expected) {
+				t.Errorf("union: expected %v, got %v", expected, union)
+			}
+		})
+	}
+
+	if _, ok := intersectKernel([]float64{1}, []float64{1}); ok {
+		t.Error("expected no kernel for float64")
+	}
+}
+
+func BenchmarkKernels(b *testing.B) {
+	// id has no branchless kernel, so it measures the generic merge loop
+	type id int
+
+	rng := rand.New(rand.NewPCG(1, 2))
+	s1, s2 := New[int](10000), New[int](10000)
+	for range 10000 {
+		s1.Add(rng.IntN(40000))
+		s2.Add(rng.IntN(40000))
+	}
+
+	g1, g2 := New[id](10000), New[id](10000)
+	for _, e := range s1.items {
+		g1.Add(id(e))
+	}
+	for _, e := range s2.items {
+		g2.Add(id(e))
+	}
+
+	b.Run("intersect/generic", func(b *testing.B) {
+		b.ResetTimer()
+		for range b.N {
+			g1.Intersect(g2)
+		}
+	})
+
+	b.Run("intersect/kernel", func(b *testing.B) {
+		b.ResetTimer()
+		for range b.N {
+			intersectKernel(s1.items, s2.items)
+		}
+	})
+
+	b.Run("union/generic", func(b *testing.B) {
+		b.ResetTimer()
+		for range b.N {
+			g1.Union(g2)
+		}
+	})
+
+	b.Run("union/kernel", func(b *testing.B) {
+		b.ResetTimer()
+		for range b.N {
+			unionKernel(s1.items, s2.items)
+		}
+	})
+}
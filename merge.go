@@ -0,0 +1,170 @@
+package smallset
+
+import "cmp"
+
+// mergeK merges the sorted lists without duplicates into a single sorted slice without duplicates.
+// It's a single pass over the lists with a [loserTree], which finds the next smallest element with
+// log(K) comparisons, making it O(N*log(K)) where N is the total number of elements and K the number
+// of lists. Two lists are merged directly, without the tree.
+func mergeK[T any](lists [][]T, cmp func(a, b T) int) []T {
+	runs, size := nonEmpty(lists)
+	switch len(runs) {
+	case 0:
+		return nil
+	case 1:
+		return append(make([]T, 0, size), runs[0]...)
+	case 2:
+		return merge2(make([]T, 0, size), runs[0], runs[1], cmp)
+	}
+
+	less := func(a, b loserNode[T]) bool {
+		if a.done || b.done {
+			return b.done && !a.done
+		}
+		return cmp(a.head, b.head) < 0
+	}
+
+	t := newLoserTree(runs, less)
+	merged := make([]T, 0, size)
+	for range size {
+		e, winner := t.advance()
+		for node := t.parent(winner); node > 0; node /= 2 {
+			if loser := t.nodes[node]; less(loser, winner) {
+				t.nodes[node], winner = winner, loser
+			}
+		}
+		t.nodes[0] = winner
+
+		if len(merged) == 0 || cmp(merged[len(merged)-1], e) != 0 {
+			merged = append(merged, e)
+		}
+	}
+	return merged
+}
+
+// mergeOrdered is [mergeK] for ordered types. It compares the elements with [cmp.Less],
+// which is inlined in the matches instead of being called through a compare function.
+func mergeOrdered[T cmp.Ordered](lists [][]T) []T {
+	runs, size := nonEmpty(lists)
+	if len(runs) <= 2 {
+		return mergeK(runs, cmp.Compare[T])
+	}
+
+	t := newLoserTree(runs, lessOrdered[T])
+	merged := make([]T, 0, size)
+	for range size {
+		e, winner := t.advance()
+		for node := t.parent(winner); node > 0; node /= 2 {
+			if loser := t.nodes[node]; lessOrdered(loser, winner) {
+				t.nodes[node], winner = winner, loser
+			}
+		}
+		t.nodes[0] = winner
+
+		if len(merged) == 0 || !equal(merged[len(merged)-1], e) {
+			merged = append(merged, e)
+		}
+	}
+	return merged
+}
+
+// nonEmpty returns the non-empty lists and their total size.
+func nonEmpty[T any](lists [][]T) (runs [][]T, size int) {
+	runs = make([][]T, 0, len(lists))
+	for _, list := range lists {
+		if len(list) > 0 {
+			runs = append(runs, list)
+			size += len(list)
+		}
+	}
+	return runs, size
+}
+
+// loserTree is a tournament tree over K sorted runs, whose internal nodes hold the run that lost
+// the match played there, so that replacing the winner only replays the matches on its path to the root.
+// Node i has children 2i and 2i+1, and the leaves K..2K-1 are the runs. Nodes hold a copy of the head
+// of their run, so that the matches don't load the runs.
+type loserTree[T any] struct {
+	runs  [][]T          // the remaining elements of each run
+	nodes []loserNode[T] // nodes[0] is the overall winner
+}
+
+// loserNode is the head of a run of a [loserTree].
+type loserNode[T any] struct {
+	head T
+	run  int
+	done bool // the run is exhausted, so it loses every match
+}
+
+// newLoserTree returns the tree of the non-empty runs, after playing all matches with less.
+func newLoserTree[T any](runs [][]T, less func(a, b loserNode[T]) bool) *loserTree[T] {
+	t := &loserTree[T]{runs: runs, nodes: make([]loserNode[T], len(runs))}
+
+	var build func(node int) loserNode[T]
+	build = func(node int) loserNode[T] {
+		if node >= len(runs) {
+			run := node - len(runs)
+			return loserNode[T]{head: runs[run][0], run: run}
+		}
+
+		winner, loser := build(2*node), build(2*node+1)
+		if less(loser, winner) {
+			winner, loser = loser, winner
+		}
+		t.nodes[node] = loser
+		return winner
+	}
+
+	t.nodes[0] = build(1)
+	return t
+}
+
+// advance returns the head of the winner run, and the winner node with the next head of its run.
+// The caller must replay the matches of the node up to the root, starting from [loserTree.parent].
+func (t *loserTree[T]) advance() (T, loserNode[T]) {
+	winner := t.nodes[0]
+	e := winner.head
+
+	run := t.runs[winner.run][1:]
+	t.runs[winner.run] = run
+	if len(run) > 0 {
+		winner.head = run[0]
+	} else {
+		winner.done = true
+	}
+	return e, winner
+}
+
+// parent returns the index of the first match played by the run of the node.
+func (t *loserTree[T]) parent(n loserNode[T]) int {
+	return (n.run + len(t.runs)) / 2
+}
+
+// lessOrdered returns whether the node a comes before the node b.
+func lessOrdered[T cmp.Ordered](a, b loserNode[T]) bool {
+	if a.done || b.done {
+		return b.done && !a.done
+	}
+	return cmp.Less(a.head, b.head)
+}
+
+// merge2 appends to dst the sorted union of a and b, which must be sorted without duplicates.
+func merge2[T any](dst, a, b []T, cmp func(a, b T) int) []T {
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch c := cmp(a[i], b[j]); {
+		case c < 0:
+			dst = append(dst, a[i])
+			i++
+		case c > 0:
+			dst = append(dst, b[j])
+			j++
+		default:
+			dst = append(dst, a[i])
+			i++
+			j++
+		}
+	}
+	dst = append(dst, a[i:]...)
+	return append(dst, b[j:]...)
+}
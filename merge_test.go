@@ -0,0 +1,114 @@
+package smallset
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestMergeK(t *testing.T) {
+	rng := rand.New(rand.NewPCG(3, 4))
+	cases := []struct {
+		lists int
+		size  int
+	}{
+		{lists: 0, size: 0},
+		{lists: 3, size: 0},
+		{lists: 5, size: 1},
+		{lists: 8, size: 50},
+		{lists: 100, size: 20},
+	}
+
+	for i, test := range cases {
+		t.Run(fmt.Sprintf("Case_%d", i), func(t *testing.T) {
+			lists := make([][]int, test.lists)
+			var all []int
+			for i := range lists {
+				for range test.size {
+					lists[i] = append(lists[i], rng.IntN(200))
+				}
+				slices.Sort(lists[i])
+				lists[i] = slices.Compact(lists[i])
+				all = append(all, lists[i]...)
+			}
+
+			slices.Sort(all)
+			expected := slices.Compact(all)
+			merged := mergeK(lists, cmp.Compare[int])
+			if !slices.Equal(merged, expected) {
+				t.Errorf("Expected %v, got %v", expected, merged)
+			}
+			if merged := mergeOrdered(lists); !slices.Equal(merged, expected) {
+				t.Errorf("mergeOrdered: expected %v, got %v", expected, merged)
+			}
+		})
+	}
+}
+
+func TestMergeKNaN(t *testing.T) {
+	nan := math.NaN()
+	sets := []*Ordered[float64]{
+		From(nan, 1, 2),
+		From(nan, 2, 3),
+		From(0.5, 3),
+		From(nan),
+		From(4.0),
+	}
+
+	merged := Merge(sets...)
+	if merged.Size() != 6 || !math.IsNaN(merged.items[0]) {
+		t.Errorf("Expected a single NaN followed by 0.5 1 2 3 4, got %v", merged.items)
+	}
+}
+
+func BenchmarkMerge(b *testing.B) {
+	for _, k := range []int{2, 4, 8, 64} {
+		sets := randomSets(rand.New(rand.NewPCG(1, 2)), k, 1000, 1_000_000)
+		lists := make([][]int, k)
+		for i, s := range sets {
+			lists[i] = s.items
+		}
+
+		b.Run(fmt.Sprintf("sort/k=%d", k), func(b *testing.B) {
+			b.ResetTimer()
+			for range b.N {
+				combined := make([]int, 0, k*1000)
+				for _, list := range lists {
+					combined = append(combined, list...)
+				}
+				slices.Sort(combined)
+				compact(combined)
+			}
+		})
+
+		b.Run(fmt.Sprintf("kway/k=%d", k), func(b *testing.B) {
+			b.ResetTimer()
+			for range b.N {
+				mergeOrdered(lists)
+			}
+		})
+
+		// the path of MergeCustom, where sorting also calls the compare function
+		b.Run(fmt.Sprintf("sortfunc/k=%d", k), func(b *testing.B) {
+			b.ResetTimer()
+			for range b.N {
+				combined := make([]int, 0, k*1000)
+				for _, list := range lists {
+					combined = append(combined, list...)
+				}
+				slices.SortFunc(combined, cmp.Compare[int])
+				_ = slices.CompactFunc(combined, func(a, b int) bool { return a == b })
+			}
+		})
+
+		b.Run(fmt.Sprintf("kwayfunc/k=%d", k), func(b *testing.B) {
+			b.ResetTimer()
+			for range b.N {
+				mergeK(lists, cmp.Compare[int])
+			}
+		})
+	}
+}
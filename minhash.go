@@ -0,0 +1,78 @@
+package smallset
+
+import (
+	"iter"
+	"math"
+)
+
+// MinHash is a MinHash signature of a set, made of the minimum of k different hashes over
+// its elements. The fraction of positions where the signatures of two sets agree estimates
+// their Jaccard similarity |A ∩ B| / |A ∪ B|, with a standard error of about 1/sqrt(k),
+// without merging the sets. It's meant for comparing many sets, like clustering them by similarity.
+//
+// Signatures are computed with [Ordered.Sketch] or [Custom.Sketch]. The hashes don't depend on
+// the process, so signatures computed with the same k and seed on different machines can be compared.
+type MinHash struct {
+	seed uint64
+	mins []uint64
+}
+
+// newMinHash returns the signature with k hashes of the elements with the provided base hashes.
+func newMinHash(k int, seed uint64, hashes iter.Seq[uint64]) MinHash {
+	m := MinHash{seed: seed, mins: make([]uint64, k)}
+	for i := range m.mins {
+		m.mins[i] = math.MaxUint64
+	}
+
+	for h := range hashes {
+		for i := range m.mins {
+			// derive the i-th hash function by mixing the base hash with a different key
+			hi := mix(h ^ (seed + uint64(i+1)*0x9e3779b97f4a7c15))
+			m.mins[i] = min(m.mins[i], hi)
+		}
+	}
+	return m
+}
+
+// Size returns the number of hashes of the signature.
+func (m MinHash) Size() int {
+	return len(m.mins)
+}
+
+// Jaccard returns the estimated Jaccard similarity between the sets of the two signatures,
+// between 0 (disjoint) and 1 (equal). The similarity of two empty sets is 1.
+// It panics if the signatures were computed with a different k or seed.
+func (m MinHash) Jaccard(other MinHash) float64 {
+	if len(m.mins) != len(other.mins) || m.seed != other.seed {
+		panic("smallset.MinHash.Jaccard: signatures have a different k or seed")
+	}
+	if len(m.mins) == 0 {
+		return 1
+	}
+
+	equal := 0
+	for i := range m.mins {
+		if m.mins[i] == other.mins[i] {
+			equal++
+		}
+	}
+	return float64(equal) / float64(len(m.mins))
+}
+
+// MinHashFrom returns the signature with the provided seed and minimum hashes, as returned
+// by [MinHash.Seed] and [MinHash.Mins], for rebuilding a signature that was stored or sent.
+// The mins are copied.
+func MinHashFrom(seed uint64, mins []uint64) MinHash {
+	return MinHash{seed: seed, mins: append([]uint64(nil), mins...)}
+}
+
+// Seed returns the seed of the signature.
+func (m MinHash) Seed() uint64 {
+	return m.seed
+}
+
+// Mins returns a copy of the minimum hashes of the signature, for storing or sending it.
+// The signature can be rebuilt with [MinHashFrom].
+func (m MinHash) Mins() []uint64 {
+	return append([]uint64(nil), m.mins...)
+}
@@ -0,0 +1,73 @@
+package smallset
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestSketch(t *testing.T) {
+	cases := []struct {
+		s1, s2   [2]int // ranges [min, max)
+		expected float64
+	}{
+		{s1: [2]int{0, 1000}, s2: [2]int{0, 1000}, expected: 1},
+		{s1: [2]int{0, 1000}, s2: [2]int{1000, 2000}, expected: 0},
+		{s1: [2]int{0, 1000}, s2: [2]int{500, 1500}, expected: 1.0 / 3},
+		{s1: [2]int{0, 1000}, s2: [2]int{0, 500}, expected: 0.5},
+		{s1: [2]int{0, 0}, s2: [2]int{0, 0}, expected: 1},
+	}
+
+	const k = 512
+	for i, test := range cases {
+		t.Run(fmt.Sprintf("Case_%d", i), func(t *testing.T) {
+			s1, s2 := New[int](10), New[int](10)
+			for e := test.s1[0]; e < test.s1[1]; e++ {
+				s1.Add(e)
+			}
+			for e := test.s2[0]; e < test.s2[1]; e++ {
+				s2.Add(e)
+			}
+
+			jaccard := s1.Sketch(k, 42).Jaccard(s2.Sketch(k, 42))
+			if math.Abs(jaccard-test.expected) > 4/math.Sqrt(k) {
+				t.Errorf("expected Jaccard about %f, got %f", test.expected, jaccard)
+			}
+		})
+	}
+}
+
+func TestMinHashFrom(t *testing.T) {
+	s1, s2 := NewRange(0, 1000, 1), NewRange(500, 1500, 1)
+	m1, m2 := s1.Sketch(128, 7), s2.Sketch(128, 7)
+
+	mins := m2.Mins()
+	received := MinHashFrom(m2.Seed(), mins)
+	mins[0] = 0 // the mins are copied
+
+	if received.Size() != 128 || received.Seed() != 7 {
+		t.Fatalf("expected size 128 and seed 7, got %d and %d", received.Size(), received.Seed())
+	}
+	if j := m1.Jaccard(received); j != m1.Jaccard(m2) {
+		t.Errorf("expected Jaccard %f, got %f", m1.Jaccard(m2), j)
+	}
+}
+
+func TestSketchCustom(t *testing.T) {
+	encode := func(dst []byte, p Person) []byte {
+		return binary.BigEndian.AppendUint64(dst, uint64(p.ID))
+	}
+
+	s := CustomFrom(PersonCmp, people1...)
+	if j := s.Sketch(64, 1, encode).Jaccard(s.Clone().Sketch(64, 1, encode)); j != 1 {
+		t.Errorf("expected equal sets to have Jaccard 1, got %f", j)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic for signatures with different seeds")
+		}
+	}()
+	s.Sketch(64, 1, encode).Jaccard(s.Sketch(64, 2, encode))
+}
@@ -0,0 +1,364 @@
+package smallset
+
+import (
+	"cmp"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+)
+
+// MessagePack type bytes, see https://github.com/msgpack/msgpack/blob/master/spec.md
+const (
+	msgpackNil      = 0xc0
+	msgpackFloat32  = 0xca
+	msgpackFloat64  = 0xcb
+	msgpackUint8    = 0xcc
+	msgpackUint16   = 0xcd
+	msgpackUint32   = 0xce
+	msgpackUint64   = 0xcf
+	msgpackInt8     = 0xd0
+	msgpackInt16    = 0xd1
+	msgpackInt32    = 0xd2
+	msgpackInt64    = 0xd3
+	msgpackStr8     = 0xd9
+	msgpackStr16    = 0xda
+	msgpackStr32    = 0xdb
+	msgpackArray16  = 0xdc
+	msgpackArray32  = 0xdd
+	msgpackFixArray = 0x90
+	msgpackFixStr   = 0xa0
+)
+
+// MarshalMsg appends the MessagePack encoding of the set to b, as an array of its elements
+// in ascending order, and returns the extended slice. Integers use the smallest encoding that fits.
+// The method set of MarshalMsg, UnmarshalMsg and Msgsize matches the interfaces of
+// github.com/tinylib/msgp, so that sets can be fields of structs with generated serializers.
+// The error is always nil.
+func (s *Ordered[T]) MarshalMsg(b []byte) ([]byte, error) {
+	defer s.pin()()
+	b = appendMsgpackArray(b, len(s.items))
+	for _, e := range s.items {
+		b = appendMsgpackOrdered(b, e)
+	}
+	return b, nil
+}
+
+// UnmarshalMsg replaces the elements of the set with the ones of the MessagePack array
+// at the start of data, and returns the remaining bytes. A MessagePack nil clears the set.
+// Elements can be in any order, and duplicates are discarded.
+// It returns [ErrInvalidFormat] if the data is malformed, or if an element doesn't fit in T.
+// If an error is returned, the set is left unchanged.
+func (s *Ordered[T]) UnmarshalMsg(data []byte) ([]byte, error) {
+	count, data, err := readMsgpackArray(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// every element takes at least one byte
+	items := make([]T, 0, min(count, len(data)))
+	for range count {
+		var e T
+		e, data, err = readMsgpackOrdered[T](data)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, e)
+	}
+
+	slices.Sort(items)
+	s.reset(compact(items))
+	return data, nil
+}
+
+// Msgsize returns an upper bound of the size of the MessagePack encoding of the set.
+func (s *Ordered[T]) Msgsize() int {
+	size := 5
+	if reflectKind[T]() != reflect.String {
+		return size + 9*s.Size()
+	}
+
+	for _, e := range s.Ascend() {
+		size += 5 + len(reflect.ValueOf(e).String())
+	}
+	return size
+}
+
+// AppendMsgpack appends the MessagePack encoding of the set to dst, as an array of its elements
+// in the order of the set, and returns the extended slice.
+// The encode function must append the MessagePack encoding of e to dst and return the extended slice.
+func (s *Custom[T]) AppendMsgpack(dst []byte, encode func(dst []byte, e T) []byte) []byte {
+	defer s.pin()()
+	dst = appendMsgpackArray(dst, len(s.items))
+	for _, e := range s.items {
+		dst = encode(dst, e)
+	}
+	return dst
+}
+
+// UnmarshalMsgpack replaces the elements of the set with the ones of the MessagePack array
+// at the start of data, and returns the remaining bytes. A MessagePack nil clears the set.
+// The decode function must read an element from the start of data and return the remaining bytes.
+// Elements can be in any order, and duplicates are discarded.
+// It returns [ErrInvalidFormat] if the data is malformed, wrapping the errors of decode.
+// If an error is returned, the set is left unchanged.
+func (s *Custom[T]) UnmarshalMsgpack(data []byte, decode func(data []byte) (T, []byte, error)) ([]byte, error) {
+	count, data, err := readMsgpackArray(data)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]T, 0, min(count, len(data)))
+	for range count {
+		var e T
+		e, data, err = decode(data)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidFormat, err)
+		}
+		items = append(items, e)
+	}
+
+	slices.SortFunc(items, s.cmp)
+	s.reset(slices.CompactFunc(items, s.cmp.equal))
+	return data, nil
+}
+
+func appendMsgpackArray(dst []byte, n int) []byte {
+	switch {
+	case n < 16:
+		return append(dst, msgpackFixArray|byte(n))
+	case n <= math.MaxUint16:
+		dst = append(dst, msgpackArray16)
+		return binary.BigEndian.AppendUint16(dst, uint16(n))
+	default:
+		dst = append(dst, msgpackArray32)
+		return binary.BigEndian.AppendUint32(dst, uint32(n))
+	}
+}
+
+// appendMsgpackOrdered appends the MessagePack encoding of e to dst, and returns the extended slice.
+func appendMsgpackOrdered[T cmp.Ordered](dst []byte, e T) []byte {
+	v := reflect.ValueOf(e)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return appendMsgpackInt(dst, v.Int())
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return appendMsgpackUint(dst, v.Uint())
+
+	case reflect.Float32:
+		dst = append(dst, msgpackFloat32)
+		return binary.BigEndian.AppendUint32(dst, math.Float32bits(float32(v.Float())))
+
+	case reflect.Float64:
+		dst = append(dst, msgpackFloat64)
+		return binary.BigEndian.AppendUint64(dst, math.Float64bits(v.Float()))
+
+	case reflect.String:
+		s := v.String()
+		switch {
+		case len(s) < 32:
+			dst = append(dst, msgpackFixStr|byte(len(s)))
+		case len(s) <= math.MaxUint8:
+			dst = append(dst, msgpackStr8, byte(len(s)))
+		case len(s) <= math.MaxUint16:
+			dst = append(dst, msgpackStr16)
+			dst = binary.BigEndian.AppendUint16(dst, uint16(len(s)))
+		default:
+			dst = append(dst, msgpackStr32)
+			dst = binary.BigEndian.AppendUint32(dst, uint32(len(s)))
+		}
+		return append(dst, s...)
+
+	default:
+		panic("smallset.appendMsgpackOrdered: unsupported kind " + v.Kind().String())
+	}
+}
+
+func appendMsgpackInt(dst []byte, x int64) []byte {
+	switch {
+	case x >= 0:
+		return appendMsgpackUint(dst, uint64(x))
+	case x >= -32:
+		return append(dst, byte(x)) // negative fixint
+	case x >= math.MinInt8:
+		return append(dst, msgpackInt8, byte(x))
+	case x >= math.MinInt16:
+		dst = append(dst, msgpackInt16)
+		return binary.BigEndian.AppendUint16(dst, uint16(x))
+	case x >= math.MinInt32:
+		dst = append(dst, msgpackInt32)
+		return binary.BigEndian.AppendUint32(dst, uint32(x))
+	default:
+		dst = append(dst, msgpackInt64)
+		return binary.BigEndian.AppendUint64(dst, uint64(x))
+	}
+}
+
+func appendMsgpackUint(dst []byte, x uint64) []byte {
+	switch {
+	case x <= math.MaxInt8:
+		return append(dst, byte(x)) // positive fixint
+	case x <= math.MaxUint8:
+		return append(dst, msgpackUint8, byte(x))
+	case x <= math.MaxUint16:
+		dst = append(dst, msgpackUint16)
+		return binary.BigEndian.AppendUint16(dst, uint16(x))
+	case x <= math.MaxUint32:
+		dst = append(dst, msgpackUint32)
+		return binary.BigEndian.AppendUint32(dst, uint32(x))
+	default:
+		dst = append(dst, msgpackUint64)
+		return binary.BigEndian.AppendUint64(dst, x)
+	}
+}
+
+// readMsgpackArray reads the header of an array or a nil, returning its number of elements
+// and the remaining bytes.
+func readMsgpackArray(data []byte) (int, []byte, error) {
+	if len(data) == 0 {
+		return 0, nil, fmt.Errorf("%w: missing msgpack array", ErrInvalidFormat)
+	}
+
+	switch b := data[0]; {
+	case b == msgpackNil:
+		return 0, data[1:], nil
+	case b&0xf0 == msgpackFixArray:
+		return int(b & 0x0f), data[1:], nil
+	case b == msgpackArray16 || b == msgpackArray32:
+		return readMsgpackLength(data)
+	default:
+		return 0, nil, fmt.Errorf("%w: msgpack type 0x%02x is not an array", ErrInvalidFormat, b)
+	}
+}
+
+// readMsgpackLength reads the big-endian length following the type byte of a 16 or 32 bits
+// array or string header, returning it and the remaining bytes.
+func readMsgpackLength(data []byte) (int, []byte, error) {
+	size := 2
+	if data[0] == msgpackArray32 || data[0] == msgpackStr32 {
+		size = 4
+	}
+	if len(data) < 1+size {
+		return 0, nil, fmt.Errorf("%w: truncated msgpack length", ErrInvalidFormat)
+	}
+
+	if size == 2 {
+		return int(binary.BigEndian.Uint16(data[1:])), data[3:], nil
+	}
+	return int(binary.BigEndian.Uint32(data[1:])), data[5:], nil
+}
+
+// readMsgpackOrdered reads an element from the start of data, returning it and the remaining bytes.
+func readMsgpackOrdered[T cmp.Ordered](data []byte) (T, []byte, error) {
+	var e T
+	if len(data) == 0 {
+		return e, nil, fmt.Errorf("%w: missing msgpack element", ErrInvalidFormat)
+	}
+
+	v := reflect.ValueOf(&e).Elem()
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		x, negative, rest, err := readMsgpackInt(data)
+		if err != nil {
+			return e, nil, err
+		}
+		if (!negative && x > math.MaxInt64) || v.OverflowInt(int64(x)) {
+			return e, nil, fmt.Errorf("%w: msgpack integer overflows %s", ErrInvalidFormat, v.Type())
+		}
+		v.SetInt(int64(x))
+		return e, rest, nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		x, negative, rest, err := readMsgpackInt(data)
+		if err != nil {
+			return e, nil, err
+		}
+		if negative || v.OverflowUint(x) {
+			return e, nil, fmt.Errorf("%w: msgpack integer overflows %s", ErrInvalidFormat, v.Type())
+		}
+		v.SetUint(x)
+		return e, rest, nil
+
+	case reflect.Float32, reflect.Float64:
+		switch {
+		case data[0] == msgpackFloat32 && len(data) >= 5:
+			v.SetFloat(float64(math.Float32frombits(binary.BigEndian.Uint32(data[1:]))))
+			return e, data[5:], nil
+		case data[0] == msgpackFloat64 && len(data) >= 9:
+			v.SetFloat(math.Float64frombits(binary.BigEndian.Uint64(data[1:])))
+			return e, data[9:], nil
+		default:
+			return e, nil, fmt.Errorf("%w: invalid msgpack float", ErrInvalidFormat)
+		}
+
+	case reflect.String:
+		var n int
+		var rest []byte
+		switch b := data[0]; {
+		case b&0xe0 == msgpackFixStr:
+			n, rest = int(b&0x1f), data[1:]
+		case b == msgpackStr8 && len(data) >= 2:
+			n, rest = int(data[1]), data[2:]
+		case b == msgpackStr16 || b == msgpackStr32:
+			var err error
+			if n, rest, err = readMsgpackLength(data); err != nil {
+				return e, nil, err
+			}
+		default:
+			return e, nil, fmt.Errorf("%w: invalid msgpack string", ErrInvalidFormat)
+		}
+
+		if len(rest) < n {
+			return e, nil, fmt.Errorf("%w: truncated msgpack string", ErrInvalidFormat)
+		}
+		v.SetString(string(rest[:n]))
+		return e, rest[n:], nil
+
+	default:
+		panic("smallset.readMsgpackOrdered: unsupported kind " + v.Kind().String())
+	}
+}
+
+// readMsgpackInt reads an integer of any width and signedness from the start of data,
+// returning it as a uint64 and whether it's negative, in which case int64(x) is its value.
+func readMsgpackInt(data []byte) (x uint64, negative bool, rest []byte, err error) {
+	b := data[0]
+	switch {
+	case b <= math.MaxInt8:
+		return uint64(b), false, data[1:], nil
+	case b >= 0xe0:
+		return uint64(int64(int8(b))), true, data[1:], nil
+	}
+
+	var size int
+	switch b {
+	case msgpackUint8, msgpackInt8:
+		size = 1
+	case msgpackUint16, msgpackInt16:
+		size = 2
+	case msgpackUint32, msgpackInt32:
+		size = 4
+	case msgpackUint64, msgpackInt64:
+		size = 8
+	default:
+		return 0, false, nil, fmt.Errorf("%w: invalid msgpack integer", ErrInvalidFormat)
+	}
+	if len(data) < 1+size {
+		return 0, false, nil, fmt.Errorf("%w: truncated msgpack integer", ErrInvalidFormat)
+	}
+
+	var u uint64
+	for _, c := range data[1 : 1+size] {
+		u = u<<8 | uint64(c)
+	}
+
+	if b >= msgpackInt8 {
+		// sign extend
+		shift := 64 - 8*size
+		i := int64(u<<shift) >> shift
+		return uint64(i), i < 0, data[1+size:], nil
+	}
+	return u, false, data[1+size:], nil
+}
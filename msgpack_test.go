@@ -0,0 +1,154 @@
+package smallset
+
+import (
+	"bytes"
+	"cmp"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestMarshalMsg(t *testing.T) {
+	cases := []struct {
+		set      *Ordered[int]
+		expected []byte
+	}{
+		{set: New[int](10), expected: []byte{0x90}},
+		{set: From(1, -1, 200), expected: []byte{0x93, 0xff, 0x01, 0xcc, 0xc8}},
+		{set: From(-33, 1<<16), expected: []byte{0x92, 0xd0, 0xdf, 0xce, 0x00, 0x01, 0x00, 0x00}},
+	}
+
+	for i, test := range cases {
+		t.Run(fmt.Sprintf("Case_%d", i), func(t *testing.T) {
+			data, err := test.set.MarshalMsg(nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(data, test.expected) {
+				t.Errorf("expected % x, got % x", test.expected, data)
+			}
+		})
+	}
+}
+
+func TestMsgpackRoundTrip(t *testing.T) {
+	t.Run("int64", func(t *testing.T) {
+		set := From[int64](math.MinInt64, math.MinInt32-1, math.MinInt16, -129, -32, 0, 127, 128, math.MaxUint16+1, math.MaxInt64)
+		testMsgpackRoundTrip(t, set)
+	})
+
+	t.Run("uint64", func(t *testing.T) {
+		testMsgpackRoundTrip(t, From[uint64](0, 255, 256, math.MaxUint32, math.MaxUint64))
+	})
+
+	t.Run("float32", func(t *testing.T) {
+		testMsgpackRoundTrip(t, From[float32](-1.5, 0, 3.25))
+	})
+
+	t.Run("string", func(t *testing.T) {
+		set := New[string](10)
+		for _, n := range []int{0, 31, 32, 255, 256, 70000} {
+			set.Add(strings.Repeat("a", n))
+		}
+		testMsgpackRoundTrip(t, set)
+	})
+
+	t.Run("array16", func(t *testing.T) {
+		testMsgpackRoundTrip(t, NewRange(0, 1000, 3))
+	})
+}
+
+func testMsgpackRoundTrip[T cmp.Ordered](t *testing.T, set *Ordered[T]) {
+	data, err := set.MarshalMsg(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) > set.Msgsize() {
+		t.Errorf("encoding is %d bytes, more than Msgsize %d", len(data), set.Msgsize())
+	}
+
+	trailer := []byte{0xc0}
+	other := New[T](10)
+	rest, err := other.UnmarshalMsg(append(data, trailer...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(rest, trailer) {
+		t.Errorf("expected the remaining bytes % x, got % x", trailer, rest)
+	}
+	if !slices.Equal(set.items, other.items) {
+		t.Errorf("expected %v, got %v", set.items, other.items)
+	}
+}
+
+func TestUnmarshalMsg(t *testing.T) {
+	cases := []struct {
+		data     []byte
+		expected []uint8
+		err      bool
+	}{
+		{data: []byte{0xc0}, expected: nil},
+		{data: []byte{0x93, 0x03, 0x01, 0x03}, expected: []uint8{1, 3}},
+		{data: []byte{0x91, 0xcc, 0xff}, expected: []uint8{255}},
+		{data: []byte{0x91, 0xcd, 0x01, 0x00}, err: true}, // overflow
+		{data: []byte{0x91, 0xff}, err: true},             // negative
+		{data: []byte{0x92, 0x01}, err: true},             // truncated array
+		{data: []byte{0x91, 0xcd, 0x01}, err: true},       // truncated integer
+		{data: []byte{0x91, 0xa1, 0x61}, err: true},       // string
+		{data: []byte{0x81, 0x01, 0x01}, err: true},       // map
+		{data: nil, err: true},
+	}
+
+	for i, test := range cases {
+		t.Run(fmt.Sprintf("Case_%d", i), func(t *testing.T) {
+			set := From[uint8](7)
+			_, err := set.UnmarshalMsg(test.data)
+			if (err != nil) != test.err {
+				t.Fatalf("expected error %v, got %v", test.err, err)
+			}
+
+			if err != nil {
+				if !errors.Is(err, ErrInvalidFormat) {
+					t.Errorf("expected ErrInvalidFormat, got %v", err)
+				}
+				if !slices.Equal(set.items, []uint8{7}) {
+					t.Errorf("expected the set to be unchanged, got %v", set.items)
+				}
+				return
+			}
+
+			if !slices.Equal(set.items, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, set.items)
+			}
+		})
+	}
+}
+
+func TestCustomMsgpack(t *testing.T) {
+	encode := func(dst []byte, p Person) []byte { return appendMsgpackInt(dst, int64(p.ID)) }
+	decode := func(data []byte) (Person, []byte, error) {
+		id, rest, err := readMsgpackOrdered[int](data)
+		return Person{ID: id}, rest, err
+	}
+
+	set := CustomFrom(PersonCmp, Person{ID: 3}, Person{ID: 1})
+	data := set.AppendMsgpack(nil, encode)
+	if !bytes.Equal(data, []byte{0x92, 0x01, 0x03}) {
+		t.Fatalf("unexpected encoding % x", data)
+	}
+
+	other := NewCustom(PersonCmp, 10)
+	if _, err := other.UnmarshalMsgpack(data, decode); err != nil {
+		t.Fatal(err)
+	}
+	if other.Size() != 2 || other.Min().ID != 1 || other.Max().ID != 3 {
+		t.Errorf("unexpected set %v", other.items)
+	}
+
+	if _, err := other.UnmarshalMsgpack([]byte{0x91, 0xa0}, decode); !errors.Is(err, ErrInvalidFormat) {
+		t.Errorf("expected ErrInvalidFormat, got %v", err)
+	}
+}
@@ -2,19 +2,71 @@ package smallset
 
 import (
 	"cmp"
+	"container/heap"
 	"fmt"
+	"hash"
 	"iter"
+	"math/bits"
+	"math/rand/v2"
 	"slices"
+	"sort"
 )
 
 var defaultCapacity int = 10
 
+// Integer is a constraint that permits any integer type.
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// isNaN reports whether x is a floating point NaN. It's always false for the other types.
+func isNaN[T cmp.Ordered](x T) bool {
+	return x != x
+}
+
+// equal reports whether a and b are equal, considering NaNs equal to each other like [cmp.Compare].
+func equal[T cmp.Ordered](a, b T) bool {
+	return a == b || (isNaN(a) && isNaN(b))
+}
+
+// compact replaces consecutive runs of equal elements in the sorted slice with a single copy,
+// considering NaNs equal to each other like [cmp.Compare]. It's [slices.Compact] for sets.
+func compact[T cmp.Ordered](items []T) []T {
+	items = slices.Compact(items)
+
+	// NaNs are sorted first, and are never equal according to slices.Compact
+	nans := 0
+	for nans < len(items) && isNaN(items[nans]) {
+		nans++
+	}
+	if nans > 1 {
+		items = slices.Delete(items, 1, nans)
+	}
+	return items
+}
+
 // Ordered is a slice-based set sorted in ascending order.
 // It's more performant that a map based approach for small collections (< 1000) of ordered types.
 // The capacity of the set can dynamically grow, but the performance would start to deteriorate.
 // Not safe for concurrent use.
+//
+// Floating point elements follow the ordering of [cmp.Compare]: NaNs are sorted before any
+// other value and are all equal to each other, so a set contains at most one NaN.
+// Likewise -0.0 and 0.0 are equal, so a set contains at most one of them.
 type Ordered[T cmp.Ordered] struct {
-	items []T
+	items      []T
+	tombstones []T       // elements marked for removal, see [Ordered.MarkRemove]
+	shared     bool      // whether items is shared with a snapshot, see [Ordered.Snapshot]
+	owner      owner     // goroutine that owns the set in debug builds, see [Ordered.Handoff]
+	shrink     float64   // shrink threshold of len/cap, see [Ordered.WithShrink]
+	growth     Growth    // see [Ordered.WithGrowth]
+	store      *store[T] // nil unless the set is backed by a [Storage], see [NewStored]
+
+	// optional structures, nil unless enabled
+	bloom       *bloom[T]       // see [Ordered.WithBloom]
+	fingerprint *fingerprint[T] // see [Ordered.WithFingerprint]
+	changelog   *changelog[T]   // see [Ordered.WithChangeLog]
 }
 
 // New returns an initialized set with the provided capacity.
@@ -37,23 +89,274 @@ func From[T cmp.Ordered](items ...T) *Ordered[T] {
 
 	copy := slices.Clone(items)
 	slices.Sort(copy)
-	copy = slices.Compact(copy)
+	copy = compact(copy)
 	return &Ordered[T]{items: copy}
 }
 
+// SortedKeys returns a set that contains the keys of the map, for iterating it deterministically.
+func SortedKeys[K cmp.Ordered, V any](m map[K]V) *Ordered[K] {
+	if len(m) == 0 {
+		return New[K](defaultCapacity)
+	}
+
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	// map keys are unique, but NaNs can be repeated since they are not equal to themselves
+	slices.Sort(keys)
+	return &Ordered[K]{items: compact(keys)}
+}
+
+// AsMap returns a map pre-sized for the set, associating each element with the result of value,
+// for passing the set to map-shaped APIs. It panics if value is nil.
+func AsMap[T cmp.Ordered, V any](s *Ordered[T], value func(T) V) map[T]V {
+	if value == nil {
+		panic("smallset.AsMap: value cannot be nil")
+	}
+
+	defer s.pin()()
+	m := make(map[T]V, len(s.items))
+	for _, e := range s.items {
+		m[e] = value(e)
+	}
+	return m
+}
+
+// AsKeySet returns a map pre-sized for the set whose keys are its elements,
+// for passing the set to APIs that expect a map-based set.
+func AsKeySet[T cmp.Ordered](s *Ordered[T]) map[T]struct{} {
+	defer s.pin()()
+	m := make(map[T]struct{}, len(s.items))
+	for _, e := range s.items {
+		m[e] = struct{}{}
+	}
+	return m
+}
+
+// Adopt returns a set that takes ownership of the provided slice, sorting and deduplicating it
+// in place. Unlike [From], it doesn't copy the elements, which keeps big bulk loads allocation-free.
+// The set aliases the slice: the caller must not use it after the call, because its order,
+// its length and the elements past the new length are changed, and later set operations
+// change it further.
+func Adopt[T cmp.Ordered](items []T) *Ordered[T] {
+	slices.Sort(items)
+	return &Ordered[T]{items: compact(items)}
+}
+
+// NewRange returns an initialized set that contains the arithmetic sequence
+// start, start+step, start+2*step... of all the values < end.
+// The values are generated directly in sorted order, without sorting nor intermediate slices.
+// It returns an empty set if end <= start, and panics if step is <= 0.
+func NewRange[T Integer](start, end, step T) *Ordered[T] {
+	if step <= 0 {
+		panic("smallset.NewRange: step must be > 0")
+	}
+
+	size := 0
+	for v := start; v < end; v += step {
+		size++
+		if v+step < v {
+			// overflow
+			break
+		}
+	}
+
+	if size == 0 {
+		return New[T](defaultCapacity)
+	}
+
+	items := make([]T, size)
+	for i := range items {
+		items[i] = start + T(i)*step
+	}
+	return &Ordered[T]{items: items}
+}
+
+// FromSeq returns an initialized set that contains the elements of the sequence.
+// The elements are sorted once at the end, which is skipped altogether if the sequence
+// is already in ascending order (e.g. when draining a min-heap).
+func FromSeq[T cmp.Ordered](seq iter.Seq[T]) *Ordered[T] {
+	items := slices.Collect(seq)
+	if len(items) == 0 {
+		return New[T](defaultCapacity)
+	}
+
+	if !slices.IsSorted(items) {
+		slices.Sort(items)
+	}
+	items = compact(items)
+	return &Ordered[T]{items: items}
+}
+
+// FromHeap returns an initialized set that contains the elements of the heap,
+// which is drained by repeatedly calling [heap.Pop].
+// It panics if an element of the heap is not of type T.
+func FromHeap[T cmp.Ordered](h heap.Interface) *Ordered[T] {
+	return FromSeq(drain[T](h))
+}
+
+// WithBloom attaches to the set a Bloom filter of the provided number of bits,
+// which is consulted before the binary search in [Ordered.Contains].
+// It speeds up workloads where most lookups are misses, at the cost of slower insertions.
+// A good number of bits is about 10 times the expected size of the set.
+//
+// Removed elements can't be deleted from the filter, so frequent removals degrade its
+// effectiveness until the set is cleared. The filter is copied by [Ordered.Clone], but not into
+// the results of the set operations. It returns s, to allow chaining with the constructor.
+// It panics if bits is <= 0.
+func (s *Ordered[T]) WithBloom(bits int) *Ordered[T] {
+	if bits <= 0 {
+		panic("smallset.Ordered.WithBloom: bits must be > 0")
+	}
+
+	defer s.pin()()
+	s.bloom = newBloom[T](bits)
+	for _, e := range s.items {
+		s.bloom.add(e)
+	}
+	return s
+}
+
+// FromChan returns an initialized set that contains all the elements received from the channel.
+// It blocks until the channel is closed, then sorts the elements once.
+func FromChan[T cmp.Ordered](ch <-chan T) *Ordered[T] {
+	return FromSeq(receive(ch))
+}
+
+// WithFingerprint enables the incremental maintenance of the [Ordered.Fingerprint] of the set,
+// which becomes O(1) at the cost of hashing every inserted and removed element.
+// It returns s, to allow chaining with the constructor.
+func (s *Ordered[T]) WithFingerprint() *Ordered[T] {
+	defer s.pin()()
+	s.fingerprint = newFingerprint(hashOrdered[T], s.items)
+	return s
+}
+
+// WithShrink enables an auto-compaction policy, which reallocates the underlying slice to
+// twice the size of the set whenever a removal leaves its length below fraction * capacity.
+// It avoids stranding large capacity on long-lived sets pruned with range removals,
+// like [Ordered.RemoveBefore] or [Ordered.RemoveBetween], at the cost of a copy when it triggers.
+// It returns s, to allow chaining with the constructor.
+// It panics if fraction is not in (0, 0.5].
+func (s *Ordered[T]) WithShrink(fraction float64) *Ordered[T] {
+	if !(fraction > 0 && fraction <= 0.5) {
+		panic("smallset.Ordered.WithShrink: fraction must be in (0, 0.5]")
+	}
+
+	s.shrink = fraction
+	return s
+}
+
+// WithGrowth sets the strategy used to grow the underlying slice when an insertion exceeds
+// its capacity. By default it grows like the built-in append, which may over-allocate
+// for memory-tight workloads or reallocate too often for insert-heavy ones.
+// It returns s, to allow chaining with the constructor.
+func (s *Ordered[T]) WithGrowth(g Growth) *Ordered[T] {
+	s.growth = g
+	return s
+}
+
+// WithChangeLog enables the recording of every addition and removal to the set, which can be
+// replayed with [Ordered.Changes] instead of diffing snapshots of the set.
+// Operations that rebuild the set, like Load or ApplyDiff, record the net changes in ascending order.
+// The log grows with every change until it's trimmed with [Ordered.TrimChanges].
+// It returns s, to allow chaining with the constructor.
+func (s *Ordered[T]) WithChangeLog() *Ordered[T] {
+	if s.changelog == nil {
+		s.changelog = &changelog[T]{}
+	}
+	s.changelog.retain = true
+	return s
+}
+
+// ChangeSeq returns the sequence number of the last change recorded, or 0 if there is none.
+// It panics if the change log is not enabled with [Ordered.WithChangeLog].
+func (s *Ordered[T]) ChangeSeq() uint64 {
+	if !s.changelog.enabled() {
+		panic("smallset.Ordered.ChangeSeq: change log is not enabled")
+	}
+	return s.changelog.seq
+}
+
+// Changes returns an iterator over the recorded changes with a sequence number bigger than since,
+// in the order they happened. Use since = 0 to get all the changes that haven't been trimmed.
+// It panics if the change log is not enabled with [Ordered.WithChangeLog].
+func (s *Ordered[T]) Changes(since uint64) iter.Seq[Change[T]] {
+	if !s.changelog.enabled() {
+		panic("smallset.Ordered.Changes: change log is not enabled")
+	}
+	return s.changelog.since(since)
+}
+
+// TrimChanges discards the recorded changes with a sequence number smaller or equal than seq,
+// typically after all consumers have processed them.
+// It panics if the change log is not enabled with [Ordered.WithChangeLog].
+func (s *Ordered[T]) TrimChanges(seq uint64) {
+	if !s.changelog.enabled() {
+		panic("smallset.Ordered.TrimChanges: change log is not enabled")
+	}
+	s.changelog.trim(seq)
+}
+
+// DeltaSince returns the net changes recorded after seq, for replicas to pull incremental updates
+// instead of full snapshots: the elements added and the ones removed, both in ascending order,
+// and the sequence number to pass to the next call. Elements added and then removed, or vice versa,
+// are omitted.
+// A replica starts from a copy of the set and its [Ordered.ChangeSeq].
+// It returns [ErrChangesTrimmed] if some changes after seq have been trimmed, in which case the replica
+// must start over. It panics if the change log is not enabled with [Ordered.WithChangeLog].
+func (s *Ordered[T]) DeltaSince(seq uint64) (adds, removes []T, newSeq uint64, err error) {
+	if !s.changelog.enabled() {
+		panic("smallset.Ordered.DeltaSince: change log is not enabled")
+	}
+
+	adds, removes, err = s.changelog.delta(seq, cmp.Compare[T], false)
+	if err != nil {
+		return nil, nil, seq, err
+	}
+	return adds, removes, s.changelog.seq, nil
+}
+
+// Subscribe returns a channel that receives every addition and removal to the set, numbered
+// like the entries of the change log, so that caches or UI layers can react to changes without
+// polling the set. The channel has the provided buffer size, and policy decides what happens when
+// it's full. Subscribing doesn't enable the retention of the changes of [Ordered.WithChangeLog].
+//
+// The cancel function stops the subscription and closes the channel. Like the other methods,
+// it must be called by the goroutine that uses the set, and calling it more than once is a no-op.
+// Clones of the set don't inherit its subscriptions. It panics if buffer is < 0.
+func (s *Ordered[T]) Subscribe(buffer int, policy Backpressure) (changes <-chan Change[T], cancel func()) {
+	if buffer < 0 {
+		panic("smallset.Ordered.Subscribe: buffer must be >= 0")
+	}
+	if s.changelog == nil {
+		s.changelog = &changelog[T]{}
+	}
+	return s.changelog.subscribe(buffer, policy)
+}
+
 // Size returns the number of elements in the set.
 func (s *Ordered[T]) Size() int {
+	if s.stored() {
+		return s.store.storage.Len()
+	}
 	return len(s.items)
 }
 
-// Capacity returns the capacity of the underlying slice.
+// Capacity returns the capacity of the underlying slice,
+// or the size of the set if it's backed by a [Storage].
 func (s *Ordered[T]) Capacity() int {
+	if s.store != nil {
+		return s.Size()
+	}
 	return cap(s.items)
 }
 
 // IsEmpty returns whether the set has no elements.
 func (s *Ordered[T]) IsEmpty() bool {
-	return len(s.items) == 0
+	return s.Size() == 0
 }
 
 // Clear removes all elements from the set.
@@ -62,84 +365,760 @@ func (s *Ordered[T]) IsEmpty() bool {
 // and resets the length to 0. The underlying array capacity is preserved
 // to minimize allocations during future insertions.
 func (s *Ordered[T]) Clear() {
-	clear(s.items)
-	s.items = s.items[:0]
+	defer s.pin()()
+	if s.changelog != nil {
+		for _, e := range s.items {
+			s.changelog.record(OpRemove, e)
+		}
+	}
+	s.owner.check("Ordered")
+	s.store.touch()
+	if s.shared {
+		// the snapshots still read the elements, so they can't be zeroed
+		s.items = make([]T, 0, cap(s.items))
+		s.shared = false
+	} else {
+		clear(s.items)
+		s.items = s.items[:0]
+	}
+	clear(s.tombstones)
+	s.tombstones = s.tombstones[:0]
+	if s.bloom != nil {
+		s.bloom.reset()
+	}
+	if s.fingerprint != nil {
+		s.fingerprint.sum = 0
+	}
+}
+
+// ClearAndShrink removes all elements from the set like [Ordered.Clear], and reallocates the
+// underlying slice if its capacity exceeds the provided one. It's meant for resetting pooled
+// sets to a known memory footprint, after they grew to serve a big request.
+// It panics if the capacity is <= 0.
+func (s *Ordered[T]) ClearAndShrink(capacity int) {
+	if capacity <= 0 {
+		panic("smallset.Ordered.ClearAndShrink: capacity must be > 0")
+	}
+
+	s.Clear()
+	if cap(s.items) > capacity {
+		s.items = make([]T, 0, capacity)
+	}
+	s.tombstones = nil
+}
+
+// insert e at index i, keeping the optional structures of the set up to date.
+func (s *Ordered[T]) insert(i int, e T) {
+	s.own()
+	if s.stored() {
+		s.store.storage.Insert(i, e)
+	} else {
+		s.items = insertGrow(s.growth, s.items, i, e)
+	}
+	s.inserted(e)
+}
+
+// Handoff releases the ownership of the set by the current goroutine, which is only tracked in
+// debug builds (built with -tags smallsetdebug), so that another goroutine can modify it.
+// In debug builds, the first goroutine that modifies a set becomes its owner, and modifications
+// from other goroutines panic. Call Handoff before passing the set to another goroutine,
+// for example over a channel. It's a no-op in normal builds.
+func (s *Ordered[T]) Handoff() {
+	s.owner.release()
+}
+
+// own takes ownership of the items before they are modified, by copying them if they are shared
+// with a snapshot. In debug builds, it also panics if the set is owned by another goroutine.
+func (s *Ordered[T]) own() {
+	s.owner.check("Ordered")
+	s.store.touch()
+	if !s.shared {
+		return
+	}
+	items := make([]T, len(s.items), cap(s.items))
+	copy(items, s.items)
+	s.items = items
+	s.shared = false
+}
+
+// inserted updates the optional structures of the set after the insertion of e.
+func (s *Ordered[T]) inserted(e T) {
+	if s.changelog != nil {
+		s.changelog.record(OpAdd, e)
+	}
+	if s.bloom != nil {
+		s.bloom.add(e)
+	}
+	if s.fingerprint != nil {
+		s.fingerprint.add(e)
+	}
+}
+
+// removed updates the optional structures of the set after the removal of e.
+func (s *Ordered[T]) removed(e T) {
+	if s.fingerprint != nil {
+		s.fingerprint.remove(e)
+	}
+	if s.changelog != nil {
+		s.changelog.record(OpRemove, e)
+	}
+}
+
+// replace the element at index i with e, keeping the optional structures of the set up to date.
+// It assumes e preserves the sort order.
+func (s *Ordered[T]) replace(i int, e T) {
+	s.own()
+	s.removed(s.at(i))
+	s.inserted(e)
+	if s.stored() {
+		s.store.storage.DeleteRange(i, i+1)
+		s.store.storage.Insert(i, e)
+		return
+	}
+	s.items[i] = e
+}
+
+// delete removes the elements in [i, j), keeping the optional structures of the set up to date.
+func (s *Ordered[T]) delete(i, j int) {
+	s.own()
+	if s.fingerprint != nil || s.changelog != nil {
+		for k := i; k < j; k++ {
+			s.removed(s.at(k))
+		}
+	}
+	if s.stored() {
+		s.store.storage.DeleteRange(i, j)
+		return
+	}
+	s.items = slices.Delete(s.items, i, j)
+	s.maybeShrink()
+}
+
+// deleteFunc removes the elements for which del returns true in a single pass, keeping the
+// optional structures of the set up to date. The elements are passed to del in ascending order.
+// Returns num removed.
+func (s *Ordered[T]) deleteFunc(del func(e T) bool) int {
+	defer s.pin()()
+	s.own()
+	size := len(s.items)
+	s.items = slices.DeleteFunc(s.items, func(e T) bool {
+		if !del(e) {
+			return false
+		}
+		s.removed(e)
+		return true
+	})
+	s.maybeShrink()
+	return size - len(s.items)
+}
+
+// pin makes the elements of a set backed by a [Storage] available in its slice until the returned
+// function is called, for the methods that work on the whole set. See [store].
+// It's a no-op for the other sets, and for the sets that are already pinned.
+func (s *Ordered[T]) pin() (unpin func()) {
+	if s.store == nil || s.store.pinned {
+		return noop
+	}
+
+	s.items = s.store.pin()
+	return func() {
+		s.store.unpin(s.items)
+		s.items = nil
+	}
+}
+
+// stored reports whether the elements of the set are in its storage, rather than in its slice.
+func (s *Ordered[T]) stored() bool {
+	return s.store != nil && !s.store.pinned
 }
 
-// Clone returns a clone of the set.
+// search returns the index of e, or the index where it would be inserted, and whether it's present.
+func (s *Ordered[T]) search(e T) (int, bool) {
+	if s.stored() {
+		return s.store.storage.Search(e, s.store.cmp)
+	}
+	return slices.BinarySearch(s.items, e)
+}
+
+// at returns the element at index i.
+func (s *Ordered[T]) at(i int) T {
+	if s.stored() {
+		return s.store.storage.At(i)
+	}
+	return s.items[i]
+}
+
+// appendRange appends to dst the elements with index in [i, j).
+func (s *Ordered[T]) appendRange(dst []T, i, j int) []T {
+	if s.stored() {
+		for k := i; k < j; k++ {
+			dst = append(dst, s.store.storage.At(k))
+		}
+		return dst
+	}
+	return append(dst, s.items[i:j]...)
+}
+
+// maybeShrink reallocates the items to twice their length if the shrink policy is enabled
+// and the length is below the shrink fraction of the capacity.
+func (s *Ordered[T]) maybeShrink() {
+	if s.shrink == 0 || float64(len(s.items)) >= s.shrink*float64(cap(s.items)) {
+		return
+	}
+
+	capacity := max(2*len(s.items), 1)
+	if capacity >= cap(s.items) {
+		return
+	}
+
+	items := make([]T, len(s.items), capacity)
+	copy(items, s.items)
+	s.items = items
+}
+
+// reset replaces the elements of the set with items, which must be sorted and unique,
+// keeping the optional structures of the set up to date.
+func (s *Ordered[T]) reset(items []T) {
+	defer s.pin()()
+	if s.changelog != nil {
+		s.changelog.recordDiff(s.items, items, cmp.Compare[T])
+	}
+	s.owner.check("Ordered")
+	s.store.touch()
+	if !s.shared {
+		clear(s.items)
+	}
+	s.items = items
+	s.shared = false
+	if s.bloom != nil {
+		s.bloom.reset()
+		for _, e := range items {
+			s.bloom.add(e)
+		}
+	}
+	if s.fingerprint != nil {
+		s.fingerprint = newFingerprint(s.fingerprint.hash, items)
+	}
+}
+
+// Clone returns a clone of the set, including its optional structures.
+// The sets returned by the set operations, like [Ordered.Union], never include them.
+// The clone of a set backed by a [Storage] has a clone of the storage, if it can be cloned.
 func (s *Ordered[T]) Clone() *Ordered[T] {
-	return &Ordered[T]{
-		items: slices.Clone(s.items),
+	clone := &Ordered[T]{
+		bloom:       s.bloom.clone(),
+		fingerprint: s.fingerprint.clone(),
+		changelog:   s.changelog.clone(),
+		shrink:      s.shrink,
+		growth:      s.growth,
+	}
+
+	if s.stored() {
+		if clone.store = s.store.clone(); clone.store != nil {
+			return clone
+		}
 	}
+
+	defer s.pin()()
+	clone.items = slices.Clone(s.items)
+	return clone
+}
+
+// cloneItems returns a new set with a copy of the elements of the set but none of its optional
+// structures, like the results of the set operations.
+func (s *Ordered[T]) cloneItems() *Ordered[T] {
+	return &Ordered[T]{items: s.Items()}
+}
+
+// Snapshot returns an immutable view of the current elements of the set in O(1), whose iterators
+// stay consistent while the set keeps changing. See [Snapshot].
+// The set copies its elements before its next modification, so a snapshot costs a single copy
+// if the set is modified afterwards. Like the other methods, it must not be called concurrently
+// with modifications, but the returned snapshot can be used from any goroutine.
+func (s *Ordered[T]) Snapshot() *Snapshot[T] {
+	defer s.pin()()
+	if s.store == nil {
+		// the pinned elements of a stored set are already a copy
+		s.shared = true
+	}
+	return &Snapshot[T]{items: s.items[:len(s.items):len(s.items)], cmp: cmp.Compare[T]}
 }
 
 // Items returns a copy of the internal slice of the set.
 func (s *Ordered[T]) Items() []T {
+	if s.stored() {
+		return collectStorage(s.store.storage)
+	}
 	return slices.Clone(s.items)
 }
 
+// Release returns the internal sorted slice of the set without copying it, and leaves the set empty.
+// It's meant for terminal consumers, like encoders and batch writers, that would otherwise copy
+// the elements with [Ordered.Items] right before discarding the set. The caller owns the returned slice,
+// and the set allocates a new one on the next insertion.
+//
+// After a call to [Ordered.Snapshot], the slice is shared with the snapshot, so Release copies it
+// like [Ordered.Items] does, in order for the caller to be free to modify it.
+func (s *Ordered[T]) Release() []T {
+	defer s.pin()()
+	s.own()
+	items := s.items
+	if s.changelog != nil {
+		for _, e := range items {
+			s.changelog.record(OpRemove, e)
+		}
+	}
+
+	s.items = nil
+	s.Clear()
+	return items
+}
+
+// Hash returns a deterministic digest of the set, computed by resetting h and writing to it
+// the canonical binary encoding of the elements in ascending order. Equal sets have equal digests.
+// When h doesn't depend on the process (e.g. [fnv.New64a]), digests can be compared
+// across processes and used as cache keys.
+func (s *Ordered[T]) Hash(h hash.Hash64) uint64 {
+	defer s.pin()()
+	h.Reset()
+	buf := make([]byte, 0, 64)
+	for _, e := range s.items {
+		buf = appendOrdered(buf[:0], e)
+		h.Write(buf)
+	}
+	return h.Sum64()
+}
+
+// RangeDigest returns a hierarchical digest of the set with the provided number of levels,
+// for identifying which ranges of elements differ from another replica. See [RangeDigest].
+// It panics if levels is not in [1, 24]. O(N) complexity.
+func (s *Ordered[T]) RangeDigest(levels int) *RangeDigest {
+	if levels < 1 || levels > maxDigestLevels {
+		panic("smallset.Ordered.RangeDigest: levels must be in [1, 24]")
+	}
+
+	defer s.pin()()
+	return newRangeDigest(levels, func(yield func(uint64) bool) {
+		for _, e := range s.items {
+			if !yield(stableHashOrdered(e)) {
+				return
+			}
+		}
+	})
+}
+
+// RangeItems returns the elements of the set in the provided ranges of the last level of a
+// [RangeDigest] with the provided number of levels, like the ones returned by [RangeDigest.Diff].
+// It panics if levels is not in [1, 24]. O(N*log(R)) complexity.
+func (s *Ordered[T]) RangeItems(levels int, ranges []int) []T {
+	if levels < 1 || levels > maxDigestLevels {
+		panic("smallset.Ordered.RangeItems: levels must be in [1, 24]")
+	}
+
+	defer s.pin()()
+	return rangeItems(s.items, levels, ranges, stableHashOrdered[T])
+}
+
+// Sketch returns the [MinHash] signature of the set with k hashes derived from the seed,
+// for estimating its Jaccard similarity with other sets. It panics if k is <= 0. O(N*k) complexity.
+func (s *Ordered[T]) Sketch(k int, seed uint64) MinHash {
+	if k <= 0 {
+		panic("smallset.Ordered.Sketch: k must be > 0")
+	}
+
+	defer s.pin()()
+	return newMinHash(k, seed, func(yield func(uint64) bool) {
+		for _, e := range s.items {
+			if !yield(stableHashOrdered(e)) {
+				return
+			}
+		}
+	})
+}
+
+// stableHashOrdered returns the hash of e used by [Ordered.RangeDigest] and [Ordered.Sketch].
+func stableHashOrdered[T cmp.Ordered](e T) uint64 {
+	var buf [16]byte
+	return stableHash(appendOrdered(buf[:0], e))
+}
+
+// Key returns a compact canonical encoding of the set, suitable as a map key.
+// Two sets have the same key if and only if they are equal.
+func (s *Ordered[T]) Key() string {
+	defer s.pin()()
+	buf := make([]byte, 0, 8*len(s.items))
+	for _, e := range s.items {
+		buf = appendOrdered(buf, e)
+	}
+	return string(buf)
+}
+
+// Fingerprint returns an order-independent hash of the elements of the set.
+// Equal sets have equal fingerprints within the same process, so it can be used to cheaply check
+// whether a set has changed since a previous call. It is O(1) if the set was configured
+// with [Ordered.WithFingerprint], otherwise it's computed in O(N).
+func (s *Ordered[T]) Fingerprint() uint64 {
+	if s.fingerprint != nil {
+		return s.fingerprint.sum
+	}
+
+	defer s.pin()()
+	return newFingerprint(hashOrdered[T], s.items).sum
+}
+
 // Contains returns whether the element is in the set. Operation is O(log(N))
 func (s *Ordered[T]) Contains(e T) bool {
-	_, found := slices.BinarySearch(s.items, e)
+	if s.bloom != nil && !s.bloom.mayContain(e) {
+		return false
+	}
+	_, found := s.search(e)
+	return found
+}
+
+// ContainsCount returns how many of the probes are in the set, counting repeated probes
+// multiple times. O(P*log(N)) complexity.
+func (s *Ordered[T]) ContainsCount(probes ...T) int {
+	count := 0
+	for _, e := range probes {
+		if s.Contains(e) {
+			count++
+		}
+	}
+	return count
+}
+
+// ContainsMany returns whether each of the probes is in the set, in the order of the probes.
+// The probes are sorted and answered in a single merge pass over the set, which is faster than
+// independent calls to [Ordered.Contains] for medium batch sizes. O(P*log(P) + N) complexity.
+func (s *Ordered[T]) ContainsMany(probes []T) []bool {
+	defer s.pin()()
+	found := make([]bool, len(probes))
+	j := 0
+	for _, p := range sortedIndices(probes, cmp.Compare[T]) {
+		for j < len(s.items) && cmp.Less(s.items[j], probes[p]) {
+			j++
+		}
+		found[p] = j < len(s.items) && equal(s.items[j], probes[p])
+	}
 	return found
 }
 
 // At returns the element at index i or panics if out of range.
 func (s *Ordered[T]) At(i int) T {
-	if i < 0 || i >= len(s.items) {
+	if i < 0 || i >= s.Size() {
 		panic("smallset.Ordered.At: index out of range")
 	}
-	return s.items[i]
+	return s.at(i)
 }
 
 // Find returns the index of an element, or the position where target would appear
 // in the sort order. It also returns a bool saying whether the target is really found in the slice.
 func (s *Ordered[T]) Find(e T) (int, bool) {
-	return slices.BinarySearch(s.items, e)
+	return s.search(e)
+}
+
+// FindMany is the batch version of [Ordered.Find]. It returns, in the order of the probes, the index
+// of each probe or the position where it would appear in the sort order, and whether it's found.
+// The probes are sorted and resolved in a single merge pass over the set. O(P*log(P) + N) complexity.
+func (s *Ordered[T]) FindMany(probes []T) ([]int, []bool) {
+	defer s.pin()()
+	idxs := make([]int, len(probes))
+	found := make([]bool, len(probes))
+	j := 0
+	for _, p := range sortedIndices(probes, cmp.Compare[T]) {
+		for j < len(s.items) && cmp.Less(s.items[j], probes[p]) {
+			j++
+		}
+		idxs[p] = j
+		found[p] = j < len(s.items) && equal(s.items[j], probes[p])
+	}
+	return idxs, found
+}
+
+// ContainsFunc returns whether at least one element of the set satisfies pred.
+// It's meant for queries that can't be expressed with the ordering of the set,
+// and it's O(N) since every element may be checked.
+func (s *Ordered[T]) ContainsFunc(pred func(T) bool) bool {
+	defer s.pin()()
+	return slices.ContainsFunc(s.items, pred)
+}
+
+// IndexFunc returns the index of the first element in ascending order that satisfies pred,
+// or -1 if none does. Like [Ordered.ContainsFunc], it's O(N).
+func (s *Ordered[T]) IndexFunc(pred func(T) bool) int {
+	defer s.pin()()
+	return slices.IndexFunc(s.items, pred)
+}
+
+// IndexRange returns the indices [start, end) of the elements e such that min <= e < max,
+// as the positions where min and max would appear in the sort order.
+// It allows slicing, counting or iterating a range without repeating the binary searches.
+// Panics if max < min.
+func (s *Ordered[T]) IndexRange(min, max T) (start, end int) {
+	if cmp.Less(max, min) {
+		panic("smallset.Ordered.IndexRange: invalid range (max < min)")
+	}
+	return s.indexRange(min, max)
+}
+
+// indexRange returns the indices [start, end) of the elements e such that min <= e < max.
+// It assumes min <= max.
+func (s *Ordered[T]) indexRange(min, max T) (start, end int) {
+	start, _ = s.search(min)
+	end, _ = s.search(max)
+	return start, end
+}
+
+// Add an element and returns whether is was added (true), or was already present (false).
+func (s *Ordered[T]) Add(e T) bool {
+	i, found := s.search(e)
+	if found {
+		return false
+	}
+
+	s.insert(i, e)
+	return true
+}
+
+// AddSorted adds the elements of items, which must be sorted in ascending order, and returns
+// the number of elements added. Unlike adding them one by one, the elements are merged into
+// the set with a single backward pass, without sorting them nor shifting the set once per element.
+// Duplicates in items are allowed. O(N+M) complexity. It panics if items are not sorted.
+func (s *Ordered[T]) AddSorted(items []T) int {
+	if !slices.IsSorted(items) {
+		panic("smallset.Ordered.AddSorted: items must be sorted")
+	}
+
+	defer s.pin()()
+
+	// collect the new elements, skipping the duplicates in items
+	var added []T
+	i := 0
+	for j, e := range items {
+		if j > 0 && equal(items[j-1], e) {
+			continue
+		}
+		for i < len(s.items) && cmp.Less(s.items[i], e) {
+			i++
+		}
+		if i == len(s.items) || !equal(s.items[i], e) {
+			added = append(added, e)
+		}
+	}
+
+	if len(added) == 0 {
+		return 0
+	}
+
+	s.own()
+	for _, e := range added {
+		s.inserted(e)
+	}
+
+	// merge from the back, moving every element of the set at most once
+	n := len(s.items)
+	s.items = grow(s.growth, s.items, len(added))[:n+len(added)]
+	w := len(s.items) - 1
+	i = n - 1
+	for j := len(added) - 1; j >= 0; j-- {
+		e := added[j]
+		for i >= 0 && cmp.Less(e, s.items[i]) {
+			s.items[w] = s.items[i]
+			w--
+			i--
+		}
+		s.items[w] = e
+		w--
+	}
+	return len(added)
+}
+
+// Remove an element if present, and returns whether is was removed (true), or was never present (false).
+func (s *Ordered[T]) Remove(e T) bool {
+	i, found := s.search(e)
+	if !found {
+		return false
+	}
+
+	s.delete(i, i+1)
+	return true
+}
+
+// ReplaceAt replaces the element at index i with e if it preserves the sort order, meaning e is
+// strictly between the neighbors of index i. It returns whether the element was replaced (true),
+// or would break the sort order (false). It's an O(1) alternative to a Remove followed by an Add.
+// It panics if i is out of range.
+func (s *Ordered[T]) ReplaceAt(i int, e T) bool {
+	n := s.Size()
+	if i < 0 || i >= n {
+		panic("smallset.Ordered.ReplaceAt: index out of range")
+	}
+
+	if i > 0 && !cmp.Less(s.at(i-1), e) {
+		return false
+	}
+	if i < n-1 && !cmp.Less(e, s.at(i+1)) {
+		return false
+	}
+
+	s.replace(i, e)
+	return true
+}
+
+// MarkRemove marks an element for removal, and returns whether it's present in the set (true)
+// or not (false). Marked elements stay in the set until [Ordered.Compact] is called, which
+// removes all of them in a single O(N) pass.
+// It allows to remove elements while iterating the set, e.g. with [Ordered.Ascend],
+// without collecting them into a temporary slice.
+func (s *Ordered[T]) MarkRemove(e T) bool {
+	if _, found := s.search(e); !found {
+		return false
+	}
+	s.tombstones = append(s.tombstones, e)
+	return true
+}
+
+// Compact removes all the elements marked with [Ordered.MarkRemove] in a single pass.
+// Returns num removed.
+func (s *Ordered[T]) Compact() int {
+	if len(s.tombstones) == 0 {
+		return 0
+	}
+
+	slices.Sort(s.tombstones)
+	j := 0
+	removed := s.deleteFunc(func(e T) bool {
+		for j < len(s.tombstones) && cmp.Less(s.tombstones[j], e) {
+			j++
+		}
+		return j < len(s.tombstones) && equal(s.tombstones[j], e)
+	})
+
+	clear(s.tombstones)
+	s.tombstones = s.tombstones[:0]
+	return removed
+}
+
+// RemoveIndices removes the elements at the provided indices in a single O(N) pass.
+// Indices refer to the positions before any removal, and can be unsorted or repeated.
+// Returns num removed. It panics if any index is out of range.
+func (s *Ordered[T]) RemoveIndices(idxs ...int) int {
+	if len(idxs) == 0 {
+		return 0
+	}
+
+	idxs = slices.Clone(idxs)
+	slices.Sort(idxs)
+	idxs = slices.Compact(idxs)
+	if idxs[0] < 0 || idxs[len(idxs)-1] >= s.Size() {
+		panic("smallset.Ordered.RemoveIndices: index out of range")
+	}
+
+	i := 0
+	return s.deleteFunc(func(T) bool {
+		del := len(idxs) > 0 && idxs[0] == i
+		if del {
+			idxs = idxs[1:]
+		}
+		i++
+		return del
+	})
 }
 
-// Add an element and returns whether is was added (true), or was already present (false).
-func (s *Ordered[T]) Add(e T) bool {
-	i, found := slices.BinarySearch(s.items, e)
-	if found {
-		return false
+// ExtractFunc removes the elements for which pred returns true, and returns them as a new set,
+// in a single pass over the set. It's the "move these elements elsewhere" pattern, without
+// filtering the set and then removing the matches one by one. O(N) complexity.
+// It panics if pred is nil.
+func (s *Ordered[T]) ExtractFunc(pred func(T) bool) *Ordered[T] {
+	if pred == nil {
+		panic("smallset.Ordered.ExtractFunc: pred cannot be nil")
 	}
 
-	s.items = slices.Insert(s.items, i, e)
-	return true
+	var extracted []T
+	s.deleteFunc(func(e T) bool {
+		if !pred(e) {
+			return false
+		}
+		extracted = append(extracted, e)
+		return true
+	})
+	return &Ordered[T]{items: extracted}
 }
 
-// Remove an element if present, and returns whether is was removed (true), or was never present (false).
-func (s *Ordered[T]) Remove(e T) bool {
-	i, found := slices.BinarySearch(s.items, e)
-	if !found {
-		return false
+// ApplyDiff removes the elements of remove and adds the elements of add in a single merge pass,
+// instead of shifting the elements once per insertion or removal. The lists can be unsorted.
+// An element in both lists is treated as removed and then added again, so it ends up in the set.
+// Returns the number of elements that were added or removed as a result.
+// O(A*log(A) + R*log(R) + N) complexity.
+func (s *Ordered[T]) ApplyDiff(add, remove []T) (added, removed int) {
+	if len(add) == 0 && len(remove) == 0 {
+		return 0, 0
 	}
 
-	s.items = slices.Delete(s.items, i, i+1)
-	return true
+	defer s.pin()()
+	add = compact(slices.Sorted(slices.Values(add)))
+	remove = compact(slices.Sorted(slices.Values(remove)))
+	items := make([]T, 0, len(s.items)+len(add))
+
+	// i, j, k are the indices of the next element of s.items, add and remove respectively
+	i, j, k := 0, 0, 0
+	for i < len(s.items) || j < len(add) {
+		switch {
+		case j == len(add) || (i < len(s.items) && cmp.Less(s.items[i], add[j])):
+			// element in s not in add
+			e := s.items[i]
+			i++
+
+			for k < len(remove) && cmp.Less(remove[k], e) {
+				k++
+			}
+			if k < len(remove) && equal(remove[k], e) {
+				removed++
+				continue
+			}
+			items = append(items, e)
+
+		case i == len(s.items) || cmp.Less(add[j], s.items[i]):
+			// element in add not in s
+			items = append(items, add[j])
+			added++
+			j++
+
+		default:
+			// element in both
+			items = append(items, s.items[i])
+			i++
+			j++
+		}
+	}
+
+	if added > 0 || removed > 0 {
+		s.reset(items)
+	}
+	return added, removed
 }
 
 // RemoveBefore removes all elements e such that e < max. Returns num removed.
 func (s *Ordered[T]) RemoveBefore(max T) int {
-	end, _ := slices.BinarySearch(s.items, max)
+	end, _ := s.search(max)
 	if end == 0 {
 		return 0
 	}
 
-	s.items = slices.Delete(s.items, 0, end)
+	s.delete(0, end)
 	return end
 }
 
 // RemoveFrom removed all elements e such that e >= min. Returns num removed.
 func (s *Ordered[T]) RemoveFrom(min T) int {
-	start, _ := slices.BinarySearch(s.items, min)
-	if start == len(s.items) {
+	start, _ := s.search(min)
+	n := s.Size()
+	if start == n {
 		return 0
 	}
 
-	removed := len(s.items) - start
-	s.items = slices.Delete(s.items, start, len(s.items))
+	removed := n - start
+	s.delete(start, n)
 	return removed
 }
 
@@ -149,23 +1128,67 @@ func (s *Ordered[T]) RemoveBetween(min, max T) int {
 		panic("smallset.Ordered.RemoveBetween: invalid range (max < min)")
 	}
 
-	start, _ := slices.BinarySearch(s.items, min)
-	end, _ := slices.BinarySearch(s.items, max)
+	start, end := s.indexRange(min, max)
 	if start == end {
 		return 0
 	}
 
-	s.items = slices.Delete(s.items, start, end)
+	s.delete(start, end)
 	return end - start
 }
 
+// KeepBetween removes all elements e outside of min <= e < max, the inverse of [Ordered.RemoveBetween],
+// with at most two range deletions. It's meant for clamping a retention window on both ends.
+// Returns num removed. Panics if max < min.
+func (s *Ordered[T]) KeepBetween(min, max T) int {
+	if cmp.Less(max, min) {
+		panic("smallset.Ordered.KeepBetween: invalid range (max < min)")
+	}
+
+	start, end := s.indexRange(min, max)
+	n := s.Size()
+	removed := n - (end - start)
+
+	// delete the tail first, so that the indices of the head don't change
+	if end < n {
+		s.delete(end, n)
+	}
+	if start > 0 {
+		s.delete(0, start)
+	}
+	return removed
+}
+
+// TrimPercentile removes the lowest fraction low and the highest fraction high of the elements,
+// rounded down, with at most two range deletions. Returns num removed.
+// It's meant for removing outliers before computing robust statistics.
+// It panics if low or high are not in [0, 1], or if low + high > 1.
+func (s *Ordered[T]) TrimPercentile(low, high float64) int {
+	if !(low >= 0 && low <= 1) || !(high >= 0 && high <= 1) || low+high > 1 {
+		panic("smallset.Ordered.TrimPercentile: invalid fractions")
+	}
+
+	n := s.Size()
+	lo := int(low * float64(n))
+	hi := int(high * float64(n))
+
+	// delete the highest elements first, so that they are not shifted
+	if hi > 0 {
+		s.delete(n-hi, n)
+	}
+	if lo > 0 {
+		s.delete(0, lo)
+	}
+	return lo + hi
+}
+
 // Min returns the smallest element in the set.
 // It panics if the set is empty.
 func (s *Ordered[T]) Min() T {
 	if s.IsEmpty() {
 		panic("smallset.Ordered.Min: set is empty")
 	}
-	return s.items[0]
+	return s.at(0)
 }
 
 // Max returns the biggest element in the sets.
@@ -174,7 +1197,43 @@ func (s *Ordered[T]) Max() T {
 	if s.IsEmpty() {
 		panic("smallset.Ordered.Max: set is empty")
 	}
-	return s.items[len(s.items)-1]
+	return s.at(s.Size() - 1)
+}
+
+// Summary returns the count, min, max and median of the set in a single call. O(1) complexity.
+// Use [Summarize] for the sum and mean of numeric sets.
+func (s *Ordered[T]) Summary() Summary[T] {
+	defer s.pin()()
+	return summarize(s.items)
+}
+
+// PopMin removes and returns the smallest element in the set. O(N) complexity.
+// It panics if the set is empty.
+func (s *Ordered[T]) PopMin() T {
+	if s.IsEmpty() {
+		panic("smallset.Ordered.PopMin: set is empty")
+	}
+	min := s.at(0)
+	s.delete(0, 1)
+	return min
+}
+
+// PopMax removes and returns the biggest element in the set. O(1) complexity.
+// It panics if the set is empty.
+func (s *Ordered[T]) PopMax() T {
+	if s.IsEmpty() {
+		panic("smallset.Ordered.PopMax: set is empty")
+	}
+	last := s.Size() - 1
+	max := s.at(last)
+	s.delete(last, last+1)
+	return max
+}
+
+// Queue returns a [PriorityQueue] view over the set.
+// The view shares the set, so changes made through one are visible in the other.
+func (s *Ordered[T]) Queue() *PriorityQueue[T] {
+	return &PriorityQueue[T]{set: s}
 }
 
 // MinK returns the k smallest elements in s, sorted in ascending order. O(k) complexity.
@@ -184,7 +1243,7 @@ func (s *Ordered[T]) MinK(k int) []T {
 		panic(fmt.Sprintf("smallset.Ordered.MinK: k must be positive: %d", k))
 	}
 	k = min(k, s.Size())
-	return slices.Clone(s.items[:k])
+	return s.appendRange(make([]T, 0, k), 0, k)
 }
 
 // MaxK returns the k biggest elements in s, sorted in ascending order. O(k) complexity.
@@ -194,16 +1253,86 @@ func (s *Ordered[T]) MaxK(k int) []T {
 		panic(fmt.Sprintf("smallset.Ordered.MaxK: k must be positive: %d", k))
 	}
 	k = min(k, s.Size())
-	return slices.Clone(s.items[len(s.items)-k:])
+	n := s.Size()
+	return s.appendRange(make([]T, 0, k), n-k, n)
+}
+
+// Page returns a copy of up to limit elements starting at index offset, in ascending order,
+// together with the total number of elements in the set. Offsets past the end return no elements.
+// It panics if offset or limit are negative.
+func (s *Ordered[T]) Page(offset, limit int) ([]T, int) {
+	if offset < 0 || limit < 0 {
+		panic(fmt.Sprintf("smallset.Ordered.Page: offset and limit must be positive: %d, %d", offset, limit))
+	}
+
+	n := s.Size()
+	start := min(offset, n)
+	end := start + min(limit, n-start)
+	return s.appendRange(make([]T, 0, end-start), start, end), n
+}
+
+// MinKAppend appends the k smallest elements in s to dst in ascending order,
+// and returns the extended slice. Unlike [Ordered.MinK], it doesn't allocate if dst has enough capacity.
+// It panics if k is negative. If k is bigger than the set size, it appends all the items.
+func (s *Ordered[T]) MinKAppend(dst []T, k int) []T {
+	if k < 0 {
+		panic(fmt.Sprintf("smallset.Ordered.MinKAppend: k must be positive: %d", k))
+	}
+	k = min(k, s.Size())
+	return s.appendRange(dst, 0, k)
+}
+
+// MaxKAppend appends the k biggest elements in s to dst in ascending order,
+// and returns the extended slice. Unlike [Ordered.MaxK], it doesn't allocate if dst has enough capacity.
+// It panics if k is negative. If k is bigger than the set size, it appends all the items.
+func (s *Ordered[T]) MaxKAppend(dst []T, k int) []T {
+	if k < 0 {
+		panic(fmt.Sprintf("smallset.Ordered.MaxKAppend: k must be positive: %d", k))
+	}
+	k = min(k, s.Size())
+	n := s.Size()
+	return s.appendRange(dst, n-k, n)
+}
+
+// SampleWeighted returns k elements sampled without replacement, with probabilities proportional
+// to the provided weight function. Elements with a non-positive weight are never sampled.
+// The sampled elements are returned in ascending order. O(N*log(N)) complexity.
+// It panics if k is negative. If k is bigger than the number of elements with a positive weight,
+// it returns all of them. If rng is nil, the global random source is used.
+func (s *Ordered[T]) SampleWeighted(k int, weight func(T) float64, rng *rand.Rand) []T {
+	if k < 0 {
+		panic(fmt.Sprintf("smallset.Ordered.SampleWeighted: k must be positive: %d", k))
+	}
+
+	defer s.pin()()
+	return sampleWeighted(s.items, k, weight, rng)
+}
+
+// SortInterface returns a read-only [sort.Interface] view over the set, for APIs
+// that take a sort.Interface or need index-based access. Len and Less reflect
+// the current state of the set, while Swap panics unless i == j.
+func (s *Ordered[T]) SortInterface() sort.Interface {
+	return sorter[T]{set: s, less: cmp.Less[T]}
+}
+
+// Cursor returns a [Cursor] over the set, positioned before its smallest element.
+func (s *Ordered[T]) Cursor() *Cursor[T] {
+	return &Cursor[T]{set: s}
 }
 
 // Ascend returns an iterator over the set in ascending order.
 func (s *Ordered[T]) Ascend() iter.Seq2[int, T] {
+	if s.stored() {
+		return s.store.storage.All()
+	}
 	return slices.All(s.items)
 }
 
 // Descend returns an iterator over the set in descending order.
 func (s *Ordered[T]) Descend() iter.Seq2[int, T] {
+	if s.stored() {
+		return backward(s.store.storage)
+	}
 	return slices.Backward(s.items)
 }
 
@@ -214,11 +1343,11 @@ func (s *Ordered[T]) BetweenAsc(min, max T) iter.Seq2[int, T] {
 	if cmp.Less(max, min) {
 		panic("smallset.Ordered.BetweenAsc: invalid range (max < min)")
 	}
-	start, _ := slices.BinarySearch(s.items, min)
+	start, _ := s.search(min)
 
 	return func(yield func(int, T) bool) {
-		for i := start; i < len(s.items); i++ {
-			v := s.items[i]
+		for i := start; i < s.Size(); i++ {
+			v := s.at(i)
 			if !cmp.Less(v, max) {
 				return
 			}
@@ -237,14 +1366,14 @@ func (s *Ordered[T]) BetweenDesc(max, min T) iter.Seq2[int, T] {
 		panic("smallset.Ordered.BetweenDesc: invalid range (max < min)")
 	}
 
-	end, found := slices.BinarySearch(s.items, max)
+	end, found := s.search(max)
 	if !found && end > 0 {
 		end--
 	}
 
 	return func(yield func(int, T) bool) {
 		for i := end; i >= 0; i-- {
-			v := s.items[i]
+			v := s.at(i)
 			if !cmp.Less(min, v) {
 				return
 			}
@@ -255,19 +1384,77 @@ func (s *Ordered[T]) BetweenDesc(max, min T) iter.Seq2[int, T] {
 	}
 }
 
+// BetweenValues iterates the values from min (inclusive) to max (exclusive) in ascending order.
+// It's like [Ordered.BetweenAsc] without the index, to compose with [iter.Seq] based pipelines.
+// Panics if max < min.
+func (s *Ordered[T]) BetweenValues(min, max T) iter.Seq[T] {
+	if cmp.Less(max, min) {
+		panic("smallset.Ordered.BetweenValues: invalid range (max < min)")
+	}
+	start, _ := s.search(min)
+
+	return func(yield func(T) bool) {
+		for i := start; i < s.Size(); i++ {
+			v := s.at(i)
+			if !cmp.Less(v, max) {
+				return
+			}
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Pairs iterates over each pair of adjacent elements in ascending order, yielding (s[0], s[1]),
+// (s[1], s[2]) and so on. It's the natural primitive for computing the gaps between consecutive
+// elements, like the deltas between timestamps. Sets with less than two elements yield nothing.
+func (s *Ordered[T]) Pairs() iter.Seq2[T, T] {
+	return func(yield func(T, T) bool) {
+		for i := 1; i < s.Size(); i++ {
+			if !yield(s.at(i-1), s.at(i)) {
+				return
+			}
+		}
+	}
+}
+
 // IsEqual returns whether the two sets have the same elements.
 func (s *Ordered[T]) IsEqual(other *Ordered[T]) bool {
-	return slices.Equal(s.items, other.items)
+	defer s.pin()()
+	defer other.pin()()
+	return slices.EqualFunc(s.items, other.items, equal[T])
+}
+
+// EqualFunc returns whether two sets of possibly different element types have the same size and
+// eq returns true for each pair of elements at the same position, like a set of IDs and a set of
+// the records with those IDs. The elements are compared in ascending order, so eq *must* match
+// elements that have the same position in both orders. O(N) complexity. It panics if eq is nil.
+func EqualFunc[A, B cmp.Ordered](a *Ordered[A], b *Ordered[B], eq func(A, B) bool) bool {
+	if eq == nil {
+		panic("smallset.EqualFunc: eq cannot be nil")
+	}
+
+	defer a.pin()()
+	defer b.pin()()
+	return slices.EqualFunc(a.items, b.items, eq)
 }
 
 // Intersect returns the intersection of two sets, returning a New set
 // containing only the common elements. O(N+M) complexity.
+// Sets of int, int32, int64, uint32 and uint64 use a faster branchless kernel.
 func (s *Ordered[T]) Intersect(other *Ordered[T]) *Ordered[T] {
 	size := min(s.Size(), other.Size())
 	if size == 0 {
 		return New[T](defaultCapacity)
 	}
 
+	defer s.pin()()
+	defer other.pin()()
+	if items, ok := intersectKernel(s.items, other.items); ok {
+		return &Ordered[T]{items: items}
+	}
+
 	inter := New[T](size)
 
 	i := 0
@@ -277,10 +1464,10 @@ func (s *Ordered[T]) Intersect(other *Ordered[T]) *Ordered[T] {
 		s_i := s.items[i]
 		o_j := other.items[j]
 
-		if s_i < o_j {
+		if cmp.Less(s_i, o_j) {
 			// element in s not in other
 			i++
-		} else if o_j < s_i {
+		} else if cmp.Less(o_j, s_i) {
 			// element in other not in s
 			j++
 		} else {
@@ -294,6 +1481,33 @@ func (s *Ordered[T]) Intersect(other *Ordered[T]) *Ordered[T] {
 	return inter
 }
 
+// IntersectCount returns the number of elements in common between the two sets,
+// without allocating their intersection. O(N+M) complexity.
+func (s *Ordered[T]) IntersectCount(other *Ordered[T]) int {
+	defer s.pin()()
+	defer other.pin()()
+	count := 0
+	i := 0
+	j := 0
+
+	for i < s.Size() && j < other.Size() {
+		s_i := s.items[i]
+		o_j := other.items[j]
+
+		if cmp.Less(s_i, o_j) {
+			i++
+		} else if cmp.Less(o_j, s_i) {
+			j++
+		} else {
+			count++
+			i++
+			j++
+		}
+	}
+
+	return count
+}
+
 // Difference returns the difference between this set and other. The returned set will contain
 // all elements of this set that are not elements of other. O(N+M) complexity.
 func (s *Ordered[T]) Difference(other *Ordered[T]) *Ordered[T] {
@@ -301,9 +1515,11 @@ func (s *Ordered[T]) Difference(other *Ordered[T]) *Ordered[T] {
 		return New[T](defaultCapacity)
 	}
 	if other.IsEmpty() {
-		return s.Clone()
+		return s.cloneItems()
 	}
 
+	defer s.pin()()
+	defer other.pin()()
 	diff := New[T](s.Size())
 
 	i := 0
@@ -313,11 +1529,11 @@ func (s *Ordered[T]) Difference(other *Ordered[T]) *Ordered[T] {
 		s_i := s.items[i]
 		o_j := other.items[j]
 
-		if s_i < o_j {
+		if cmp.Less(s_i, o_j) {
 			// element in s not in other
 			diff.items = append(diff.items, s_i)
 			i++
-		} else if o_j < s_i {
+		} else if cmp.Less(o_j, s_i) {
 			// element in other not in s
 			j++
 		} else {
@@ -331,16 +1547,54 @@ func (s *Ordered[T]) Difference(other *Ordered[T]) *Ordered[T] {
 	return diff
 }
 
+// Complement returns the elements of universe that are not elements of this set, which is
+// universe.Difference(s) without having to remember the order of the operands, for queries like
+// "which shards are not assigned". Elements of this set outside of universe are ignored.
+// O(N+M) complexity.
+func (s *Ordered[T]) Complement(universe *Ordered[T]) *Ordered[T] {
+	return universe.Difference(s)
+}
+
+// DifferenceCount returns the number of elements of this set that are not elements of other,
+// without allocating their difference. O(N+M) complexity.
+func (s *Ordered[T]) DifferenceCount(other *Ordered[T]) int {
+	return s.Size() - s.IntersectCount(other)
+}
+
+// DifferenceSeq returns a new set with the elements of this set that are not produced by seq,
+// which can be in any order. The sequence is streamed rather than materialized, which makes it
+// suitable for subtracting a large lazily-produced sequence from a small set.
+// O(M*log(N) + N) complexity.
+func (s *Ordered[T]) DifferenceSeq(seq iter.Seq[T]) *Ordered[T] {
+	defer s.pin()()
+	removed := make([]bool, len(s.items))
+	for e := range seq {
+		if i, found := slices.BinarySearch(s.items, e); found {
+			removed[i] = true
+		}
+	}
+
+	diff := New[T](max(s.Size(), 1))
+	for i, e := range s.items {
+		if !removed[i] {
+			diff.items = append(diff.items, e)
+		}
+	}
+	return diff
+}
+
 // SymmetricDifference returns a New set with all elements which are
 // in either this set or the other set but not in both. O(N+M) complexity.
 func (s *Ordered[T]) SymmetricDifference(other *Ordered[T]) *Ordered[T] {
 	if s.IsEmpty() {
-		return other.Clone()
+		return other.cloneItems()
 	}
 	if other.IsEmpty() {
-		return s.Clone()
+		return s.cloneItems()
 	}
 
+	defer s.pin()()
+	defer other.pin()()
 	sdiff := New[T](s.Size() + other.Size())
 
 	i := 0
@@ -350,11 +1604,11 @@ func (s *Ordered[T]) SymmetricDifference(other *Ordered[T]) *Ordered[T] {
 		s_i := s.items[i]
 		o_j := other.items[j]
 
-		if s_i < o_j {
+		if cmp.Less(s_i, o_j) {
 			// element in s not in other
 			sdiff.items = append(sdiff.items, s_i)
 			i++
-		} else if o_j < s_i {
+		} else if cmp.Less(o_j, s_i) {
 			// element in other not in s
 			sdiff.items = append(sdiff.items, o_j)
 			j++
@@ -371,12 +1625,19 @@ func (s *Ordered[T]) SymmetricDifference(other *Ordered[T]) *Ordered[T] {
 }
 
 // Union returns a New set with all elements in both sets. O(N+M) complexity.
+// Sets of int, int32, int64, uint32 and uint64 use a faster branchless kernel.
 func (s *Ordered[T]) Union(other *Ordered[T]) *Ordered[T] {
 	if s.IsEmpty() {
-		return other.Clone()
+		return other.cloneItems()
 	}
 	if other.IsEmpty() {
-		return s.Clone()
+		return s.cloneItems()
+	}
+
+	defer s.pin()()
+	defer other.pin()()
+	if items, ok := unionKernel(s.items, other.items); ok {
+		return &Ordered[T]{items: items}
 	}
 
 	union := New[T](s.Size() + other.Size())
@@ -388,11 +1649,11 @@ func (s *Ordered[T]) Union(other *Ordered[T]) *Ordered[T] {
 		s_i := s.items[i]
 		o_j := other.items[j]
 
-		if s_i < o_j {
+		if cmp.Less(s_i, o_j) {
 			// element in s not in other
 			union.items = append(union.items, s_i)
 			i++
-		} else if o_j < s_i {
+		} else if cmp.Less(o_j, s_i) {
 			// element in other not in s
 			union.items = append(union.items, o_j)
 			j++
@@ -409,6 +1670,26 @@ func (s *Ordered[T]) Union(other *Ordered[T]) *Ordered[T] {
 	return union
 }
 
+// UnionCount returns the number of elements in either set, without allocating their union.
+// It's useful to pre-size the result of a union. O(N+M) complexity.
+func (s *Ordered[T]) UnionCount(other *Ordered[T]) int {
+	return s.Size() + other.Size() - s.IntersectCount(other)
+}
+
+// UnionSeq returns a new set with the elements of this set and the ones produced by seq,
+// which can be in any order. The sequence is streamed, and only the elements that are not
+// already in the set are collected and sorted. O(M*log(N) + K*log(K) + N) complexity,
+// where K is the number of collected elements.
+func (s *Ordered[T]) UnionSeq(seq iter.Seq[T]) *Ordered[T] {
+	var extra []T
+	for e := range seq {
+		if _, found := s.search(e); !found {
+			extra = append(extra, e)
+		}
+	}
+	return s.Union(From(extra...))
+}
+
 // Partition returns three New sets:
 // - d12: elements in s1 not in s2
 // - inter: elements in both sets
@@ -416,12 +1697,15 @@ func (s *Ordered[T]) Union(other *Ordered[T]) *Ordered[T] {
 // O(N+M) complexity.
 func (s1 *Ordered[T]) Partition(s2 *Ordered[T]) (d12, inter, d21 *Ordered[T]) {
 	if s1.IsEmpty() {
-		return New[T](defaultCapacity), New[T](defaultCapacity), s2.Clone()
+		return New[T](defaultCapacity), New[T](defaultCapacity), s2.cloneItems()
 	}
 	if s2.IsEmpty() {
-		return s1.Clone(), New[T](defaultCapacity), New[T](defaultCapacity)
+		return s1.cloneItems(), New[T](defaultCapacity), New[T](defaultCapacity)
 	}
 
+	defer s1.pin()()
+	defer s2.pin()()
+
 	d12 = New[T](s1.Size())
 	inter = New[T](min(s1.Size(), s2.Size()))
 	d21 = New[T](s2.Size())
@@ -433,11 +1717,11 @@ func (s1 *Ordered[T]) Partition(s2 *Ordered[T]) (d12, inter, d21 *Ordered[T]) {
 		e1 := s1.items[i]
 		e2 := s2.items[j]
 
-		if e1 < e2 {
+		if cmp.Less(e1, e2) {
 			// element in s1 not in s2
 			d12.items = append(d12.items, e1)
 			i++
-		} else if e2 < e1 {
+		} else if cmp.Less(e2, e1) {
 			// element in s2 not in s1
 			d21.items = append(d21.items, e2)
 			j++
@@ -456,13 +1740,14 @@ func (s1 *Ordered[T]) Partition(s2 *Ordered[T]) (d12, inter, d21 *Ordered[T]) {
 
 // Merge efficiently combines multiple [Ordered] sets into a single new set.
 // This is significantly more efficient than chaining s1.Union(s2).Union(s3)...
-// as it performs only a single sort and compact operation on the combined data.
+// as it performs a k-way merge that exploits the sortedness of the sets, which is
+// O(N*log(K)) where N is the total number of elements and K the number of sets.
 func Merge[T cmp.Ordered](sets ...*Ordered[T]) *Ordered[T] {
 	if len(sets) == 0 {
 		return New[T](defaultCapacity)
 	}
 	if len(sets) == 1 {
-		return sets[0].Clone()
+		return sets[0].cloneItems()
 	}
 
 	size := 0
@@ -474,14 +1759,12 @@ func Merge[T cmp.Ordered](sets ...*Ordered[T]) *Ordered[T] {
 		return New[T](defaultCapacity)
 	}
 
-	combined := make([]T, 0, size)
-	for _, s := range sets {
-		combined = append(combined, s.items...)
+	defer pinAll(sets)()
+	lists := make([][]T, len(sets))
+	for i, s := range sets {
+		lists[i] = s.items
 	}
-
-	slices.Sort(combined)
-	combined = slices.Compact(combined)
-	return &Ordered[T]{items: combined}
+	return &Ordered[T]{items: mergeOrdered(lists)}
 }
 
 // Intersect efficiently finds the common elements present in *all* provided [Ordered] sets.
@@ -492,7 +1775,7 @@ func Intersect[T cmp.Ordered](sets ...*Ordered[T]) *Ordered[T] {
 		return New[T](defaultCapacity)
 	}
 	if len(sets) == 1 {
-		return sets[0].Clone()
+		return sets[0].cloneItems()
 	}
 
 	// sort the sets from smallest to biggest
@@ -500,7 +1783,8 @@ func Intersect[T cmp.Ordered](sets ...*Ordered[T]) *Ordered[T] {
 		return cmp.Compare(s1.Size(), s2.Size())
 	})
 
-	inter := sets[0].Clone()
+	defer pinAll(sets)()
+	inter := &Ordered[T]{items: slices.Clone(sets[0].items)}
 	if inter.IsEmpty() {
 		return inter
 	}
@@ -515,11 +1799,11 @@ func Intersect[T cmp.Ordered](sets ...*Ordered[T]) *Ordered[T] {
 			candidate := inter.items[r]
 			item := set.items[j]
 
-			if candidate < item {
+			if cmp.Less(candidate, item) {
 				// element in inter not in set.
 				// Discard it by not increasing the write index
 				r++
-			} else if item < candidate {
+			} else if cmp.Less(item, candidate) {
 				// element in set not in inter
 				j++
 			} else {
@@ -538,3 +1822,300 @@ func Intersect[T cmp.Ordered](sets ...*Ordered[T]) *Ordered[T] {
 	}
 	return inter
 }
+
+// PowerSet returns an iterator that lazily yields every subset of s, including the
+// empty set and s itself. Each subset is a new set. The 2^N subsets are yielded in the order
+// given by the binary representation of their index, where the i-th bit selects the i-th element.
+// It panics if the set has more than 62 elements.
+func PowerSet[T cmp.Ordered](s *Ordered[T]) iter.Seq[*Ordered[T]] {
+	if s.Size() > 62 {
+		panic(fmt.Sprintf("smallset.PowerSet: set is too big: %d", s.Size()))
+	}
+
+	return func(yield func(*Ordered[T]) bool) {
+		defer s.pin()()
+		n := s.Size()
+		for mask := uint64(0); mask < 1<<n; mask++ {
+			subset := &Ordered[T]{items: make([]T, 0, bits.OnesCount64(mask))}
+			for i := range n {
+				if mask&(1<<i) != 0 {
+					subset.items = append(subset.items, s.items[i])
+				}
+			}
+
+			if !yield(subset) {
+				return
+			}
+		}
+	}
+}
+
+// Joined is an element yielded by [MergeJoin] and [MergeJoinCustom],
+// together with the sets it belongs to.
+type Joined[T any] struct {
+	Value T
+	InA   bool
+	InB   bool
+}
+
+// MergeJoin returns an iterator over the union of a and b in ascending order,
+// reporting for each element whether it's in a, in b, or in both.
+// It allows implementing custom reconciliation logic in a single pass, without
+// calling [Ordered.Partition] and walking the three resulting sets. O(N+M) complexity.
+func MergeJoin[T cmp.Ordered](a, b *Ordered[T]) iter.Seq[Joined[T]] {
+	return func(yield func(Joined[T]) bool) {
+		defer a.pin()()
+		defer b.pin()()
+		i := 0
+		j := 0
+
+		for i < a.Size() && j < b.Size() {
+			a_i := a.items[i]
+			b_j := b.items[j]
+
+			var join Joined[T]
+			if cmp.Less(a_i, b_j) {
+				// element in a not in b
+				join = Joined[T]{Value: a_i, InA: true}
+				i++
+			} else if cmp.Less(b_j, a_i) {
+				// element in b not in a
+				join = Joined[T]{Value: b_j, InB: true}
+				j++
+			} else {
+				// element in both
+				join = Joined[T]{Value: a_i, InA: true, InB: true}
+				i++
+				j++
+			}
+
+			if !yield(join) {
+				return
+			}
+		}
+
+		for ; i < a.Size(); i++ {
+			if !yield(Joined[T]{Value: a.items[i], InA: true}) {
+				return
+			}
+		}
+		for ; j < b.Size(); j++ {
+			if !yield(Joined[T]{Value: b.items[j], InB: true}) {
+				return
+			}
+		}
+	}
+}
+
+// AreDisjoint returns whether no element appears in more than one of the provided sets.
+// It performs a k-way merge of the sets, returning as soon as a shared element is found.
+// O(N*k) complexity, where N is the total number of elements and k the number of sets.
+func AreDisjoint[T cmp.Ordered](sets ...*Ordered[T]) bool {
+	if len(sets) < 2 {
+		return true
+	}
+
+	defer pinAll(sets)()
+	// next[k] is the index of the next element of sets[k] to be merged
+	next := make([]int, len(sets))
+	for {
+		// m is the index of the set with the smallest next element, or -1 if all are exhausted
+		m := -1
+		for k, set := range sets {
+			if next[k] == set.Size() {
+				continue
+			}
+			if m == -1 {
+				m = k
+				continue
+			}
+
+			e := set.items[next[k]]
+			min := sets[m].items[next[m]]
+			if equal(e, min) {
+				return false
+			}
+			if cmp.Less(e, min) {
+				m = k
+			}
+		}
+
+		if m == -1 {
+			return true
+		}
+		next[m]++
+	}
+}
+
+// MultiPartition returns an iterator over the union of the sets in ascending order, yielding
+// each element with a bitmask of the sets that contain it, where bit k is set if sets[k] does.
+// It generalizes [Ordered.Partition] to any number of sets with a single k-way merge,
+// instead of a partition per pair of sets. O(N*k) complexity, where N is the total number
+// of elements and k the number of sets. It panics if there are more than 64 sets.
+func MultiPartition[T cmp.Ordered](sets ...*Ordered[T]) iter.Seq2[T, uint64] {
+	if len(sets) > 64 {
+		panic("smallset.MultiPartition: more than 64 sets")
+	}
+
+	return func(yield func(T, uint64) bool) {
+		defer pinAll(sets)()
+		// next[k] is the index of the next element of sets[k] to be merged
+		next := make([]int, len(sets))
+		for {
+			// mask has the bits of the sets whose next element is the smallest one
+			var min T
+			var mask uint64
+			for k, set := range sets {
+				if next[k] == set.Size() {
+					continue
+				}
+
+				e := set.items[next[k]]
+				switch {
+				case mask == 0 || cmp.Less(e, min):
+					min, mask = e, 1<<k
+				case equal(e, min):
+					mask |= 1 << k
+				}
+			}
+
+			if mask == 0 {
+				return
+			}
+			if !yield(min, mask) {
+				return
+			}
+
+			for k := range sets {
+				if mask&(1<<k) != 0 {
+					next[k]++
+				}
+			}
+		}
+	}
+}
+
+// Counts returns an iterator over the union of the sets in ascending order, yielding
+// each element with the number of sets that contain it, for quorum or voting logic.
+// Unlike [MultiPartition], it accepts any number of sets.
+// O(N*k) complexity, where N is the total number of elements and k the number of sets.
+func Counts[T cmp.Ordered](sets ...*Ordered[T]) iter.Seq2[T, int] {
+	return func(yield func(T, int) bool) {
+		defer pinAll(sets)()
+		// next[k] is the index of the next element of sets[k] to be merged
+		next := make([]int, len(sets))
+		for {
+			var min T
+			found := false
+			for k, set := range sets {
+				if next[k] == set.Size() {
+					continue
+				}
+				if e := set.items[next[k]]; !found || cmp.Less(e, min) {
+					min, found = e, true
+				}
+			}
+
+			if !found {
+				return
+			}
+
+			count := 0
+			for k, set := range sets {
+				if next[k] < set.Size() && equal(set.items[next[k]], min) {
+					next[k]++
+					count++
+				}
+			}
+
+			if !yield(min, count) {
+				return
+			}
+		}
+	}
+}
+
+// Runs returns an iterator over the maximal runs of consecutive integers of the set,
+// as pairs of [start, end] with both ends included, in ascending order.
+// For example, the set {1, 2, 3, 5, 7, 8} yields (1, 3), (5, 5), (7, 8). O(N) complexity.
+func Runs[T Integer](s *Ordered[T]) iter.Seq2[T, T] {
+	return func(yield func(T, T) bool) {
+		if s.IsEmpty() {
+			return
+		}
+
+		defer s.pin()()
+		start := s.items[0]
+		for i := 1; i < len(s.items); i++ {
+			if s.items[i] == s.items[i-1]+1 {
+				continue
+			}
+
+			if !yield(start, s.items[i-1]) {
+				return
+			}
+			start = s.items[i]
+		}
+		yield(start, s.items[len(s.items)-1])
+	}
+}
+
+// pinAll pins all the sets, see [Ordered.pin].
+func pinAll[T cmp.Ordered](sets []*Ordered[T]) (unpin func()) {
+	var unpins []func()
+	for _, s := range sets {
+		if s.store != nil && !s.store.pinned {
+			unpins = append(unpins, s.pin())
+		}
+	}
+
+	if len(unpins) == 0 {
+		return noop
+	}
+	return func() {
+		for _, unpin := range unpins {
+			unpin()
+		}
+	}
+}
+
+// sortedIndices returns the indices of the probes, sorted by the value of the probes.
+func sortedIndices[T any](probes []T, cmp func(a, b T) int) []int {
+	idxs := make([]int, len(probes))
+	for i := range idxs {
+		idxs[i] = i
+	}
+
+	if !slices.IsSortedFunc(probes, cmp) {
+		slices.SortStableFunc(idxs, func(i, j int) int { return cmp(probes[i], probes[j]) })
+	}
+	return idxs
+}
+
+// drain returns an iterator that pops the elements of the heap in priority order.
+// It panics if an element of the heap is not of type T.
+func drain[T any](h heap.Interface) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for h.Len() > 0 {
+			x := heap.Pop(h)
+			e, ok := x.(T)
+			if !ok {
+				panic(fmt.Sprintf("smallset: heap element has type %T, expected %T", x, e))
+			}
+			if !yield(e) {
+				return
+			}
+		}
+	}
+}
+
+// receive returns an iterator over the elements received from the channel until it's closed.
+func receive[T any](ch <-chan T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for e := range ch {
+			if !yield(e) {
+				return
+			}
+		}
+	}
+}
@@ -2,15 +2,285 @@ package smallset
 
 import (
 	"cmp"
+	"container/heap"
 	"fmt"
+	"hash/fnv"
 	"iter"
+	"maps"
+	"math"
 	"math/rand"
 	"slices"
+	"sort"
 	"testing"
 
 	mapset "github.com/deckarep/golang-set/v2"
 )
 
+type intHeap []int
+
+func (h intHeap) Len() int           { return len(h) }
+func (h intHeap) Less(i, j int) bool { return h[i] < h[j] }
+func (h intHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *intHeap) Push(x any)        { *h = append(*h, x.(int)) }
+func (h *intHeap) Pop() any {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+func TestNewRange(t *testing.T) {
+	cases := []struct {
+		start, end, step int8
+		expected         []int8
+	}{
+		{start: 0, end: 5, step: 1, expected: []int8{0, 1, 2, 3, 4}},
+		{start: -3, end: 4, step: 3, expected: []int8{-3, 0, 3}},
+		{start: 5, end: 5, step: 1, expected: []int8{}},
+		{start: 5, end: 0, step: 1, expected: []int8{}},
+		{start: 100, end: 127, step: 20, expected: []int8{100, 120}},
+		{start: -128, end: 127, step: 100, expected: []int8{-128, -28, 72}},
+	}
+
+	for i, test := range cases {
+		t.Run(fmt.Sprintf("Case_%d", i), func(t *testing.T) {
+			s := NewRange(test.start, test.end, test.step)
+			if !slices.Equal(s.items, test.expected) {
+				t.Errorf("Expected %v, got %v", test.expected, s.items)
+			}
+		})
+	}
+}
+
+func TestAdopt(t *testing.T) {
+	cases := []struct {
+		items    []int
+		expected []int
+	}{
+		{items: nil, expected: nil},
+		{items: []int{3, 1, 2, 1, 3}, expected: []int{1, 2, 3}},
+	}
+
+	for i, test := range cases {
+		t.Run(fmt.Sprintf("Case_%d", i), func(t *testing.T) {
+			items := test.items
+			s := Adopt(items)
+
+			if !slices.Equal(s.items, test.expected) {
+				t.Errorf("Expected %v, got %v", test.expected, s.items)
+			}
+			if len(items) > 0 && &s.items[0] != &items[0] {
+				t.Errorf("expected the set to alias the adopted slice")
+			}
+
+			s.Add(10)
+			if !s.Contains(10) {
+				t.Errorf("expected the set to be usable after Adopt")
+			}
+		})
+	}
+}
+
+func TestSortedKeys(t *testing.T) {
+	cases := []struct {
+		m        map[string]int
+		expected []string
+	}{
+		{m: nil, expected: []string{}},
+		{m: map[string]int{"b": 1, "c": 2, "a": 3}, expected: []string{"a", "b", "c"}},
+	}
+
+	for i, test := range cases {
+		t.Run(fmt.Sprintf("Case_%d", i), func(t *testing.T) {
+			s := SortedKeys(test.m)
+			if !slices.Equal(s.items, test.expected) {
+				t.Errorf("Expected %v, got %v", test.expected, s.items)
+			}
+		})
+	}
+
+	nan := math.NaN()
+	if s := SortedKeys(map[float64]bool{nan: true, nan: false, 1: true}); s.Size() != 2 {
+		t.Errorf("expected the NaN keys to be deduplicated, got %v", s.items)
+	}
+}
+
+func TestAsMap(t *testing.T) {
+	s := From("a", "bb", "ccc")
+
+	m := AsMap(s, func(e string) int { return len(e) })
+	expected := map[string]int{"a": 1, "bb": 2, "ccc": 3}
+	if !maps.Equal(m, expected) {
+		t.Errorf("AsMap expected %v, got %v", expected, m)
+	}
+
+	keys := AsKeySet(s)
+	if len(keys) != 3 || !SortedKeys(keys).IsEqual(s) {
+		t.Errorf("AsKeySet expected the keys %v, got %v", s.items, keys)
+	}
+}
+
+func TestFromSeq(t *testing.T) {
+	cases := []struct {
+		seq      []int
+		expected []int
+	}{
+		{seq: nil, expected: []int{}},
+		{seq: []int{1, 2, 2, 3}, expected: []int{1, 2, 3}},
+		{seq: []int{5, 1, 3, 1}, expected: []int{1, 3, 5}},
+	}
+
+	for i, test := range cases {
+		t.Run(fmt.Sprintf("Case_%d", i), func(t *testing.T) {
+			s := FromSeq(slices.Values(test.seq))
+			if !slices.Equal(s.items, test.expected) {
+				t.Errorf("Expected %v, got %v", test.expected, s.items)
+			}
+		})
+	}
+}
+
+func TestFromHeap(t *testing.T) {
+	h := &intHeap{}
+	for _, e := range []int{7, 3, 9, 3, 1} {
+		heap.Push(h, e)
+	}
+
+	s := FromHeap[int](h)
+	if !slices.Equal(s.items, []int{1, 3, 7, 9}) {
+		t.Errorf("Expected %v, got %v", []int{1, 3, 7, 9}, s.items)
+	}
+	if h.Len() != 0 {
+		t.Errorf("expected the heap to be drained, got %v", *h)
+	}
+}
+
+func TestFromChan(t *testing.T) {
+	ch := make(chan int)
+	go func() {
+		defer close(ch)
+		for _, e := range []int{5, 1, 3, 1, 5} {
+			ch <- e
+		}
+	}()
+
+	s := FromChan(ch)
+	if !slices.Equal(s.items, []int{1, 3, 5}) {
+		t.Errorf("Expected %v, got %v", []int{1, 3, 5}, s.items)
+	}
+}
+
+func TestHash(t *testing.T) {
+	h := fnv.New64a()
+
+	if From(3, 1, 2).Hash(h) != From(1, 2, 3).Hash(h) {
+		t.Errorf("expected equal sets to have equal hashes")
+	}
+	if From(1, 2, 3).Hash(h) == From(1, 2, 4).Hash(h) {
+		t.Errorf("expected different sets to have different hashes")
+	}
+	if From("ab", "c").Hash(h) == From("a", "bc").Hash(h) {
+		t.Errorf("expected different string sets to have different hashes")
+	}
+	if From(0.0, 1.5).Hash(h) != From(math.Copysign(0, -1), 1.5).Hash(h) {
+		t.Errorf("expected 0 and -0 to have equal hashes")
+	}
+}
+
+func TestKey(t *testing.T) {
+	cases := []struct {
+		s1, s2   *Ordered[string]
+		expected bool
+	}{
+		{s1: From("a", "b"), s2: From("b", "a", "b"), expected: true},
+		{s1: From("ab", "c"), s2: From("a", "bc"), expected: false},
+		{s1: From(""), s2: New[string](1), expected: false},
+		{s1: New[string](1), s2: New[string](10), expected: true},
+	}
+
+	for i, test := range cases {
+		t.Run(fmt.Sprintf("Case_%d", i), func(t *testing.T) {
+			if res := test.s1.Key() == test.s2.Key(); res != test.expected {
+				t.Errorf("expected keys equality to be %t, got %t", test.expected, res)
+			}
+		})
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	s := From(1, 2, 3).WithFingerprint()
+	initial := s.Fingerprint()
+
+	if initial != From(3, 2, 1).Fingerprint() {
+		t.Fatalf("expected equal sets to have equal fingerprints")
+	}
+
+	s.Add(10)
+	s.Add(20)
+	s.Add(30)
+	s.Remove(2)
+	s.RemoveBetween(15, 25)
+	s.PopMin()
+	s.PopMax()
+
+	if s.Fingerprint() != From(3, 10).Fingerprint() {
+		t.Errorf("fingerprint of %v is not up to date", s.items)
+	}
+
+	s.Add(1)
+	s.Add(2)
+	s.Remove(10)
+	if s.Fingerprint() != initial {
+		t.Errorf("expected the fingerprint to go back to the initial value")
+	}
+
+	s.Clear()
+	if s.Fingerprint() != New[int](1).Fingerprint() {
+		t.Errorf("expected the fingerprint of the cleared set to be the empty one")
+	}
+}
+
+func TestRelease(t *testing.T) {
+	s := From(3, 1, 2).WithFingerprint().WithChangeLog()
+	seq := s.ChangeSeq()
+	internal := s.items
+
+	items := s.Release()
+	if !slices.Equal(items, []int{1, 2, 3}) || &items[0] != &internal[0] {
+		t.Errorf("expected the internal slice [1 2 3], got %v", items)
+	}
+
+	if !s.IsEmpty() || s.Fingerprint() != 0 {
+		t.Errorf("expected an empty set, got %v", s.items)
+	}
+	if changes := slices.Collect(s.Changes(seq)); len(changes) != 3 {
+		t.Errorf("expected 3 removals in the changelog, got %v", changes)
+	}
+
+	s.Add(5)
+	if items[0] != 1 || !slices.Equal(s.items, []int{5}) {
+		t.Errorf("expected the released slice to be independent of the set")
+	}
+}
+
+func TestClearAndShrink(t *testing.T) {
+	s := New[int](1000)
+	for i := range 1000 {
+		s.Add(i)
+	}
+
+	s.ClearAndShrink(16)
+	if !s.IsEmpty() || s.Capacity() != 16 {
+		t.Errorf("expected an empty set with capacity 16, got size %d and capacity %d", s.Size(), s.Capacity())
+	}
+
+	s.Add(1)
+	s.ClearAndShrink(32)
+	if !s.IsEmpty() || s.Capacity() != 16 {
+		t.Errorf("expected the capacity to be kept at 16, got %d", s.Capacity())
+	}
+}
+
 func TestContains(t *testing.T) {
 	initial := []int{5, 10, 15, 20}
 	s := From(initial...)
@@ -35,6 +305,84 @@ func TestContains(t *testing.T) {
 	}
 }
 
+func TestWithBloom(t *testing.T) {
+	s := From(1, 2, 3).WithBloom(1024)
+	for i := 10; i < 20; i++ {
+		s.Add(i)
+	}
+
+	for _, e := range s.items {
+		if !s.Contains(e) {
+			t.Errorf("Contains(%d) expected true got false", e)
+		}
+	}
+	if s.Contains(100) {
+		t.Errorf("Contains(100) expected false got true")
+	}
+
+	clone := s.Clone()
+	if !clone.Contains(15) {
+		t.Errorf("expected the clone to contain 15")
+	}
+
+	// like the results with non-empty operands, the results with an empty one have no filter
+	empty := New[int](1)
+	for _, r := range []*Ordered[int]{s.Union(empty), empty.Union(s), s.Difference(empty), Merge(s), Intersect(s)} {
+		if r.bloom != nil {
+			t.Errorf("expected no filter in the result %v", r.items)
+		}
+	}
+
+	s.Clear()
+	for i := range 20 {
+		if s.bloom.mayContain(i) {
+			t.Errorf("expected the filter to be reset, but it may contain %d", i)
+		}
+	}
+}
+
+func TestNaN(t *testing.T) {
+	nan := math.NaN()
+	otherNaN := math.Float64frombits(math.Float64bits(nan) + 1)
+	negZero := math.Copysign(0, -1)
+
+	s := From(1, nan, 2, otherNaN, negZero, 0).WithBloom(64).WithFingerprint()
+	if s.Size() != 4 || !isNaN(s.items[0]) || !slices.Equal(s.items[1:], []float64{0, 1, 2}) {
+		t.Fatalf("expected a single NaN first and a single zero, got %v", s.items)
+	}
+
+	if !s.Contains(nan) || !s.Contains(otherNaN) {
+		t.Errorf("expected the set to contain NaN")
+	}
+	if s.Add(otherNaN) || s.Add(0) {
+		t.Errorf("expected NaN and 0 to be already present")
+	}
+	if !s.IsEqual(s.Clone()) {
+		t.Errorf("expected the set to be equal to its clone")
+	}
+	if s.Key() != From(otherNaN, 1, 2, 0).Key() || s.Fingerprint() != From(otherNaN, 1, 2, 0).Fingerprint() {
+		t.Errorf("expected equal sets with different NaNs to have equal keys and fingerprints")
+	}
+
+	inter := s.Intersect(From(nan, 2))
+	if inter.Size() != 2 || !isNaN(inter.items[0]) || inter.items[1] != 2 {
+		t.Errorf("expected intersection to be [NaN 2], got %v", inter.items)
+	}
+	if AreDisjoint(s, From(nan)) {
+		t.Errorf("expected sets sharing NaN to not be disjoint")
+	}
+	if diff := s.Difference(From(otherNaN)); !slices.Equal(diff.items, []float64{0, 1, 2}) {
+		t.Errorf("expected difference to be [0 1 2], got %v", diff.items)
+	}
+
+	if !s.Remove(nan) || s.Contains(nan) {
+		t.Errorf("expected NaN to be removed")
+	}
+	if s.Fingerprint() != From(0.0, 1, 2).Fingerprint() {
+		t.Errorf("fingerprint of %v is not up to date", s.items)
+	}
+}
+
 func TestAdd(t *testing.T) {
 	cases := []struct {
 		toAdd    []int
@@ -77,6 +425,38 @@ func TestAdd(t *testing.T) {
 	}
 }
 
+func TestAddSorted(t *testing.T) {
+	cases := []struct {
+		initial  []int
+		items    []int
+		expected int
+		result   []int
+	}{
+		{initial: []int{}, items: []int{}, expected: 0, result: []int{}},
+		{initial: []int{}, items: []int{1, 1, 2}, expected: 2, result: []int{1, 2}},
+		{initial: []int{2, 4, 6}, items: []int{1, 3, 5, 7}, expected: 4, result: []int{1, 2, 3, 4, 5, 6, 7}},
+		{initial: []int{2, 4, 6}, items: []int{2, 4, 4, 6}, expected: 0, result: []int{2, 4, 6}},
+		{initial: []int{2, 4, 6}, items: []int{0, 0, 4, 8, 8}, expected: 2, result: []int{0, 2, 4, 6, 8}},
+	}
+
+	for i, test := range cases {
+		t.Run(fmt.Sprintf("Case_%d", i), func(t *testing.T) {
+			s := From(test.initial...).WithFingerprint()
+			if res := s.AddSorted(test.items); res != test.expected {
+				t.Errorf("AddSorted(%v) expected %d, got %d", test.items, test.expected, res)
+			}
+
+			if !slices.Equal(s.items, test.result) {
+				t.Errorf("Items mismatch.\nExpected: %v\nActual: %v", test.result, s.items)
+			}
+
+			if expected := From(test.result...).Fingerprint(); s.Fingerprint() != expected {
+				t.Errorf("Fingerprint mismatch.\nExpected: %v\nActual: %v", expected, s.Fingerprint())
+			}
+		})
+	}
+}
+
 func TestRemove(t *testing.T) {
 	cases := []struct {
 		initial  []int
@@ -123,6 +503,163 @@ func TestRemove(t *testing.T) {
 	}
 }
 
+func TestReplaceAt(t *testing.T) {
+	cases := []struct {
+		index    int
+		element  int
+		expected bool
+		items    []int
+	}{
+		{index: 0, element: 5, expected: true, items: []int{5, 20, 30}},
+		{index: 0, element: 20, expected: false, items: []int{10, 20, 30}},
+		{index: 1, element: 15, expected: true, items: []int{10, 15, 30}},
+		{index: 1, element: 10, expected: false, items: []int{10, 20, 30}},
+		{index: 1, element: 31, expected: false, items: []int{10, 20, 30}},
+		{index: 2, element: 100, expected: true, items: []int{10, 20, 100}},
+		{index: 2, element: 20, expected: false, items: []int{10, 20, 30}},
+	}
+
+	for i, test := range cases {
+		t.Run(fmt.Sprintf("Case_%d", i), func(t *testing.T) {
+			s := From(10, 20, 30).WithFingerprint()
+			if res := s.ReplaceAt(test.index, test.element); res != test.expected {
+				t.Errorf("ReplaceAt(%d, %d) expected %t got %t", test.index, test.element, test.expected, res)
+			}
+
+			if !slices.Equal(s.items, test.items) {
+				t.Errorf("Items mismatch.\nExpected: %v\nActual: %v", test.items, s.items)
+			}
+
+			if expected := From(test.items...).Fingerprint(); s.Fingerprint() != expected {
+				t.Errorf("Fingerprint mismatch.\nExpected: %v\nActual: %v", expected, s.Fingerprint())
+			}
+		})
+	}
+}
+
+func TestMarkRemove(t *testing.T) {
+	s := From(1, 2, 3, 4, 5, 6, 7, 8).WithFingerprint()
+	for _, e := range s.Ascend() {
+		if e%2 == 0 {
+			s.MarkRemove(e)
+		}
+	}
+
+	if s.MarkRemove(100) {
+		t.Errorf("expected MarkRemove(100) to be false")
+	}
+	s.MarkRemove(4)
+
+	if s.Size() != 8 {
+		t.Errorf("expected the marked elements to stay until Compact, got %v", s.items)
+	}
+
+	if removed := s.Compact(); removed != 4 {
+		t.Errorf("expected 4 elements removed, got %d", removed)
+	}
+	if !slices.Equal(s.items, []int{1, 3, 5, 7}) {
+		t.Errorf("Items mismatch.\nExpected: %v\nActual: %v", []int{1, 3, 5, 7}, s.items)
+	}
+	if s.Fingerprint() != From(1, 3, 5, 7).Fingerprint() {
+		t.Errorf("fingerprint of %v is not up to date", s.items)
+	}
+	if removed := s.Compact(); removed != 0 {
+		t.Errorf("expected no elements removed, got %d", removed)
+	}
+}
+
+func TestRemoveIndices(t *testing.T) {
+	cases := []struct {
+		idxs     []int
+		expected int
+		items    []int
+	}{
+		{idxs: nil, expected: 0, items: []int{10, 20, 30, 40, 50}},
+		{idxs: []int{0}, expected: 1, items: []int{20, 30, 40, 50}},
+		{idxs: []int{4, 0, 2}, expected: 3, items: []int{20, 40}},
+		{idxs: []int{1, 1, 3, 1}, expected: 2, items: []int{10, 30, 50}},
+		{idxs: []int{0, 1, 2, 3, 4}, expected: 5, items: []int{}},
+	}
+
+	for i, test := range cases {
+		t.Run(fmt.Sprintf("Case_%d", i), func(t *testing.T) {
+			s := From(10, 20, 30, 40, 50)
+			if res := s.RemoveIndices(test.idxs...); res != test.expected {
+				t.Errorf("RemoveIndices(%v) expected %d got %d", test.idxs, test.expected, res)
+			}
+
+			if !slices.Equal(s.items, test.items) {
+				t.Errorf("Items mismatch.\nExpected: %v\nActual: %v", test.items, s.items)
+			}
+		})
+	}
+}
+
+func TestExtractFunc(t *testing.T) {
+	cases := []struct {
+		pred      func(int) bool
+		extracted []int
+		items     []int
+	}{
+		{pred: func(int) bool { return false }, extracted: nil, items: []int{10, 20, 30, 40, 50}},
+		{pred: func(e int) bool { return e%20 == 0 }, extracted: []int{20, 40}, items: []int{10, 30, 50}},
+		{pred: func(int) bool { return true }, extracted: []int{10, 20, 30, 40, 50}, items: []int{}},
+	}
+
+	for i, test := range cases {
+		t.Run(fmt.Sprintf("Case_%d", i), func(t *testing.T) {
+			s := From(10, 20, 30, 40, 50)
+			extracted := s.ExtractFunc(test.pred)
+
+			if !slices.Equal(extracted.items, test.extracted) {
+				t.Errorf("Extracted mismatch.\nExpected: %v\nActual: %v", test.extracted, extracted.items)
+			}
+			if !slices.Equal(s.items, test.items) {
+				t.Errorf("Items mismatch.\nExpected: %v\nActual: %v", test.items, s.items)
+			}
+
+			extracted.Add(25)
+			if s.Contains(25) {
+				t.Error("the extracted set shares memory with the set")
+			}
+		})
+	}
+}
+
+func TestApplyDiff(t *testing.T) {
+	cases := []struct {
+		add, remove []int
+		added       int
+		removed     int
+		items       []int
+	}{
+		{add: nil, remove: nil, added: 0, removed: 0, items: []int{10, 20, 30}},
+		{add: []int{25, 5, 5, 40}, remove: nil, added: 3, removed: 0, items: []int{5, 10, 20, 25, 30, 40}},
+		{add: nil, remove: []int{30, 10, 15}, added: 0, removed: 2, items: []int{20}},
+		{add: []int{20, 35}, remove: []int{20, 30, 35}, added: 1, removed: 1, items: []int{10, 20, 35}},
+		{add: []int{1}, remove: []int{10, 20, 30}, added: 1, removed: 3, items: []int{1}},
+	}
+
+	for i, test := range cases {
+		t.Run(fmt.Sprintf("Case_%d", i), func(t *testing.T) {
+			s := From(10, 20, 30).WithFingerprint()
+			added, removed := s.ApplyDiff(test.add, test.remove)
+
+			if added != test.added || removed != test.removed {
+				t.Errorf("ApplyDiff expected (%d, %d), got (%d, %d)", test.added, test.removed, added, removed)
+			}
+
+			if !slices.Equal(s.items, test.items) {
+				t.Errorf("Items mismatch.\nExpected: %v\nActual: %v", test.items, s.items)
+			}
+
+			if expected := From(test.items...).Fingerprint(); s.Fingerprint() != expected {
+				t.Errorf("Fingerprint mismatch.\nExpected: %v\nActual: %v", expected, s.Fingerprint())
+			}
+		})
+	}
+}
+
 func TestRemoveBefore(t *testing.T) {
 	cases := []struct {
 		initial  []int
@@ -209,6 +746,42 @@ func TestRemoveFrom(t *testing.T) {
 	}
 }
 
+func TestTrimPercentile(t *testing.T) {
+	cases := []struct {
+		initial   []int
+		low, high float64
+		expected  int
+		items     []int
+	}{
+		{initial: []int{}, low: 0.1, high: 0.1, expected: 0, items: []int{}},
+		{initial: []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, low: 0.1, high: 0.2, expected: 3, items: []int{2, 3, 4, 5, 6, 7, 8}},
+		{initial: []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, low: 0.05, high: 0, expected: 0, items: []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}},
+		{initial: []int{1, 2, 3, 4}, low: 0.5, high: 0.5, expected: 4, items: []int{}},
+	}
+
+	for i, test := range cases {
+		t.Run(fmt.Sprintf("Case_%d", i), func(t *testing.T) {
+			s := From(test.initial...)
+			res := s.TrimPercentile(test.low, test.high)
+
+			if res != test.expected {
+				t.Errorf("TrimPercentile results mismatch.\nExpected: %v\nActual: %v", test.expected, res)
+			}
+
+			if !slices.Equal(s.items, test.items) {
+				t.Errorf("Items mismatch.\nExpected: %v\nActual: %v", test.items, s.items)
+			}
+		})
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic for low + high > 1")
+		}
+	}()
+	From(1, 2).TrimPercentile(0.6, 0.6)
+}
+
 func TestRemoveBetween(t *testing.T) {
 	cases := []struct {
 		initial  []int
@@ -252,6 +825,118 @@ func TestRemoveBetween(t *testing.T) {
 	}
 }
 
+func TestKeepBetween(t *testing.T) {
+	cases := []struct {
+		min, max int
+		expected int
+		items    []int
+	}{
+		{min: 20, max: 40, expected: 3, items: []int{20, 30}},
+		{min: 15, max: 35, expected: 3, items: []int{20, 30}},
+		{min: 0, max: 100, expected: 0, items: []int{10, 20, 30, 40, 50}},
+		{min: 10, max: 30, expected: 3, items: []int{10, 20}},
+		{min: 60, max: 70, expected: 5, items: []int{}},
+		{min: 30, max: 30, expected: 5, items: []int{}},
+	}
+
+	for i, test := range cases {
+		t.Run(fmt.Sprintf("Case_%d", i), func(t *testing.T) {
+			s := From(10, 20, 30, 40, 50)
+			if res := s.KeepBetween(test.min, test.max); res != test.expected {
+				t.Errorf("KeepBetween(%d, %d) expected %d got %d", test.min, test.max, test.expected, res)
+			}
+
+			if !slices.Equal(s.items, test.items) {
+				t.Errorf("Items mismatch.\nExpected: %v\nActual: %v", test.items, s.items)
+			}
+		})
+	}
+}
+
+func TestWithShrink(t *testing.T) {
+	cases := []struct {
+		remove   int
+		capacity int
+	}{
+		{remove: 10, capacity: 200},
+		{remove: 60, capacity: 80},
+		{remove: 90, capacity: 20},
+		{remove: 100, capacity: 1},
+	}
+
+	for i, test := range cases {
+		t.Run(fmt.Sprintf("Case_%d", i), func(t *testing.T) {
+			s := New[int](200).WithShrink(0.25)
+			for i := range 100 {
+				s.Add(i)
+			}
+
+			s.RemoveBefore(test.remove)
+			if s.Capacity() != test.capacity {
+				t.Errorf("expected capacity %d, got %d", test.capacity, s.Capacity())
+			}
+
+			if s.Size() != 100-test.remove || (s.Size() > 0 && s.Min() != test.remove) {
+				t.Errorf("unexpected items after shrinking: %v", s.items)
+			}
+		})
+	}
+}
+
+func TestFindMany(t *testing.T) {
+	s := From(10, 20, 30)
+	probes := []int{35, 20, 5, 20, 25, 10}
+
+	idxs, found := s.FindMany(probes)
+	for i, p := range probes {
+		idx, ok := s.Find(p)
+		if idxs[i] != idx || found[i] != ok {
+			t.Errorf("FindMany(%d) expected (%d, %t), got (%d, %t)", p, idx, ok, idxs[i], found[i])
+		}
+	}
+}
+
+func TestIndexFunc(t *testing.T) {
+	s := From(1, 4, 6, 9)
+	isEven := func(e int) bool { return e%2 == 0 }
+	isNegative := func(e int) bool { return e < 0 }
+
+	if !s.ContainsFunc(isEven) || s.ContainsFunc(isNegative) {
+		t.Errorf("ContainsFunc mismatch")
+	}
+
+	if i := s.IndexFunc(isEven); i != 1 {
+		t.Errorf("IndexFunc expected 1, got %d", i)
+	}
+	if i := s.IndexFunc(isNegative); i != -1 {
+		t.Errorf("IndexFunc expected -1, got %d", i)
+	}
+}
+
+func TestIndexRange(t *testing.T) {
+	s := From(1, 3, 5, 7, 9)
+
+	cases := []struct {
+		min, max   int
+		start, end int
+	}{
+		{min: -1, max: 10, start: 0, end: 5},
+		{min: 3, max: 7, start: 1, end: 3},
+		{min: 4, max: 8, start: 2, end: 4},
+		{min: 8, max: 8, start: 4, end: 4},
+		{min: 10, max: 20, start: 5, end: 5},
+	}
+
+	for i, test := range cases {
+		t.Run(fmt.Sprintf("Case_%d", i), func(t *testing.T) {
+			start, end := s.IndexRange(test.min, test.max)
+			if start != test.start || end != test.end {
+				t.Errorf("IndexRange(%d, %d) expected [%d, %d), got [%d, %d)", test.min, test.max, test.start, test.end, start, end)
+			}
+		})
+	}
+}
+
 func TestIsEqual(t *testing.T) {
 	s1 := From(1, 2, 3)
 	s2 := From(3, 2, 1)
@@ -278,6 +963,28 @@ func TestIsEqual(t *testing.T) {
 	}
 }
 
+func TestEqualFunc(t *testing.T) {
+	sameID := func(id int, key string) bool { return fmt.Sprintf("user:%d", id) == key }
+	cases := []struct {
+		ids      []int
+		keys     []string
+		expected bool
+	}{
+		{ids: nil, keys: nil, expected: true},
+		{ids: []int{3, 1, 2}, keys: []string{"user:1", "user:2", "user:3"}, expected: true},
+		{ids: []int{1, 2}, keys: []string{"user:1", "user:3"}, expected: false},
+		{ids: []int{1, 2}, keys: []string{"user:1", "user:2", "user:3"}, expected: false},
+	}
+
+	for i, test := range cases {
+		t.Run(fmt.Sprintf("Case_%d", i), func(t *testing.T) {
+			if res := EqualFunc(From(test.ids...), From(test.keys...), sameID); res != test.expected {
+				t.Errorf("EqualFunc expected %t, got %t", test.expected, res)
+			}
+		})
+	}
+}
+
 func TestMin(t *testing.T) {
 	cases := []struct {
 		set      *Ordered[int]
@@ -318,6 +1025,36 @@ func TestMax(t *testing.T) {
 	}
 }
 
+func TestPopMin(t *testing.T) {
+	s := From(10, 5, 20, 15)
+	expected := []int{5, 10, 15, 20}
+
+	for i, exp := range expected {
+		if res := s.PopMin(); res != exp {
+			t.Errorf("PopMin() %d failed.\nExpected: %d\nActual:   %d", i, exp, res)
+		}
+	}
+
+	if !s.IsEmpty() {
+		t.Errorf("expected empty set, got %v", s.items)
+	}
+}
+
+func TestPopMax(t *testing.T) {
+	s := From(10, 5, 20, 15)
+	expected := []int{20, 15, 10, 5}
+
+	for i, exp := range expected {
+		if res := s.PopMax(); res != exp {
+			t.Errorf("PopMax() %d failed.\nExpected: %d\nActual:   %d", i, exp, res)
+		}
+	}
+
+	if !s.IsEmpty() {
+		t.Errorf("expected empty set, got %v", s.items)
+	}
+}
+
 func TestMinK(t *testing.T) {
 	cases := []struct {
 		set      *Ordered[int]
@@ -364,6 +1101,91 @@ func TestMaxK(t *testing.T) {
 	}
 }
 
+func TestPage(t *testing.T) {
+	s := From(1, 2, 3, 4, 5)
+
+	cases := []struct {
+		offset, limit int
+		expected      []int
+	}{
+		{offset: 0, limit: 2, expected: []int{1, 2}},
+		{offset: 2, limit: 2, expected: []int{3, 4}},
+		{offset: 4, limit: 2, expected: []int{5}},
+		{offset: 5, limit: 2, expected: []int{}},
+		{offset: 100, limit: 2, expected: []int{}},
+		{offset: 1, limit: 0, expected: []int{}},
+		{offset: 0, limit: math.MaxInt, expected: []int{1, 2, 3, 4, 5}},
+	}
+
+	for i, test := range cases {
+		t.Run(fmt.Sprintf("Case_%d", i), func(t *testing.T) {
+			page, total := s.Page(test.offset, test.limit)
+			if !slices.Equal(page, test.expected) {
+				t.Errorf("Page(%d, %d) failed.\nExpected: %v\nActual: %v", test.offset, test.limit, test.expected, page)
+			}
+			if total != 5 {
+				t.Errorf("expected total 5, got %d", total)
+			}
+		})
+	}
+}
+
+func TestMinKAppend(t *testing.T) {
+	s := From(10, 5, 20, 15)
+	buf := make([]int, 0, 10)
+
+	buf = s.MinKAppend(buf, 2)
+	buf = s.MinKAppend(buf, 10)
+	expected := []int{5, 10, 5, 10, 15, 20}
+
+	if !slices.Equal(buf, expected) {
+		t.Errorf("MinKAppend failed.\nExpected: %v\nActual: %v", expected, buf)
+	}
+
+	allocs := testing.AllocsPerRun(10, func() { s.MinKAppend(buf[:0], 3) })
+	if allocs != 0 {
+		t.Errorf("expected no allocations, got %v", allocs)
+	}
+}
+
+func TestMaxKAppend(t *testing.T) {
+	s := From(10, 5, 20, 15)
+	buf := make([]int, 0, 10)
+
+	buf = s.MaxKAppend(buf, 2)
+	buf = s.MaxKAppend(buf, 0)
+	buf = s.MaxKAppend(buf, 10)
+	expected := []int{15, 20, 5, 10, 15, 20}
+
+	if !slices.Equal(buf, expected) {
+		t.Errorf("MaxKAppend failed.\nExpected: %v\nActual: %v", expected, buf)
+	}
+}
+
+func TestSortInterface(t *testing.T) {
+	s := From(5, 1, 3)
+	view := s.SortInterface()
+
+	if !sort.IsSorted(view) {
+		t.Errorf("expected view to be sorted")
+	}
+
+	s.Add(2)
+	if view.Len() != 4 {
+		t.Errorf("expected view to reflect the set size 4, got %d", view.Len())
+	}
+	if !view.Less(0, 1) || view.Less(3, 2) {
+		t.Errorf("Less doesn't reflect the set ordering %v", s.items)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected Swap to panic")
+		}
+	}()
+	view.Swap(0, 1)
+}
+
 func collect[T any](seq iter.Seq2[int, T]) []T {
 	var out []T
 	for _, v := range seq {
@@ -422,6 +1244,54 @@ func TestBetweenDesc(t *testing.T) {
 	}
 }
 
+func TestBetweenValues(t *testing.T) {
+	s := From(1, 3, 5, 7, 9)
+
+	cases := []struct {
+		min, max int
+		expected []int
+	}{
+		{min: -1, max: 10, expected: []int{1, 3, 5, 7, 9}},
+		{min: 3, max: 7, expected: []int{3, 5}},
+		{min: 8, max: 8, expected: nil},
+		{min: 10, max: 20, expected: nil},
+	}
+
+	for i, test := range cases {
+		t.Run(fmt.Sprintf("Case_%d", i), func(t *testing.T) {
+			result := slices.Collect(s.BetweenValues(test.min, test.max))
+			if !slices.Equal(result, test.expected) {
+				t.Errorf("BetweenValues(%d, %d) failed.\nExpected: %v\nActual: %v", test.min, test.max, test.expected, result)
+			}
+		})
+	}
+}
+
+func TestPairs(t *testing.T) {
+	cases := []struct {
+		set      []int
+		expected [][2]int
+	}{
+		{set: []int{}, expected: nil},
+		{set: []int{1}, expected: nil},
+		{set: []int{1, 2}, expected: [][2]int{{1, 2}}},
+		{set: []int{10, 1, 5}, expected: [][2]int{{1, 5}, {5, 10}}},
+	}
+
+	for i, test := range cases {
+		t.Run(fmt.Sprintf("Case_%d", i), func(t *testing.T) {
+			var pairs [][2]int
+			for a, b := range From(test.set...).Pairs() {
+				pairs = append(pairs, [2]int{a, b})
+			}
+
+			if !slices.Equal(pairs, test.expected) {
+				t.Errorf("Expected %v, got %v", test.expected, pairs)
+			}
+		})
+	}
+}
+
 // --- Binary Set Operation Tests ---
 
 func TestIntersect(t *testing.T) {
@@ -449,6 +1319,10 @@ func TestIntersect(t *testing.T) {
 				t.Errorf("Expected %v, got %v", test.expected, inter.items)
 			}
 
+			if count := s1.IntersectCount(s2); count != len(test.expected) {
+				t.Errorf("IntersectCount expected %d, got %d", len(test.expected), count)
+			}
+
 			if !s1.IsEqual(o1) {
 				t.Errorf("s1 mutated. before %v, after %v", o1, s1)
 			}
@@ -459,6 +1333,38 @@ func TestIntersect(t *testing.T) {
 	}
 }
 
+func TestContainsCount(t *testing.T) {
+	s := From(1, 3, 5, 7)
+	if count := s.ContainsCount(0, 1, 1, 4, 7, 8); count != 3 {
+		t.Errorf("ContainsCount expected 3, got %d", count)
+	}
+
+	allocs := testing.AllocsPerRun(100, func() { s.IntersectCount(s) })
+	if allocs != 0 {
+		t.Errorf("IntersectCount expected 0 allocations, got %v", allocs)
+	}
+}
+
+func TestContainsMany(t *testing.T) {
+	cases := []struct {
+		probes   []int
+		expected []bool
+	}{
+		{probes: nil, expected: []bool{}},
+		{probes: []int{1, 3, 5}, expected: []bool{true, true, true}},
+		{probes: []int{8, 0, 7, 3, 3, 4}, expected: []bool{false, false, true, true, true, false}},
+	}
+
+	for i, test := range cases {
+		t.Run(fmt.Sprintf("Case_%d", i), func(t *testing.T) {
+			s := From(1, 3, 5, 7)
+			if res := s.ContainsMany(test.probes); !slices.Equal(res, test.expected) {
+				t.Errorf("Expected %v, got %v", test.expected, res)
+			}
+		})
+	}
+}
+
 func TestDifference(t *testing.T) {
 	cases := []struct {
 		s1       []int
@@ -486,6 +1392,14 @@ func TestDifference(t *testing.T) {
 				t.Errorf("Expected %v, got %v", test.expected, diff.items)
 			}
 
+			if count := s1.DifferenceCount(s2); count != len(test.expected) {
+				t.Errorf("DifferenceCount expected %d, got %d", len(test.expected), count)
+			}
+
+			if complement := s2.Complement(s1); !slices.Equal(complement.items, test.expected) {
+				t.Errorf("Complement expected %v, got %v", test.expected, complement.items)
+			}
+
 			if !s1.IsEqual(o1) {
 				t.Errorf("s1 mutated. before %v, after %v", o1, s1)
 			}
@@ -522,6 +1436,10 @@ func TestUnion(t *testing.T) {
 				t.Errorf("Expected %v, got %v", test.expected, union.items)
 			}
 
+			if count := s1.UnionCount(s2); count != len(test.expected) {
+				t.Errorf("UnionCount expected %d, got %d", len(test.expected), count)
+			}
+
 			if !s1.IsEqual(o1) {
 				t.Errorf("s1 mutated. before %v, after %v", o1, s1)
 			}
@@ -532,6 +1450,37 @@ func TestUnion(t *testing.T) {
 	}
 }
 
+func TestSeqOperations(t *testing.T) {
+	cases := []struct {
+		set        []int
+		seq        []int
+		union      []int
+		difference []int
+	}{
+		{set: []int{}, seq: []int{3, 1, 3}, union: []int{1, 3}, difference: []int{}},
+		{set: []int{1, 2, 3}, seq: []int{}, union: []int{1, 2, 3}, difference: []int{1, 2, 3}},
+		{set: []int{1, 3, 5}, seq: []int{6, 3, 0, 3, 6}, union: []int{0, 1, 3, 5, 6}, difference: []int{1, 5}},
+	}
+
+	for i, test := range cases {
+		t.Run(fmt.Sprintf("Case_%d", i), func(t *testing.T) {
+			s := From(test.set...)
+
+			if union := s.UnionSeq(slices.Values(test.seq)); !slices.Equal(union.items, test.union) {
+				t.Errorf("UnionSeq expected %v, got %v", test.union, union.items)
+			}
+
+			if diff := s.DifferenceSeq(slices.Values(test.seq)); !slices.Equal(diff.items, test.difference) {
+				t.Errorf("DifferenceSeq expected %v, got %v", test.difference, diff.items)
+			}
+
+			if !slices.Equal(s.items, test.set) {
+				t.Errorf("set mutated. before %v, after %v", test.set, s.items)
+			}
+		})
+	}
+}
+
 func TestSymmetricDifference(t *testing.T) {
 	cases := []struct {
 		s1       []int
@@ -683,6 +1632,166 @@ func TestIntersectMulti(t *testing.T) {
 	}
 }
 
+func TestPowerSet(t *testing.T) {
+	cases := []struct {
+		set      []int
+		expected [][]int
+	}{
+		{set: []int{}, expected: [][]int{{}}},
+		{set: []int{1}, expected: [][]int{{}, {1}}},
+		{set: []int{3, 1, 2}, expected: [][]int{{}, {1}, {2}, {1, 2}, {3}, {1, 3}, {2, 3}, {1, 2, 3}}},
+	}
+
+	for i, test := range cases {
+		t.Run(fmt.Sprintf("Case_%d", i), func(t *testing.T) {
+			var subsets [][]int
+			for subset := range PowerSet(From(test.set...)) {
+				subsets = append(subsets, subset.items)
+			}
+
+			if !slices.EqualFunc(subsets, test.expected, slices.Equal) {
+				t.Errorf("Expected %v, got %v", test.expected, subsets)
+			}
+		})
+	}
+}
+
+func TestMergeJoin(t *testing.T) {
+	cases := []struct {
+		s1       []int
+		s2       []int
+		expected []Joined[int]
+	}{
+		{s1: []int{}, s2: []int{}, expected: nil},
+		{
+			s1: []int{1, 3, 5},
+			s2: []int{3, 4},
+			expected: []Joined[int]{
+				{Value: 1, InA: true},
+				{Value: 3, InA: true, InB: true},
+				{Value: 4, InB: true},
+				{Value: 5, InA: true},
+			},
+		},
+		{
+			s1: []int{},
+			s2: []int{2, 6},
+			expected: []Joined[int]{
+				{Value: 2, InB: true},
+				{Value: 6, InB: true},
+			},
+		},
+	}
+
+	for i, test := range cases {
+		t.Run(fmt.Sprintf("Case_%d", i), func(t *testing.T) {
+			joins := slices.Collect(MergeJoin(From(test.s1...), From(test.s2...)))
+			if !slices.Equal(joins, test.expected) {
+				t.Errorf("Expected %v, got %v", test.expected, joins)
+			}
+		})
+	}
+}
+
+func TestAreDisjoint(t *testing.T) {
+	cases := []struct {
+		sets     [][]int
+		expected bool
+	}{
+		{sets: nil, expected: true},
+		{sets: [][]int{{1, 2, 3}}, expected: true},
+		{sets: [][]int{{1, 2, 3}, {}, nil, {5, 4}}, expected: true},
+		{sets: [][]int{{1, 5, 9}, {2, 6}, {3, 7, 100}}, expected: true},
+		{sets: [][]int{{1, 5, 9}, {2, 6}, {3, 9}}, expected: false},
+		{sets: [][]int{{5}, {3}, {5}}, expected: false},
+	}
+
+	for i, test := range cases {
+		t.Run(fmt.Sprintf("Case_%d", i), func(t *testing.T) {
+			sets := make([]*Ordered[int], len(test.sets))
+			for i := range test.sets {
+				sets[i] = From(test.sets[i]...)
+			}
+
+			if res := AreDisjoint(sets...); res != test.expected {
+				t.Errorf("Expected %t, got %t", test.expected, res)
+			}
+		})
+	}
+}
+
+func TestCounts(t *testing.T) {
+	type counted struct {
+		e     int
+		count int
+	}
+
+	cases := []struct {
+		sets     [][]int
+		expected []counted
+	}{
+		{sets: nil, expected: nil},
+		{sets: [][]int{{}, nil}, expected: nil},
+		{sets: [][]int{{1, 2}}, expected: []counted{{1, 1}, {2, 1}}},
+		{sets: [][]int{{1, 3}, {}, {3, 2}}, expected: []counted{{1, 1}, {2, 1}, {3, 2}}},
+		{sets: [][]int{{5, 6}, {5}, {4, 5, 6}}, expected: []counted{{4, 1}, {5, 3}, {6, 2}}},
+	}
+
+	for i, test := range cases {
+		t.Run(fmt.Sprintf("Case_%d", i), func(t *testing.T) {
+			sets := make([]*Ordered[int], len(test.sets))
+			for i := range test.sets {
+				sets[i] = From(test.sets[i]...)
+			}
+
+			var res []counted
+			for e, count := range Counts(sets...) {
+				res = append(res, counted{e, count})
+			}
+
+			if !slices.Equal(res, test.expected) {
+				t.Errorf("Expected %v, got %v", test.expected, res)
+			}
+		})
+	}
+}
+
+func TestMultiPartition(t *testing.T) {
+	type tagged struct {
+		e    int
+		mask uint64
+	}
+
+	cases := []struct {
+		sets     [][]int
+		expected []tagged
+	}{
+		{sets: nil, expected: nil},
+		{sets: [][]int{{1, 2}}, expected: []tagged{{1, 0b1}, {2, 0b1}}},
+		{sets: [][]int{{1, 3}, {}, {3, 2}}, expected: []tagged{{1, 0b001}, {2, 0b100}, {3, 0b101}}},
+		{sets: [][]int{{5}, {5}, {5}}, expected: []tagged{{5, 0b111}}},
+		{sets: [][]int{{1, 4}, {2, 4}, {3, 4}, {4}}, expected: []tagged{{1, 0b0001}, {2, 0b0010}, {3, 0b0100}, {4, 0b1111}}},
+	}
+
+	for i, test := range cases {
+		t.Run(fmt.Sprintf("Case_%d", i), func(t *testing.T) {
+			sets := make([]*Ordered[int], len(test.sets))
+			for i := range test.sets {
+				sets[i] = From(test.sets[i]...)
+			}
+
+			var res []tagged
+			for e, mask := range MultiPartition(sets...) {
+				res = append(res, tagged{e, mask})
+			}
+
+			if !slices.Equal(res, test.expected) {
+				t.Errorf("Expected %v, got %v", test.expected, res)
+			}
+		})
+	}
+}
+
 type bench struct {
 	size int
 	vals []int
@@ -754,6 +1863,18 @@ func BenchmarkContains(b *testing.B) {
 				}
 			})
 
+			b.Run("slice_set_bloom", func(b *testing.B) {
+				set := New[int](bench.size).WithBloom(10 * bench.size)
+				for _, v := range bench.vals {
+					set.Add(v)
+				}
+
+				b.ResetTimer()
+				for i := range b.N {
+					set.Contains(i)
+				}
+			})
+
 			b.Run("slice_set_custom", func(b *testing.B) {
 				set := NewCustom(cmp.Compare[int], bench.size)
 				for _, v := range bench.vals {
@@ -1007,3 +2128,40 @@ func BenchmarkSymmetricDifference(b *testing.B) {
 		})
 	}
 }
+
+func TestRuns(t *testing.T) {
+	cases := []struct {
+		items    []int
+		expected [][2]int
+	}{
+		{items: []int{}, expected: nil},
+		{items: []int{4}, expected: [][2]int{{4, 4}}},
+		{items: []int{1, 2, 3, 5, 7, 8}, expected: [][2]int{{1, 3}, {5, 5}, {7, 8}}},
+		{items: []int{-2, -1, 0, 1}, expected: [][2]int{{-2, 1}}},
+		{items: []int{0, 2, 4}, expected: [][2]int{{0, 0}, {2, 2}, {4, 4}}},
+	}
+
+	for i, test := range cases {
+		t.Run(fmt.Sprintf("Case_%d", i), func(t *testing.T) {
+			var runs [][2]int
+			for start, end := range Runs(From(test.items...)) {
+				runs = append(runs, [2]int{start, end})
+			}
+
+			if !slices.Equal(runs, test.expected) {
+				t.Errorf("Expected %v, got %v", test.expected, runs)
+			}
+		})
+	}
+
+	t.Run("overflow", func(t *testing.T) {
+		var runs [][2]uint8
+		for start, end := range Runs(From[uint8](0, 1, 254, 255)) {
+			runs = append(runs, [2]uint8{start, end})
+		}
+
+		if expected := [][2]uint8{{0, 1}, {254, 255}}; !slices.Equal(runs, expected) {
+			t.Errorf("Expected %v, got %v", expected, runs)
+		}
+	})
+}
@@ -0,0 +1,10 @@
+//go:build !smallsetdebug
+
+package smallset
+
+// owner records the goroutine that owns a set in debug builds, built with -tags smallsetdebug.
+// In normal builds it's empty and its checks compile to nothing.
+type owner struct{}
+
+func (*owner) check(string) {}
+func (*owner) release()     {}
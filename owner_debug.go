@@ -0,0 +1,50 @@
+//go:build smallsetdebug
+
+package smallset
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"strconv"
+	"sync/atomic"
+)
+
+// owner records the goroutine that owns a set in debug builds, built with -tags smallsetdebug.
+// The first goroutine that modifies the set becomes its owner, and modifications from any other
+// goroutine panic, turning silent corruptions caused by concurrent use into clear failures.
+type owner struct {
+	id atomic.Uint64
+}
+
+// check panics if the calling goroutine is not the owner, after making it the owner if there's none.
+func (o *owner) check(typ string) {
+	g := goroutineID()
+	if o.id.CompareAndSwap(0, g) {
+		return
+	}
+	if id := o.id.Load(); id != g {
+		panic(fmt.Sprintf("smallset.%s: modified by goroutine %d, but owned by goroutine %d. "+
+			"Sets are not safe for concurrent use: call Handoff before passing a set to another goroutine", typ, g, id))
+	}
+}
+
+// release removes the owner, so that the next goroutine that modifies the set becomes the owner.
+func (o *owner) release() {
+	o.id.Store(0)
+}
+
+// goroutineID returns the ID of the calling goroutine, parsed from the header of its stack trace,
+// which looks like "goroutine 42 [running]:". It's slow, which is fine for debug builds.
+func goroutineID() uint64 {
+	var buf [64]byte
+	header := buf[:runtime.Stack(buf[:], false)]
+	header = bytes.TrimPrefix(header, []byte("goroutine "))
+	header, _, _ = bytes.Cut(header, []byte(" "))
+
+	id, err := strconv.ParseUint(string(header), 10, 64)
+	if err != nil {
+		panic("smallset.goroutineID: unexpected stack trace header")
+	}
+	return id
+}
@@ -0,0 +1,71 @@
+//go:build smallsetdebug
+
+package smallset
+
+import (
+	"cmp"
+	"strings"
+	"testing"
+)
+
+// modifyIn calls modify in a new goroutine, and returns the message of its panic, if any.
+func modifyIn(modify func()) string {
+	done := make(chan string)
+	go func() {
+		defer func() {
+			msg, _ := recover().(string)
+			done <- msg
+		}()
+		modify()
+	}()
+	return <-done
+}
+
+func TestOwner(t *testing.T) {
+	s := New[int](10)
+	s.Add(1)
+
+	msg := modifyIn(func() { s.Add(2) })
+	if !strings.Contains(msg, "smallset.Ordered: modified by goroutine") {
+		t.Errorf("expected a panic for the cross-goroutine modification, got %q", msg)
+	}
+
+	s.Handoff()
+	if msg := modifyIn(func() { s.Add(3) }); msg != "" {
+		t.Errorf("expected no panic after Handoff, got %q", msg)
+	}
+
+	// reads are not checked
+	if !s.Contains(3) {
+		t.Errorf("expected the set to contain 3")
+	}
+}
+
+func TestCustomOwner(t *testing.T) {
+	s := NewCustom(cmp.Compare[int], 10)
+	s.Add(1)
+
+	msg := modifyIn(func() { s.Clear() })
+	if !strings.Contains(msg, "smallset.Custom: modified by goroutine") {
+		t.Errorf("expected a panic for the cross-goroutine modification, got %q", msg)
+	}
+}
+
+func TestOwnerAddSorted(t *testing.T) {
+	s := New[int](10).WithChangeLog()
+	s.Add(1)
+
+	msg := modifyIn(func() { s.AddSorted([]int{2, 3}) })
+	if !strings.Contains(msg, "smallset.Ordered: modified by goroutine") {
+		t.Errorf("expected a panic for the cross-goroutine modification, got %q", msg)
+	}
+
+	// the panic happens before the changelog records the elements
+	changes := 0
+	for range s.Changes(0) {
+		changes++
+	}
+	if changes != 1 {
+		t.Errorf("expected 1 change, got %d", changes)
+	}
+}
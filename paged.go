@@ -0,0 +1,278 @@
+package smallset
+
+import (
+	"cmp"
+	"iter"
+	"slices"
+	"sort"
+)
+
+// PagedStorage is a [Storage] that keeps the elements in a sequence of pages of bounded size,
+// also known as a tiered vector. Insertions and removals only shift the elements of one page,
+// instead of the whole slice, which extends the range of sizes where the set performs well
+// well past 1000 elements, at the cost of an extra binary search over the pages.
+// A page left with less than a quarter of pageSize elements by a removal is merged with a neighbour,
+// so that sets that shrink don't end up with many tiny pages.
+// Use it through [NewPaged] or [NewPagedCustom].
+type PagedStorage[T any] struct {
+	pages    [][]T // non-empty pages
+	offsets  []int // offsets[p] is the index of the first element of pages[p]
+	pageSize int
+	size     int
+}
+
+// NewPagedStorage returns an empty paged storage whose pages hold at most pageSize elements.
+// It panics if pageSize is < 2.
+func NewPagedStorage[T any](pageSize int) *PagedStorage[T] {
+	if pageSize < 2 {
+		panic("smallset.NewPagedStorage: page size must be >= 2")
+	}
+	return &PagedStorage[T]{pageSize: pageSize}
+}
+
+// NewPaged returns an initialized set for ordered types backed by a [PagedStorage], whose pages
+// hold at most pageSize elements. A good page size is in the hundreds. See [NewStored] for which
+// methods go straight to the storage. It panics if pageSize is < 2.
+func NewPaged[T cmp.Ordered](pageSize int) *Ordered[T] {
+	if pageSize < 2 {
+		panic("smallset.NewPaged: page size must be >= 2")
+	}
+	return NewStored[T](NewPagedStorage[T](pageSize))
+}
+
+// NewPagedCustom returns an initialized set with the provided compare function backed by
+// a [PagedStorage], whose pages hold at most pageSize elements.
+// It panics if cmp is nil or pageSize is < 2.
+func NewPagedCustom[T any](cmp func(a, b T) int, pageSize int) *Custom[T] {
+	if cmp == nil {
+		panic("smallset.NewPagedCustom: cmp cannot be nil")
+	}
+	if pageSize < 2 {
+		panic("smallset.NewPagedCustom: page size must be >= 2")
+	}
+	return NewStoredCustom(cmp, NewPagedStorage[T](pageSize))
+}
+
+func (s *PagedStorage[T]) Len() int {
+	return s.size
+}
+
+func (s *PagedStorage[T]) At(i int) T {
+	p, j := s.position(i)
+	return s.pages[p][j]
+}
+
+// Search performs a binary search over the last elements of the pages, followed by one
+// within the page. Operation is O(log(N)).
+func (s *PagedStorage[T]) Search(e T, cmp func(a, b T) int) (int, bool) {
+	p := sort.Search(len(s.pages), func(p int) bool {
+		page := s.pages[p]
+		return cmp(page[len(page)-1], e) >= 0
+	})
+
+	if p == len(s.pages) {
+		return s.size, false
+	}
+
+	i, found := slices.BinarySearchFunc(s.pages[p], e, cmp)
+	return s.offsets[p] + i, found
+}
+
+// Insert shifts the elements of a single page, splitting it if it becomes full.
+// Insertions at the end of a full last page start a new page instead, so that sets filled
+// in ascending order have full pages. Operation is O(pageSize + N/pageSize).
+func (s *PagedStorage[T]) Insert(i int, e T) {
+	if i == s.size {
+		last := len(s.pages) - 1
+		if last == -1 || len(s.pages[last]) >= s.pageSize {
+			s.pages = append(s.pages, s.newPage(e))
+			s.offsets = append(s.offsets, s.size)
+		} else {
+			s.pages[last] = append(s.pages[last], e)
+		}
+		s.size++
+		return
+	}
+
+	p, j := s.position(i)
+	s.pages[p] = slices.Insert(s.pages[p], j, e)
+	s.size++
+
+	if len(s.pages[p]) > s.pageSize {
+		s.split(p)
+	}
+	s.reindex(p + 1)
+}
+
+// DeleteRange drops the pages in the range and shifts the elements of the pages at its ends,
+// merging them with a neighbour if they are left underfull. Operation is O(pageSize + N/pageSize).
+func (s *PagedStorage[T]) DeleteRange(i, j int) {
+	if i >= j {
+		return
+	}
+
+	p1, i1 := s.position(i)
+	p2, i2 := s.position(j)
+	s.removeRange(p1, i1, p2, i2)
+
+	// merges can involve the page before p1
+	s.reindex(max(p1-1, 0))
+}
+
+func (s *PagedStorage[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		i := 0
+		for _, page := range s.pages {
+			for _, e := range page {
+				if !yield(i, e) {
+					return
+				}
+				i++
+			}
+		}
+	}
+}
+
+// Clone returns a deep copy of the storage.
+func (s *PagedStorage[T]) Clone() Storage[T] {
+	pages := make([][]T, len(s.pages))
+	for p, page := range s.pages {
+		pages[p] = make([]T, len(page), s.pageSize+1)
+		copy(pages[p], page)
+	}
+
+	return &PagedStorage[T]{
+		pages:    pages,
+		offsets:  slices.Clone(s.offsets),
+		pageSize: s.pageSize,
+		size:     s.size,
+	}
+}
+
+// position returns the index p of the page of the element at index i and its index within the page.
+// If i is the number of elements, p is the number of pages.
+func (s *PagedStorage[T]) position(i int) (p, j int) {
+	if i == s.size {
+		return len(s.pages), 0
+	}
+
+	p = sort.Search(len(s.offsets), func(p int) bool { return s.offsets[p] > i }) - 1
+	return p, i - s.offsets[p]
+}
+
+// reindex recomputes the offsets of the pages from the page p onwards,
+// assuming the ones of the previous pages are up to date.
+func (s *PagedStorage[T]) reindex(p int) {
+	p = min(p, len(s.pages))
+	s.offsets = s.offsets[:p]
+
+	offset := 0
+	if p > 0 {
+		offset = s.offsets[p-1] + len(s.pages[p-1])
+	}
+
+	for _, page := range s.pages[p:] {
+		s.offsets = append(s.offsets, offset)
+		offset += len(page)
+	}
+}
+
+// removeRange removes the elements from position (p1, i1) included to (p2, i2) excluded,
+// dropping the pages that become empty and merging the ones left underfull.
+func (s *PagedStorage[T]) removeRange(p1, i1, p2, i2 int) {
+	if p1 == len(s.pages) || (p1 == p2 && i1 >= i2) {
+		return
+	}
+
+	if p1 == p2 {
+		s.pages[p1] = slices.Delete(s.pages[p1], i1, i2)
+		s.size -= i2 - i1
+		s.dropEmpty(p1, p1+1)
+		s.rebalance(p1)
+		return
+	}
+
+	removed := len(s.pages[p1]) - i1
+	clear(s.pages[p1][i1:])
+	s.pages[p1] = s.pages[p1][:i1]
+
+	for p := p1 + 1; p < p2; p++ {
+		removed += len(s.pages[p])
+		s.pages[p] = nil
+	}
+
+	if p2 < len(s.pages) {
+		removed += i2
+		s.pages[p2] = slices.Delete(s.pages[p2], 0, i2)
+	}
+
+	s.size -= removed
+	s.dropEmpty(p1, min(p2+1, len(s.pages)))
+
+	// the remainders of the first and last page are now next to each other
+	s.rebalance(p1 + 1)
+	s.rebalance(p1)
+}
+
+// dropEmpty removes the empty pages with index in [start, end).
+func (s *PagedStorage[T]) dropEmpty(start, end int) {
+	kept := slices.DeleteFunc(s.pages[start:end], func(page []T) bool { return len(page) == 0 })
+	s.pages = slices.Delete(s.pages, start+len(kept), end)
+}
+
+// rebalance merges the page p with a neighbour if it has less than a quarter of pageSize elements.
+// It does nothing if p is out of bounds.
+func (s *PagedStorage[T]) rebalance(p int) {
+	if p >= len(s.pages) || len(s.pages) == 1 || len(s.pages[p]) >= s.pageSize/4 {
+		return
+	}
+
+	if p == len(s.pages)-1 {
+		p-- // the last page has no right neighbour
+	}
+	s.merge(p)
+}
+
+// merge merges the page p with the page p+1. If their elements don't fit in one page,
+// they are split evenly between the two instead.
+func (s *PagedStorage[T]) merge(p int) {
+	left, right := s.pages[p], s.pages[p+1]
+	total := len(left) + len(right)
+
+	if total <= s.pageSize {
+		s.pages[p] = append(left, right...)
+		s.pages = slices.Delete(s.pages, p+1, p+2)
+		return
+	}
+
+	half := total / 2
+	if len(left) < half {
+		moved := half - len(left)
+		s.pages[p] = append(left, right[:moved]...)
+		s.pages[p+1] = slices.Delete(right, 0, moved)
+		return
+	}
+
+	s.pages[p+1] = slices.Insert(right, 0, left[half:]...)
+	clear(left[half:])
+	s.pages[p] = left[:half]
+}
+
+// split divides the page p, which is full, into two halves.
+func (s *PagedStorage[T]) split(p int) {
+	page := s.pages[p]
+	half := len(page) / 2
+
+	right := make([]T, len(page)-half, s.pageSize+1)
+	copy(right, page[half:])
+	clear(page[half:])
+
+	s.pages[p] = page[:half]
+	s.pages = slices.Insert(s.pages, p+1, right)
+}
+
+func (s *PagedStorage[T]) newPage(e T) []T {
+	page := make([]T, 1, s.pageSize+1)
+	page[0] = e
+	return page
+}
@@ -0,0 +1,161 @@
+package smallset
+
+import (
+	"cmp"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+// pagesOf returns the pages of a set created with [NewPaged].
+func pagesOf[T cmp.Ordered](s *Ordered[T]) [][]T {
+	return s.Storage().(*PagedStorage[T]).pages
+}
+
+func TestPaged(t *testing.T) {
+	s := NewPaged[int](4)
+	for i := range 20 {
+		s.Add(i * 7 % 20)
+	}
+
+	pages := pagesOf(s)
+	if len(pages) < 5 {
+		t.Fatalf("expected at least 5 pages, got %d", len(pages))
+	}
+	for _, page := range pages {
+		if len(page) == 0 || len(page) > 4 {
+			t.Fatalf("invalid page size %d", len(page))
+		}
+	}
+
+	expected := make([]int, 20)
+	for i := range expected {
+		expected[i] = i
+	}
+	if items := s.Items(); !slices.Equal(items, expected) {
+		t.Errorf("Items mismatch.\nExpected: %v\nActual: %v", expected, items)
+	}
+}
+
+func TestPagedRemoveBetween(t *testing.T) {
+	cases := []struct {
+		min, max int
+		expected int
+	}{
+		{min: 0, max: 0, expected: 0},
+		{min: 3, max: 5, expected: 2},
+		{min: 2, max: 17, expected: 15},
+		{min: -5, max: 100, expected: 20},
+		{min: 19, max: 100, expected: 1},
+	}
+
+	for i, test := range cases {
+		t.Run(fmt.Sprintf("Case_%d", i), func(t *testing.T) {
+			s := NewPaged[int](4)
+			for i := range 20 {
+				s.Add(i)
+			}
+
+			if res := s.RemoveBetween(test.min, test.max); res != test.expected {
+				t.Errorf("RemoveBetween(%d, %d) expected %d, got %d", test.min, test.max, test.expected, res)
+			}
+
+			if s.Size() != 20-test.expected || len(s.Items()) != s.Size() {
+				t.Errorf("expected size %d, got %d", 20-test.expected, s.Size())
+			}
+
+			for _, page := range pagesOf(s) {
+				if len(page) == 0 {
+					t.Fatalf("empty page after RemoveBetween: %v", pagesOf(s))
+				}
+			}
+		})
+	}
+}
+
+func TestPagedMerge(t *testing.T) {
+	const pageSize = 8
+	s := NewPaged[int](pageSize)
+	expected := New[int](200)
+	for i := range 200 {
+		s.Add(i)
+		expected.Add(i)
+	}
+
+	rng := rand.New(rand.NewPCG(1, 2))
+	for step := range 500 {
+		e := rng.IntN(220)
+		switch rng.IntN(6) {
+		case 0:
+			end := e + rng.IntN(20)
+			s.RemoveBetween(e, end)
+			expected.RemoveBetween(e, end)
+		case 1:
+			if !s.IsEmpty() {
+				s.PopMin()
+				expected.PopMin()
+			}
+		case 2:
+			if !s.IsEmpty() {
+				s.PopMax()
+				expected.PopMax()
+			}
+		case 3:
+			s.Add(e)
+			expected.Add(e)
+		default:
+			s.Remove(e)
+			expected.Remove(e)
+		}
+
+		if !slices.Equal(s.Items(), expected.items) {
+			t.Fatalf("step %d: Items mismatch.\nExpected: %v\nActual: %v", step, expected.items, s.Items())
+		}
+		pages := pagesOf(s)
+		for _, page := range pages {
+			if len(page) > pageSize || (len(pages) > 1 && len(page) < pageSize/4) {
+				t.Fatalf("step %d: invalid page size %d in %v", step, len(page), pages)
+			}
+		}
+		for i := range expected.items {
+			if s.At(i) != expected.items[i] {
+				t.Fatalf("step %d: At(%d) expected %d, got %d", step, i, expected.items[i], s.At(i))
+			}
+		}
+	}
+
+	if max := s.Size()/(pageSize/4) + 1; len(pagesOf(s)) > max {
+		t.Errorf("expected at most %d pages for %d elements, got %d", max, s.Size(), len(pagesOf(s)))
+	}
+}
+
+func TestPagedSetAlgebra(t *testing.T) {
+	s := NewPaged[int](4)
+	for i := range 20 {
+		s.Add(i)
+	}
+	if pages := pagesOf(s); len(pages) != 5 {
+		t.Errorf("expected a sequential fill to have 5 full pages, got %v", pages)
+	}
+
+	other := NewRange(10, 30, 2)
+	if inter := s.Intersect(other); !slices.Equal(inter.Items(), []int{10, 12, 14, 16, 18}) {
+		t.Errorf("unexpected intersection %v", inter.Items())
+	}
+	if res := slices.Collect(s.BetweenValues(5, 8)); !slices.Equal(res, []int{5, 6, 7}) {
+		t.Errorf("unexpected BetweenValues %v", res)
+	}
+
+	clone := s.Clone()
+	clone.RemoveBetween(0, 10)
+	if s.Size() != 20 || clone.Size() != 10 || len(pagesOf(clone)) == 0 {
+		t.Errorf("expected the clone to have its own pages, got sizes %d and %d", s.Size(), clone.Size())
+	}
+
+	s.WithFingerprint()
+	s.ApplyDiff([]int{100}, []int{0, 1})
+	if s.Size() != 19 || s.Max() != 100 || s.Fingerprint() != From(s.Items()...).Fingerprint() {
+		t.Errorf("unexpected set after ApplyDiff %v", s.Items())
+	}
+}
@@ -0,0 +1,93 @@
+package smallset
+
+import (
+	"cmp"
+	"sync"
+)
+
+// MergeParallel is like [Merge], but it splits the sets into groups that are merged concurrently
+// by up to parallelism goroutines, before merging the partial results.
+// It's meant for merging dozens of sets, where the sequential [Merge] leaves cores idle.
+// It panics if parallelism is <= 0.
+func MergeParallel[T cmp.Ordered](parallelism int, sets ...*Ordered[T]) *Ordered[T] {
+	if parallelism <= 0 {
+		panic("smallset.MergeParallel: parallelism must be > 0")
+	}
+	// pinned up front, so that the goroutines only read the sets
+	defer pinAll(sets)()
+	return reduceParallel(parallelism, sets, Merge[T])
+}
+
+// IntersectParallel is like [Intersect], but it splits the sets into groups that are intersected
+// concurrently by up to parallelism goroutines, before intersecting the partial results.
+// It's meant for intersecting dozens of sets, where the sequential [Intersect] leaves cores idle.
+// It sorts the sets slice in place. It panics if parallelism is <= 0.
+func IntersectParallel[T cmp.Ordered](parallelism int, sets ...*Ordered[T]) *Ordered[T] {
+	if parallelism <= 0 {
+		panic("smallset.IntersectParallel: parallelism must be > 0")
+	}
+	// pinned up front, so that the goroutines only read the sets
+	defer pinAll(sets)()
+	return reduceParallel(parallelism, sets, Intersect[T])
+}
+
+// MergeParallelCustom is like [MergeCustom], but it splits the sets into groups that are merged
+// concurrently by up to parallelism goroutines, before merging the partial results.
+// It panics if compare is nil or parallelism is <= 0.
+func MergeParallelCustom[T any](compare func(a, b T) int, parallelism int, sets ...*Custom[T]) *Custom[T] {
+	if compare == nil {
+		panic("smallset.MergeParallelCustom: cmp cannot be nil")
+	}
+	if parallelism <= 0 {
+		panic("smallset.MergeParallelCustom: parallelism must be > 0")
+	}
+	// pinned up front, so that the goroutines only read the sets
+	defer pinAllCustom(sets)()
+	return reduceParallel(parallelism, sets, func(sets ...*Custom[T]) *Custom[T] {
+		return MergeCustom(compare, sets...)
+	})
+}
+
+// IntersectParallelCustom is like [IntersectCustom], but it splits the sets into groups that are
+// intersected concurrently by up to parallelism goroutines, before intersecting the partial results.
+// It sorts the sets slice in place. It panics if compare is nil or parallelism is <= 0.
+func IntersectParallelCustom[T any](compare func(a, b T) int, parallelism int, sets ...*Custom[T]) *Custom[T] {
+	if compare == nil {
+		panic("smallset.IntersectParallelCustom: cmp cannot be nil")
+	}
+	if parallelism <= 0 {
+		panic("smallset.IntersectParallelCustom: parallelism must be > 0")
+	}
+	// pinned up front, so that the goroutines only read the sets
+	defer pinAllCustom(sets)()
+	return reduceParallel(parallelism, sets, func(sets ...*Custom[T]) *Custom[T] {
+		return IntersectCustom(compare, sets...)
+	})
+}
+
+// reduceParallel splits the sets into at most parallelism non-empty groups of similar size,
+// reduces each group in its own goroutine, and then reduces the partial results.
+// The reduce function must be associative, like a merge or an intersection.
+func reduceParallel[S any](parallelism int, sets []S, reduce func(sets ...S) S) S {
+	if parallelism == 1 || len(sets) <= 2 {
+		return reduce(sets...)
+	}
+
+	size := (len(sets) + parallelism - 1) / parallelism
+	size = max(size, 2)
+	groups := (len(sets) + size - 1) / size
+	partials := make([]S, groups)
+
+	var wg sync.WaitGroup
+	wg.Add(groups)
+	for g := range groups {
+		group := sets[g*size : min((g+1)*size, len(sets))]
+		go func() {
+			defer wg.Done()
+			partials[g] = reduce(group...)
+		}()
+	}
+
+	wg.Wait()
+	return reduce(partials...)
+}
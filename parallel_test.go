@@ -0,0 +1,80 @@
+package smallset
+
+import (
+	"cmp"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func randomSets(rng *rand.Rand, n, size, max int) []*Ordered[int] {
+	sets := make([]*Ordered[int], n)
+	for i := range sets {
+		sets[i] = New[int](size)
+		for range size {
+			sets[i].Add(rng.IntN(max))
+		}
+	}
+	return sets
+}
+
+func TestParallel(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	cases := []struct {
+		sets        int
+		parallelism int
+	}{
+		{sets: 0, parallelism: 4},
+		{sets: 1, parallelism: 4},
+		{sets: 3, parallelism: 1},
+		{sets: 9, parallelism: 4},
+		{sets: 30, parallelism: 8},
+		{sets: 30, parallelism: 100},
+	}
+
+	for i, test := range cases {
+		t.Run(fmt.Sprintf("Case_%d", i), func(t *testing.T) {
+			sets := randomSets(rng, test.sets, 200, 300)
+
+			if merged, expected := MergeParallel(test.parallelism, sets...), Merge(sets...); !merged.IsEqual(expected) {
+				t.Errorf("MergeParallel mismatch.\nExpected: %v\nActual: %v", expected.items, merged.items)
+			}
+
+			if inter, expected := IntersectParallel(test.parallelism, sets...), Intersect(sets...); !inter.IsEqual(expected) {
+				t.Errorf("IntersectParallel mismatch.\nExpected: %v\nActual: %v", expected.items, inter.items)
+			}
+
+			custom := make([]*Custom[int], len(sets))
+			for i, s := range sets {
+				custom[i] = CustomFrom(cmp.Compare[int], s.items...)
+			}
+
+			if merged := MergeParallelCustom(cmp.Compare[int], test.parallelism, custom...); !slices.Equal(merged.items, Merge(sets...).items) {
+				t.Errorf("MergeParallelCustom mismatch")
+			}
+
+			if inter := IntersectParallelCustom(cmp.Compare[int], test.parallelism, custom...); !slices.Equal(inter.items, Intersect(sets...).items) {
+				t.Errorf("IntersectParallelCustom mismatch")
+			}
+		})
+	}
+}
+
+func BenchmarkMergeParallel(b *testing.B) {
+	sets := randomSets(rand.New(rand.NewPCG(1, 2)), 64, 1000, 100_000)
+
+	b.Run("sequential", func(b *testing.B) {
+		b.ResetTimer()
+		for range b.N {
+			Merge(sets...)
+		}
+	})
+
+	b.Run("parallel", func(b *testing.B) {
+		b.ResetTimer()
+		for range b.N {
+			MergeParallel(8, sets...)
+		}
+	})
+}
@@ -0,0 +1,284 @@
+package smallset
+
+import (
+	"bufio"
+	"cmp"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"math"
+	"reflect"
+)
+
+// The persistence format written by Save and read by Load is:
+//   - magic: the 4 bytes "SSET"
+//   - version: 1 byte
+//   - kind: 1 byte, the [reflect.Kind] of the elements of [Ordered] sets, or 0 for [Custom] sets
+//   - count: uvarint, the number of elements
+//   - elements: the encoding of the elements in ascending order
+//   - checksum: 4 bytes, the big-endian CRC-32 (IEEE) of all the previous bytes
+//
+// [Ordered] elements are encoded as described in appendOrdered, while [Custom] elements
+// are encoded by the user provided function, prefixed with their uvarint length.
+const (
+	persistMagic   = "SSET"
+	persistVersion = 1
+)
+
+var (
+	ErrInvalidFormat      = errors.New("smallset: invalid format")
+	ErrUnsupportedVersion = errors.New("smallset: unsupported version")
+	ErrChecksumMismatch   = errors.New("smallset: checksum mismatch")
+)
+
+// Save writes the set to w in a versioned binary format with a checksum,
+// that can be read back with [Ordered.Load].
+func (s *Ordered[T]) Save(w io.Writer) error {
+	defer s.pin()()
+	buf := appendHeader(nil, reflectKind[T](), len(s.items))
+	for _, e := range s.items {
+		buf = appendOrdered(buf, e)
+	}
+
+	buf = binary.BigEndian.AppendUint32(buf, crc32.ChecksumIEEE(buf))
+	_, err := w.Write(buf)
+	return err
+}
+
+// Load replaces the elements of the set with the ones read from r, which must have
+// been written by [Ordered.Save] for a set of the same element kind.
+// If r is an [io.ByteReader], like a *bufio.Reader or a *bytes.Buffer, Load reads exactly the bytes
+// of the set, so that several sets can be read one after the other. Otherwise it may read past them.
+// If an error is returned, the set is left unchanged.
+func (s *Ordered[T]) Load(r io.Reader) error {
+	cr := newChecksumReader(r)
+	count, err := readHeader(cr, reflectKind[T]())
+	if err != nil {
+		return err
+	}
+
+	items := make([]T, 0, min(count, 1<<16))
+	for range count {
+		e, err := decodeOrdered[T](cr)
+		if err != nil {
+			return err
+		}
+
+		if len(items) > 0 && !cmp.Less(items[len(items)-1], e) {
+			return fmt.Errorf("%w: elements are not sorted or unique", ErrInvalidFormat)
+		}
+		items = append(items, e)
+	}
+
+	if err := cr.verify(); err != nil {
+		return err
+	}
+
+	s.reset(items)
+	return nil
+}
+
+// Save writes the set to w in a versioned binary format with a checksum,
+// that can be read back with [Custom.Load].
+// The encode function must append the encoding of e to dst and return the extended slice.
+func (s *Custom[T]) Save(w io.Writer, encode func(dst []byte, e T) []byte) error {
+	defer s.pin()()
+	buf := appendHeader(nil, reflect.Invalid, len(s.items))
+
+	var enc []byte
+	for _, e := range s.items {
+		enc = encode(enc[:0], e)
+		buf = binary.AppendUvarint(buf, uint64(len(enc)))
+		buf = append(buf, enc...)
+	}
+
+	buf = binary.BigEndian.AppendUint32(buf, crc32.ChecksumIEEE(buf))
+	_, err := w.Write(buf)
+	return err
+}
+
+// Load replaces the elements of the set with the ones read from r, which must have
+// been written by [Custom.Save] with an encode function compatible with decode.
+// If r is an [io.ByteReader], like a *bufio.Reader or a *bytes.Buffer, Load reads exactly the bytes
+// of the set, so that several sets can be read one after the other. Otherwise it may read past them.
+// If an error is returned, the set is left unchanged.
+func (s *Custom[T]) Load(r io.Reader, decode func(data []byte) (T, error)) error {
+	cr := newChecksumReader(r)
+	count, err := readHeader(cr, reflect.Invalid)
+	if err != nil {
+		return err
+	}
+
+	items := make([]T, 0, min(count, 1<<16))
+	for range count {
+		data, err := readBytes(cr)
+		if err != nil {
+			return err
+		}
+
+		e, err := decode(data)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidFormat, err)
+		}
+
+		if len(items) > 0 && !s.cmp.less(items[len(items)-1], e) {
+			return fmt.Errorf("%w: elements are not sorted or unique", ErrInvalidFormat)
+		}
+		items = append(items, e)
+	}
+
+	if err := cr.verify(); err != nil {
+		return err
+	}
+
+	s.reset(items)
+	return nil
+}
+
+// reflectKind returns the [reflect.Kind] of T.
+func reflectKind[T any]() reflect.Kind {
+	return reflect.TypeFor[T]().Kind()
+}
+
+func appendHeader(dst []byte, kind reflect.Kind, count int) []byte {
+	dst = append(dst, persistMagic...)
+	dst = append(dst, persistVersion, byte(kind))
+	return binary.AppendUvarint(dst, uint64(count))
+}
+
+// readHeader reads the header, validating it against the expected kind, and returns the count.
+func readHeader(r *checksumReader, kind reflect.Kind) (int, error) {
+	var header [len(persistMagic) + 2]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrInvalidFormat, err)
+	}
+
+	if string(header[:len(persistMagic)]) != persistMagic {
+		return 0, fmt.Errorf("%w: bad magic number", ErrInvalidFormat)
+	}
+	if v := header[len(persistMagic)]; v != persistVersion {
+		return 0, fmt.Errorf("%w: %d", ErrUnsupportedVersion, v)
+	}
+	if k := reflect.Kind(header[len(persistMagic)+1]); k != kind {
+		return 0, fmt.Errorf("%w: expected elements of kind %s, got %s", ErrInvalidFormat, kind, k)
+	}
+
+	count, err := binary.ReadUvarint(r)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrInvalidFormat, err)
+	}
+	if count > math.MaxInt32 {
+		return 0, fmt.Errorf("%w: count is too big: %d", ErrInvalidFormat, count)
+	}
+	return int(count), nil
+}
+
+// readBytes reads a byte slice prefixed with its uvarint length.
+func readBytes(r *checksumReader) ([]byte, error) {
+	n, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidFormat, err)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r, int64(min(n, math.MaxInt64))))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidFormat, err)
+	}
+	if uint64(len(data)) != n {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidFormat, io.ErrUnexpectedEOF)
+	}
+	return data, nil
+}
+
+// decodeOrdered reads an element encoded by appendOrdered.
+func decodeOrdered[T cmp.Ordered](r *checksumReader) (T, error) {
+	var e T
+	v := reflect.ValueOf(&e).Elem()
+
+	if v.Kind() == reflect.String {
+		data, err := readBytes(r)
+		if err != nil {
+			return e, err
+		}
+		v.SetString(string(data))
+		return e, nil
+	}
+
+	var buf [8]byte
+	if _, err := io.ReadFull(r, buf[:]); err != nil {
+		return e, fmt.Errorf("%w: %w", ErrInvalidFormat, err)
+	}
+	x := binary.BigEndian.Uint64(buf[:])
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if v.OverflowInt(int64(x)) {
+			return e, fmt.Errorf("%w: %d overflows %T", ErrInvalidFormat, int64(x), e)
+		}
+		v.SetInt(int64(x))
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if v.OverflowUint(x) {
+			return e, fmt.Errorf("%w: %d overflows %T", ErrInvalidFormat, x, e)
+		}
+		v.SetUint(x)
+
+	case reflect.Float32, reflect.Float64:
+		f := math.Float64frombits(x)
+		if v.OverflowFloat(f) {
+			return e, fmt.Errorf("%w: %v overflows %T", ErrInvalidFormat, f, e)
+		}
+		v.SetFloat(f)
+	}
+	return e, nil
+}
+
+// checksumReader is a reader that computes the checksum of the bytes it reads.
+type checksumReader struct {
+	r   byteReader
+	crc hash.Hash32
+}
+
+type byteReader interface {
+	io.Reader
+	io.ByteReader
+}
+
+// newChecksumReader returns a checksum reader over r. It reads from r directly if it's an
+// [io.ByteReader], so that it doesn't consume the bytes following the record, and buffers it otherwise.
+func newChecksumReader(r io.Reader) *checksumReader {
+	br, ok := r.(byteReader)
+	if !ok {
+		br = bufio.NewReader(r)
+	}
+	return &checksumReader{r: br, crc: crc32.NewIEEE()}
+}
+
+func (c *checksumReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.crc.Write(p[:n])
+	return n, err
+}
+
+func (c *checksumReader) ReadByte() (byte, error) {
+	b, err := c.r.ReadByte()
+	if err == nil {
+		c.crc.Write([]byte{b})
+	}
+	return b, err
+}
+
+// verify reads the checksum and compares it with the one of the bytes read so far.
+func (c *checksumReader) verify() error {
+	var buf [4]byte
+	if _, err := io.ReadFull(c.r, buf[:]); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidFormat, err)
+	}
+	if binary.BigEndian.Uint32(buf[:]) != c.crc.Sum32() {
+		return ErrChecksumMismatch
+	}
+	return nil
+}
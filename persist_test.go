@@ -0,0 +1,168 @@
+package smallset
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestSaveLoad(t *testing.T) {
+	t.Run("int", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := From(-5, 0, 3, 1<<40).Save(&buf); err != nil {
+			t.Fatal(err)
+		}
+
+		s := From(42)
+		if err := s.Load(&buf); err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(s.items, []int{-5, 0, 3, 1 << 40}) {
+			t.Errorf("Expected %v, got %v", []int{-5, 0, 3, 1 << 40}, s.items)
+		}
+	})
+
+	t.Run("string", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := From("b", "", "a").Save(&buf); err != nil {
+			t.Fatal(err)
+		}
+
+		s := New[string](1)
+		if err := s.Load(&buf); err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(s.items, []string{"", "a", "b"}) {
+			t.Errorf("Expected %v, got %v", []string{"", "a", "b"}, s.items)
+		}
+	})
+
+	t.Run("float32", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := From[float32](1.5, -2.25).Save(&buf); err != nil {
+			t.Fatal(err)
+		}
+
+		s := New[float32](1)
+		if err := s.Load(&buf); err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(s.items, []float32{-2.25, 1.5}) {
+			t.Errorf("Expected %v, got %v", []float32{-2.25, 1.5}, s.items)
+		}
+	})
+}
+
+func TestLoadConsecutive(t *testing.T) {
+	var buf bytes.Buffer
+	if err := From(1, 2, 3).Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if err := From(4, 5).Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	buf.WriteString("trailer")
+
+	first, second := New[int](1), New[int](1)
+	if err := first.Load(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if err := second.Load(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	if !slices.Equal(first.items, []int{1, 2, 3}) || !slices.Equal(second.items, []int{4, 5}) {
+		t.Errorf("Expected [1 2 3] and [4 5], got %v and %v", first.items, second.items)
+	}
+	if buf.String() != "trailer" {
+		t.Errorf("expected the trailer to be left unread, got %q", buf.String())
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	var valid bytes.Buffer
+	From(1, 2, 3).Save(&valid)
+	data := valid.Bytes()
+
+	corrupt := func(i int) []byte {
+		c := bytes.Clone(data)
+		c[i] ^= 0xff
+		return c
+	}
+
+	var unsorted []byte
+	unsorted = appendHeader(unsorted, reflectKind[int](), 2)
+	unsorted = appendOrdered(unsorted, 2)
+	unsorted = appendOrdered(unsorted, 1)
+
+	cases := []struct {
+		data     []byte
+		expected error
+	}{
+		{data: nil, expected: ErrInvalidFormat},
+		{data: corrupt(0), expected: ErrInvalidFormat},
+		{data: corrupt(4), expected: ErrUnsupportedVersion},
+		{data: corrupt(5), expected: ErrInvalidFormat},
+		{data: corrupt(len(data) - 2), expected: ErrChecksumMismatch},
+		{data: corrupt(len(data) - 5), expected: ErrChecksumMismatch},
+		{data: data[:len(data)-6], expected: ErrInvalidFormat},
+		{data: unsorted, expected: ErrInvalidFormat},
+	}
+
+	for i, test := range cases {
+		t.Run(fmt.Sprintf("Case_%d", i), func(t *testing.T) {
+			s := From(69)
+			err := s.Load(bytes.NewReader(test.data))
+			if !errors.Is(err, test.expected) {
+				t.Errorf("expected error %v, got %v", test.expected, err)
+			}
+			if !slices.Equal(s.items, []int{69}) {
+				t.Errorf("expected the set to be unchanged, got %v", s.items)
+			}
+		})
+	}
+
+	t.Run("wrong kind", func(t *testing.T) {
+		s := New[string](1)
+		if err := s.Load(bytes.NewReader(data)); !errors.Is(err, ErrInvalidFormat) {
+			t.Errorf("expected error %v, got %v", ErrInvalidFormat, err)
+		}
+	})
+}
+
+func TestCustomSaveLoad(t *testing.T) {
+	encode := func(dst []byte, p Person) []byte {
+		dst = binary.AppendVarint(dst, int64(p.ID))
+		return append(dst, p.Name...)
+	}
+	decode := func(data []byte) (Person, error) {
+		id, n := binary.Varint(data)
+		if n <= 0 {
+			return Person{}, errors.New("invalid ID")
+		}
+		return Person{ID: int(id), Name: string(data[n:])}, nil
+	}
+
+	var buf bytes.Buffer
+	if err := CustomFrom(PersonCmp, people2...).Save(&buf, encode); err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewCustom(PersonCmp, 1).WithFingerprint(func(p Person) uint64 { return uint64(p.ID) })
+	if err := s.Load(&buf, decode); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Person{{ID: 20, Name: "Delta"}, {ID: 30, Name: "Gamma"}, {ID: 40, Name: "Beta"}, {ID: 50, Name: "Alpha"}}
+	if !slices.Equal(s.items, expected) {
+		t.Errorf("Expected %v, got %v", expected, s.items)
+	}
+
+	fingerprint := CustomFrom(PersonCmp, expected...).WithFingerprint(func(p Person) uint64 { return uint64(p.ID) }).Fingerprint()
+	if s.Fingerprint() != fingerprint {
+		t.Errorf("expected the fingerprint to be updated after Load")
+	}
+}
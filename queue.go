@@ -0,0 +1,63 @@
+package smallset
+
+// PriorityQueue is a min-max priority queue view over an [Ordered] or [Custom] set,
+// obtained by calling their Queue method.
+//
+// The sorted slice of the set already supports the queue operations efficiently,
+// so there is no need to keep the same data in a second data structure.
+// Like the underlying set, elements are unique. Not safe for concurrent use.
+type PriorityQueue[T any] struct {
+	set queueable[T]
+}
+
+// queueable is the subset of the set methods used by the [PriorityQueue].
+type queueable[T any] interface {
+	Add(e T) bool
+	PopMin() T
+	PopMax() T
+	MinK(k int) []T
+	Size() int
+}
+
+// Size returns the number of elements in the queue.
+func (q *PriorityQueue[T]) Size() int {
+	return q.set.Size()
+}
+
+// IsEmpty returns whether the queue has no elements.
+func (q *PriorityQueue[T]) IsEmpty() bool {
+	return q.set.Size() == 0
+}
+
+// Push an element and returns whether it was added (true), or was already present (false).
+// O(N) complexity.
+func (q *PriorityQueue[T]) Push(e T) bool {
+	return q.set.Add(e)
+}
+
+// PopMin removes and returns the smallest element in the queue. O(N) complexity.
+// It panics if the queue is empty.
+func (q *PriorityQueue[T]) PopMin() T {
+	if q.IsEmpty() {
+		panic("smallset.PriorityQueue.PopMin: queue is empty")
+	}
+	return q.set.PopMin()
+}
+
+// PopMax removes and returns the biggest element in the queue. O(1) complexity.
+// It panics if the queue is empty.
+func (q *PriorityQueue[T]) PopMax() T {
+	if q.IsEmpty() {
+		panic("smallset.PriorityQueue.PopMax: queue is empty")
+	}
+	return q.set.PopMax()
+}
+
+// PeekN returns the n smallest elements in the queue in ascending order, without removing them.
+// It panics if n is negative. If n is bigger than the queue size, it returns all the elements.
+func (q *PriorityQueue[T]) PeekN(n int) []T {
+	if n < 0 {
+		panic("smallset.PriorityQueue.PeekN: n must be positive")
+	}
+	return q.set.MinK(n)
+}
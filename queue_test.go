@@ -0,0 +1,54 @@
+package smallset
+
+import (
+	"cmp"
+	"slices"
+	"testing"
+)
+
+func TestPriorityQueue(t *testing.T) {
+	queues := map[string]*PriorityQueue[int]{
+		"ordered": New[int](10).Queue(),
+		"custom":  NewCustom(cmp.Compare[int], 10).Queue(),
+	}
+
+	for name, q := range queues {
+		t.Run(name, func(t *testing.T) {
+			for _, e := range []int{7, 3, 9, 1, 3, 5} {
+				q.Push(e)
+			}
+
+			if size := q.Size(); size != 5 {
+				t.Fatalf("expected size 5, got %d", size)
+			}
+
+			if peek := q.PeekN(2); !slices.Equal(peek, []int{1, 3}) {
+				t.Errorf("PeekN(2) expected %v, got %v", []int{1, 3}, peek)
+			}
+
+			if min := q.PopMin(); min != 1 {
+				t.Errorf("PopMin expected 1, got %d", min)
+			}
+
+			if max := q.PopMax(); max != 9 {
+				t.Errorf("PopMax expected 9, got %d", max)
+			}
+
+			if peek := q.PeekN(10); !slices.Equal(peek, []int{3, 5, 7}) {
+				t.Errorf("PeekN(10) expected %v, got %v", []int{3, 5, 7}, peek)
+			}
+		})
+	}
+}
+
+func TestPriorityQueueSharesSet(t *testing.T) {
+	s := From(1, 2, 3)
+	q := s.Queue()
+
+	q.PopMin()
+	q.Push(10)
+
+	if !slices.Equal(s.items, []int{2, 3, 10}) {
+		t.Errorf("expected set to reflect queue changes, got %v", s.items)
+	}
+}
@@ -0,0 +1,38 @@
+package smallset
+
+import (
+	"slices"
+)
+
+// RoaringBitmap is the subset of the methods of a 32-bit roaring bitmap used for the
+// conversions with [Ordered] sets of uint32.
+// It's satisfied by *roaring.Bitmap of github.com/RoaringBitmap/roaring, without requiring
+// this package to depend on it.
+type RoaringBitmap interface {
+	AddMany(values []uint32)
+	ToArray() []uint32
+}
+
+// ToRoaring adds all the elements of the set to the bitmap, in a single sorted batch.
+// It can be used to merge many small sets into a large bitmap.
+func ToRoaring(s *Ordered[uint32], b RoaringBitmap) {
+	if s.IsEmpty() {
+		return
+	}
+	b.AddMany(s.Items())
+}
+
+// FromRoaring returns an initialized set that contains the elements of the bitmap.
+// The sorted array produced by the bitmap is used directly as the storage of the set.
+func FromRoaring(b RoaringBitmap) *Ordered[uint32] {
+	items := b.ToArray()
+	if len(items) == 0 {
+		return New[uint32](defaultCapacity)
+	}
+
+	if !slices.IsSorted(items) {
+		slices.Sort(items)
+	}
+	items = slices.Compact(items)
+	return &Ordered[uint32]{items: items}
+}
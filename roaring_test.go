@@ -0,0 +1,41 @@
+package smallset
+
+import (
+	"slices"
+	"testing"
+)
+
+// fakeBitmap is a minimal bitmap that satisfies the [RoaringBitmap] interface.
+type fakeBitmap map[uint32]struct{}
+
+func (b fakeBitmap) AddMany(values []uint32) {
+	for _, v := range values {
+		b[v] = struct{}{}
+	}
+}
+
+func (b fakeBitmap) ToArray() []uint32 {
+	values := make([]uint32, 0, len(b))
+	for v := range b {
+		values = append(values, v)
+	}
+	slices.Sort(values)
+	return values
+}
+
+func TestRoaring(t *testing.T) {
+	b := fakeBitmap{}
+	ToRoaring(From[uint32](5, 1, 3), b)
+	ToRoaring(From[uint32](3, 100), b)
+	ToRoaring(New[uint32](1), b)
+
+	s := FromRoaring(b)
+	expected := []uint32{1, 3, 5, 100}
+	if !slices.Equal(s.items, expected) {
+		t.Errorf("Expected %v, got %v", expected, s.items)
+	}
+
+	if empty := FromRoaring(fakeBitmap{}); !empty.IsEmpty() {
+		t.Errorf("expected empty set, got %v", empty.items)
+	}
+}
@@ -0,0 +1,50 @@
+package smallset
+
+import (
+	"cmp"
+	"math"
+	"math/rand/v2"
+	"slices"
+)
+
+// sampleWeighted returns k elements of items sampled without replacement, with probabilities
+// proportional to their weights, using the A-Res algorithm of Efraimidis and Spirakis.
+// Elements with a non-positive weight are never sampled. The result preserves the order of items.
+func sampleWeighted[T any](items []T, k int, weight func(T) float64, rng *rand.Rand) []T {
+	random := rand.Float64
+	if rng != nil {
+		random = rng.Float64
+	}
+
+	type candidate struct {
+		index int
+		key   float64
+	}
+
+	candidates := make([]candidate, 0, len(items))
+	for i, e := range items {
+		w := weight(e)
+		if w <= 0 || math.IsNaN(w) {
+			continue
+		}
+
+		// key = u^(1/w) in logarithmic form. The biggest keys win.
+		u := 1 - random() // in (0, 1]
+		candidates = append(candidates, candidate{index: i, key: math.Log(u) / w})
+	}
+
+	slices.SortFunc(candidates, func(a, b candidate) int {
+		return cmp.Compare(b.key, a.key)
+	})
+
+	candidates = candidates[:min(k, len(candidates))]
+	slices.SortFunc(candidates, func(a, b candidate) int {
+		return cmp.Compare(a.index, b.index)
+	})
+
+	sample := make([]T, len(candidates))
+	for i, c := range candidates {
+		sample[i] = items[c.index]
+	}
+	return sample
+}
@@ -0,0 +1,60 @@
+package smallset
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestSampleWeighted(t *testing.T) {
+	s := From(1, 2, 3, 4, 5)
+	rng := rand.New(rand.NewPCG(1, 2))
+	weight := func(e int) float64 { return float64(e % 3) } // 3 has weight 0
+
+	cases := []struct {
+		k    int
+		size int
+	}{
+		{k: 0, size: 0},
+		{k: 2, size: 2},
+		{k: 4, size: 4},
+		{k: 10, size: 4},
+	}
+
+	for i, test := range cases {
+		t.Run(fmt.Sprintf("Case_%d", i), func(t *testing.T) {
+			sample := s.SampleWeighted(test.k, weight, rng)
+			if len(sample) != test.size {
+				t.Fatalf("expected %d elements, got %v", test.size, sample)
+			}
+			if slices.Contains(sample, 3) {
+				t.Errorf("element with zero weight was sampled: %v", sample)
+			}
+			if !slices.IsSorted(sample) || len(slices.Compact(slices.Clone(sample))) != len(sample) {
+				t.Errorf("expected sorted and unique elements, got %v", sample)
+			}
+		})
+	}
+}
+
+func TestCustomSampleWeighted(t *testing.T) {
+	s := CustomFrom(PersonCmp, people1...)
+	rng := rand.New(rand.NewPCG(1, 2))
+	weight := func(p Person) float64 {
+		if p.ID == 4 {
+			return 100
+		}
+		return 1
+	}
+
+	counts := make(map[int]int)
+	for range 1000 {
+		sample := s.SampleWeighted(1, weight, rng)
+		counts[sample[0].ID]++
+	}
+
+	if counts[4] < 900 {
+		t.Errorf("expected the heaviest element to be sampled most of the times, got %v", counts)
+	}
+}
@@ -0,0 +1,154 @@
+package smallset
+
+import (
+	"cmp"
+	"iter"
+	"math"
+	"sort"
+)
+
+// ScoredMember is a member of a [Scored] set with its score.
+type ScoredMember[T cmp.Ordered] struct {
+	Member T
+	Score  float64
+}
+
+// compareScored orders members by score, breaking ties by member like Redis sorted sets do.
+func compareScored[T cmp.Ordered](a, b ScoredMember[T]) int {
+	if c := cmp.Compare(a.Score, b.Score); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.Member, b.Member)
+}
+
+// Scored is a set of unique members, each with a score, ordered by score and then by member,
+// like a Redis sorted set. It's a [Custom] set of [ScoredMember] plus an index of the scores
+// by member, meant for in-process leaderboards.
+// Scores can't be NaN. Not safe for concurrent use.
+type Scored[T cmp.Ordered] struct {
+	set    *Custom[ScoredMember[T]]
+	scores map[T]float64
+}
+
+// NewScored returns an initialized scored set with the provided capacity.
+// It panics if the capacity is <= 0.
+func NewScored[T cmp.Ordered](capacity int) *Scored[T] {
+	if capacity <= 0 {
+		panic("smallset.NewScored: capacity must be > 0")
+	}
+
+	return &Scored[T]{
+		set:    NewCustom(compareScored[T], capacity),
+		scores: make(map[T]float64, capacity),
+	}
+}
+
+// Size returns the number of members in the set.
+func (s *Scored[T]) Size() int {
+	return len(s.scores)
+}
+
+// IsEmpty returns whether the set has no members.
+func (s *Scored[T]) IsEmpty() bool {
+	return len(s.scores) == 0
+}
+
+// Score returns the score of the member, and whether the member is in the set.
+func (s *Scored[T]) Score(member T) (float64, bool) {
+	score, ok := s.scores[member]
+	return score, ok
+}
+
+// Add a member with its score and returns whether it was added (true), or was already present
+// and its score was updated (false). O(N) complexity. It panics if the score is NaN.
+func (s *Scored[T]) Add(member T, score float64) bool {
+	if math.IsNaN(score) {
+		panic("smallset.Scored.Add: score cannot be NaN")
+	}
+
+	old, found := s.scores[member]
+	if found {
+		if old == score {
+			return false
+		}
+		s.set.Remove(ScoredMember[T]{Member: member, Score: old})
+	}
+
+	s.set.Add(ScoredMember[T]{Member: member, Score: score})
+	s.scores[member] = score
+	return !found
+}
+
+// IncrScore adds delta to the score of the member, adding the member with a score of delta
+// if it's not present, and returns the new score. O(N) complexity.
+// It panics if the new score is NaN.
+func (s *Scored[T]) IncrScore(member T, delta float64) float64 {
+	score := s.scores[member] + delta
+	if math.IsNaN(score) {
+		panic("smallset.Scored.IncrScore: score cannot be NaN")
+	}
+
+	s.Add(member, score)
+	return score
+}
+
+// Remove a member if present, and returns whether is was removed (true), or was never present (false).
+// O(N) complexity.
+func (s *Scored[T]) Remove(member T) bool {
+	score, found := s.scores[member]
+	if !found {
+		return false
+	}
+
+	s.set.Remove(ScoredMember[T]{Member: member, Score: score})
+	delete(s.scores, member)
+	return true
+}
+
+// RankOf returns the 0-based rank of the member in ascending order of score,
+// and whether the member is in the set. O(log(N)) complexity.
+func (s *Scored[T]) RankOf(member T) (int, bool) {
+	score, found := s.scores[member]
+	if !found {
+		return 0, false
+	}
+	return s.set.Find(ScoredMember[T]{Member: member, Score: score})
+}
+
+// At returns the member with the provided rank in ascending order of score.
+// It panics if the rank is out of range.
+func (s *Scored[T]) At(rank int) ScoredMember[T] {
+	if rank < 0 || rank >= len(s.set.items) {
+		panic("smallset.Scored.At: rank out of range")
+	}
+	return s.set.items[rank]
+}
+
+// ByScoreRange iterates the members whose score is between lo and hi (both inclusive),
+// in ascending order of score, together with their rank. O(log(N)) to find the first member.
+// Panics if hi < lo.
+func (s *Scored[T]) ByScoreRange(lo, hi float64) iter.Seq2[int, ScoredMember[T]] {
+	if hi < lo {
+		panic("smallset.Scored.ByScoreRange: invalid range (hi < lo)")
+	}
+	start := sort.Search(len(s.set.items), func(i int) bool { return s.set.items[i].Score >= lo })
+
+	return func(yield func(int, ScoredMember[T]) bool) {
+		for i := start; i < len(s.set.items) && s.set.items[i].Score <= hi; i++ {
+			if !yield(i, s.set.items[i]) {
+				return
+			}
+		}
+	}
+}
+
+// Ascend returns an iterator over the members with their rank, in ascending order of score.
+func (s *Scored[T]) Ascend() iter.Seq2[int, ScoredMember[T]] {
+	return s.set.Ascend()
+}
+
+// Descend returns an iterator over the members with their rank, in descending order of score,
+// which lists the top of a leaderboard first.
+func (s *Scored[T]) Descend() iter.Seq2[int, ScoredMember[T]] {
+	return s.set.Descend()
+}
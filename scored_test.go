@@ -0,0 +1,134 @@
+package smallset
+
+import (
+	"cmp"
+	"fmt"
+	"iter"
+	"math"
+	"slices"
+	"testing"
+)
+
+func leaderboard() *Scored[string] {
+	s := NewScored[string](10)
+	s.Add("alice", 30)
+	s.Add("bob", 10)
+	s.Add("carol", 20)
+	s.Add("dave", 20)
+	return s
+}
+
+func members[T cmp.Ordered](seq iter.Seq2[int, ScoredMember[T]]) []T {
+	var members []T
+	for _, m := range seq {
+		members = append(members, m.Member)
+	}
+	return members
+}
+
+func TestScoredAdd(t *testing.T) {
+	s := leaderboard()
+	if s.Add("bob", 40) {
+		t.Error("expected bob to be already present")
+	}
+	if !s.Add("erin", 0) {
+		t.Error("expected erin to be added")
+	}
+
+	expected := []string{"erin", "carol", "dave", "alice", "bob"}
+	if got := members(s.Ascend()); !slices.Equal(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+	if s.Size() != 5 {
+		t.Errorf("expected size 5, got %d", s.Size())
+	}
+}
+
+func TestScoredIncrScore(t *testing.T) {
+	s := leaderboard()
+	if score := s.IncrScore("bob", 15); score != 25 {
+		t.Errorf("expected score 25, got %v", score)
+	}
+	if score := s.IncrScore("erin", -1); score != -1 {
+		t.Errorf("expected score -1, got %v", score)
+	}
+
+	expected := []string{"erin", "carol", "dave", "bob", "alice"}
+	if got := members(s.Ascend()); !slices.Equal(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestScoredRemove(t *testing.T) {
+	s := leaderboard()
+	if !s.Remove("carol") || s.Remove("carol") {
+		t.Fatal("expected carol to be removed once")
+	}
+	if _, ok := s.Score("carol"); ok {
+		t.Error("expected carol to have no score")
+	}
+
+	expected := []string{"bob", "dave", "alice"}
+	if got := members(s.Ascend()); !slices.Equal(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestScoredRankOf(t *testing.T) {
+	s := leaderboard()
+	cases := []struct {
+		member string
+		rank   int
+		found  bool
+	}{
+		{member: "bob", rank: 0, found: true},
+		{member: "carol", rank: 1, found: true},
+		{member: "dave", rank: 2, found: true},
+		{member: "alice", rank: 3, found: true},
+		{member: "erin", rank: 0, found: false},
+	}
+
+	for i, test := range cases {
+		t.Run(fmt.Sprintf("Case_%d", i), func(t *testing.T) {
+			rank, found := s.RankOf(test.member)
+			if rank != test.rank || found != test.found {
+				t.Errorf("expected (%d, %v), got (%d, %v)", test.rank, test.found, rank, found)
+			}
+			if found && s.At(rank).Member != test.member {
+				t.Errorf("expected At(%d) to be %s, got %v", rank, test.member, s.At(rank))
+			}
+		})
+	}
+}
+
+func TestScoredByScoreRange(t *testing.T) {
+	s := leaderboard()
+	cases := []struct {
+		lo, hi   float64
+		expected []string
+	}{
+		{lo: 0, hi: 5, expected: nil},
+		{lo: 10, hi: 20, expected: []string{"bob", "carol", "dave"}},
+		{lo: 15, hi: 30, expected: []string{"carol", "dave", "alice"}},
+		{lo: 20, hi: 20, expected: []string{"carol", "dave"}},
+		{lo: math.Inf(-1), hi: math.Inf(1), expected: []string{"bob", "carol", "dave", "alice"}},
+	}
+
+	for i, test := range cases {
+		t.Run(fmt.Sprintf("Case_%d", i), func(t *testing.T) {
+			got := members(s.ByScoreRange(test.lo, test.hi))
+			if !slices.Equal(got, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, got)
+			}
+		})
+	}
+}
+
+func TestScoredNaN(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic for a NaN score")
+		}
+	}()
+	leaderboard().Add("erin", math.NaN())
+}
@@ -0,0 +1,114 @@
+package smallset
+
+import "iter"
+
+// Take returns an iterator over the first n elements of seq.
+// It stops pulling from seq as soon as n elements have been yielded. It panics if n is < 0.
+func Take[T any](seq iter.Seq[T], n int) iter.Seq[T] {
+	if n < 0 {
+		panic("smallset.Take: n must be >= 0")
+	}
+	return func(yield func(T) bool) {
+		if n == 0 {
+			return
+		}
+
+		taken := 0
+		for e := range seq {
+			if !yield(e) {
+				return
+			}
+			taken++
+			if taken == n {
+				return
+			}
+		}
+	}
+}
+
+// Drop returns an iterator over the elements of seq after the first n. It panics if n is < 0.
+func Drop[T any](seq iter.Seq[T], n int) iter.Seq[T] {
+	if n < 0 {
+		panic("smallset.Drop: n must be >= 0")
+	}
+	return func(yield func(T) bool) {
+		dropped := 0
+		for e := range seq {
+			if dropped < n {
+				dropped++
+				continue
+			}
+			if !yield(e) {
+				return
+			}
+		}
+	}
+}
+
+// TakeWhile returns an iterator over the leading elements of seq that satisfy pred.
+// It stops at the first element that doesn't. It panics if pred is nil.
+func TakeWhile[T any](seq iter.Seq[T], pred func(T) bool) iter.Seq[T] {
+	if pred == nil {
+		panic("smallset.TakeWhile: pred cannot be nil")
+	}
+	return func(yield func(T) bool) {
+		for e := range seq {
+			if !pred(e) || !yield(e) {
+				return
+			}
+		}
+	}
+}
+
+// DropWhile returns an iterator over the elements of seq starting from the first one
+// that doesn't satisfy pred. It panics if pred is nil.
+func DropWhile[T any](seq iter.Seq[T], pred func(T) bool) iter.Seq[T] {
+	if pred == nil {
+		panic("smallset.DropWhile: pred cannot be nil")
+	}
+	return func(yield func(T) bool) {
+		dropping := true
+		for e := range seq {
+			if dropping && pred(e) {
+				continue
+			}
+			dropping = false
+			if !yield(e) {
+				return
+			}
+		}
+	}
+}
+
+// FilterSeq returns an iterator over the elements of seq that satisfy pred.
+// Unlike filtering the set, it's lazy: pred is only called on the elements that are pulled,
+// which pays off in pipelines that consume only a few leading elements. It panics if pred is nil.
+func FilterSeq[T any](seq iter.Seq[T], pred func(T) bool) iter.Seq[T] {
+	if pred == nil {
+		panic("smallset.FilterSeq: pred cannot be nil")
+	}
+	return func(yield func(T) bool) {
+		for e := range seq {
+			if pred(e) && !yield(e) {
+				return
+			}
+		}
+	}
+}
+
+// MapSeq returns an iterator over the results of f applied to the elements of seq.
+// Unlike mapping the set, it's lazy: f is only called on the elements that are pulled.
+// The results are not sorted nor deduplicated, since f may not preserve the order.
+// It panics if f is nil.
+func MapSeq[T, U any](seq iter.Seq[T], f func(T) U) iter.Seq[U] {
+	if f == nil {
+		panic("smallset.MapSeq: f cannot be nil")
+	}
+	return func(yield func(U) bool) {
+		for e := range seq {
+			if !yield(f(e)) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package smallset
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestSeqAdapters(t *testing.T) {
+	s := From(1, 2, 3, 4, 5, 6)
+	less := func(max int) func(int) bool { return func(e int) bool { return e < max } }
+
+	cases := []struct {
+		name     string
+		seq      func() []int
+		expected []int
+	}{
+		{name: "Take", seq: func() []int { return slices.Collect(Take(s.BetweenValues(2, 6), 2)) }, expected: []int{2, 3}},
+		{name: "Take zero", seq: func() []int { return slices.Collect(Take(s.BetweenValues(2, 6), 0)) }, expected: nil},
+		{name: "Take more", seq: func() []int { return slices.Collect(Take(s.BetweenValues(2, 6), 10)) }, expected: []int{2, 3, 4, 5}},
+		{name: "Drop", seq: func() []int { return slices.Collect(Drop(s.BetweenValues(2, 6), 2)) }, expected: []int{4, 5}},
+		{name: "Drop more", seq: func() []int { return slices.Collect(Drop(s.BetweenValues(2, 6), 10)) }, expected: nil},
+		{name: "TakeWhile", seq: func() []int { return slices.Collect(TakeWhile(slices.Values(s.items), less(4))) }, expected: []int{1, 2, 3}},
+		{name: "TakeWhile none", seq: func() []int { return slices.Collect(TakeWhile(slices.Values(s.items), less(0))) }, expected: nil},
+		{name: "DropWhile", seq: func() []int { return slices.Collect(DropWhile(slices.Values(s.items), less(4))) }, expected: []int{4, 5, 6}},
+		{name: "DropWhile all", seq: func() []int { return slices.Collect(DropWhile(slices.Values(s.items), less(10))) }, expected: nil},
+		{name: "Composed", seq: func() []int { return slices.Collect(Take(Drop(slices.Values(s.items), 1), 2)) }, expected: []int{2, 3}},
+	}
+
+	for i, test := range cases {
+		t.Run(fmt.Sprintf("Case_%d", i), func(t *testing.T) {
+			if got := test.seq(); !slices.Equal(got, test.expected) {
+				t.Errorf("%s: expected %v, got %v", test.name, test.expected, got)
+			}
+		})
+	}
+}
+
+func TestTakeStopsEarly(t *testing.T) {
+	pulled := 0
+	seq := func(yield func(int) bool) {
+		for i := range 100 {
+			pulled++
+			if !yield(i) {
+				return
+			}
+		}
+	}
+
+	for range Take(seq, 3) {
+	}
+	if pulled != 3 {
+		t.Errorf("expected Take to pull 3 elements, pulled %d", pulled)
+	}
+}
+
+func TestFilterMapSeq(t *testing.T) {
+	s := From(1, 2, 3, 4, 5, 6, 7, 8)
+
+	calls := 0
+	even := func(e int) bool {
+		calls++
+		return e%2 == 0
+	}
+	label := func(e int) string { return fmt.Sprintf("#%d", e) }
+
+	got := slices.Collect(Take(MapSeq(FilterSeq(s.BetweenValues(1, 8), even), label), 2))
+	expected := []string{"#2", "#4"}
+	if !slices.Equal(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+
+	// only the elements up to the second even one were pulled
+	if calls != 4 {
+		t.Errorf("expected pred to be called 4 times, got %d", calls)
+	}
+}
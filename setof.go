@@ -0,0 +1,34 @@
+package smallset
+
+import "iter"
+
+// SetOf is the interface shared by the sorted sets of this package, regardless of how they
+// store their elements. It allows application code to switch between backends, for example
+// from an [Ordered] set to a [SkipList] one as the expected size grows, without rewriting call sites.
+type SetOf[T any] interface {
+	Size() int
+	IsEmpty() bool
+	Clear()
+	Items() []T
+	Ascend() iter.Seq2[int, T]
+
+	Contains(e T) bool
+	Add(e T) bool
+	Remove(e T) bool
+	RemoveBefore(max T) int
+	RemoveFrom(min T) int
+	RemoveBetween(min, max T) int
+
+	Min() T
+	Max() T
+	PopMin() T
+	PopMax() T
+}
+
+var (
+	_ SetOf[int] = (*Ordered[int])(nil)
+	_ SetOf[int] = (*Custom[int])(nil)
+	_ SetOf[int] = (*Gapped[int])(nil)
+	_ SetOf[int] = (*SkipList[int])(nil)
+	_ SetOf[int] = (*Buffered[int])(nil)
+)
@@ -0,0 +1,274 @@
+package smallset
+
+import (
+	"cmp"
+	"iter"
+	"math/rand/v2"
+)
+
+// skipListMaxLevel is the maximum number of levels of a [SkipList], enough for 4^32 elements.
+const skipListMaxLevel = 32
+
+// SkipList is a sorted set backed by a skip list, whose insertions and removals are
+// O(log(N)) instead of O(N), at the cost of a pointer per element per level and poor cache
+// locality. It's meant for sets that routinely grow well past the sweet spot of the slice-based
+// sets (> 10000 elements). It implements [SetOf]. Not safe for concurrent use.
+type SkipList[T any] struct {
+	head  *skipNode[T] // sentinel node, whose item is unused
+	level int          // number of levels in use
+	size  int
+	cmp   compareFunc[T]
+}
+
+type skipNode[T any] struct {
+	item T
+	next []*skipNode[T]
+}
+
+// NewSkipList returns an initialized skip list set for ordered types.
+func NewSkipList[T cmp.Ordered]() *SkipList[T] {
+	return newSkipList[T](cmp.Compare[T])
+}
+
+// NewSkipListCustom returns an initialized skip list set with the provided compare function.
+// It panics if cmp is nil.
+func NewSkipListCustom[T any](cmp func(a, b T) int) *SkipList[T] {
+	if cmp == nil {
+		panic("smallset.NewSkipListCustom: cmp cannot be nil")
+	}
+	return newSkipList(cmp)
+}
+
+func newSkipList[T any](cmp compareFunc[T]) *SkipList[T] {
+	return &SkipList[T]{
+		head:  &skipNode[T]{next: make([]*skipNode[T], skipListMaxLevel)},
+		level: 1,
+		cmp:   cmp,
+	}
+}
+
+// Size returns the number of elements in the set.
+func (s *SkipList[T]) Size() int {
+	return s.size
+}
+
+// IsEmpty returns whether the set has no elements.
+func (s *SkipList[T]) IsEmpty() bool {
+	return s.size == 0
+}
+
+// Clear removes all elements from the set.
+func (s *SkipList[T]) Clear() {
+	clear(s.head.next)
+	s.level = 1
+	s.size = 0
+}
+
+// Items returns the elements of the set in ascending order.
+func (s *SkipList[T]) Items() []T {
+	items := make([]T, 0, s.size)
+	for _, e := range s.Ascend() {
+		items = append(items, e)
+	}
+	return items
+}
+
+// Ascend returns an iterator over the set in ascending order.
+func (s *SkipList[T]) Ascend() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		i := 0
+		for n := s.head.next[0]; n != nil; n = n.next[0] {
+			if !yield(i, n.item) {
+				return
+			}
+			i++
+		}
+	}
+}
+
+// Contains returns whether the element is in the set. Operation is O(log(N)).
+func (s *SkipList[T]) Contains(e T) bool {
+	var prev [skipListMaxLevel]*skipNode[T]
+	n := s.predecessors(e, &prev).next[0]
+	return n != nil && s.cmp.equal(n.item, e)
+}
+
+// Add an element and returns whether is was added (true), or was already present (false).
+// Operation is O(log(N)).
+func (s *SkipList[T]) Add(e T) bool {
+	var prev [skipListMaxLevel]*skipNode[T]
+	if n := s.predecessors(e, &prev).next[0]; n != nil && s.cmp.equal(n.item, e) {
+		return false
+	}
+
+	level := randomLevel()
+	if level > s.level {
+		for l := s.level; l < level; l++ {
+			prev[l] = s.head
+		}
+		s.level = level
+	}
+
+	node := &skipNode[T]{item: e, next: make([]*skipNode[T], level)}
+	for l := range level {
+		node.next[l] = prev[l].next[l]
+		prev[l].next[l] = node
+	}
+
+	s.size++
+	return true
+}
+
+// Remove an element if present, and returns whether is was removed (true), or was never present (false).
+// Operation is O(log(N)).
+func (s *SkipList[T]) Remove(e T) bool {
+	var prev [skipListMaxLevel]*skipNode[T]
+	node := s.predecessors(e, &prev).next[0]
+	if node == nil || !s.cmp.equal(node.item, e) {
+		return false
+	}
+
+	for l := range node.next {
+		prev[l].next[l] = node.next[l]
+	}
+
+	s.size--
+	return true
+}
+
+// RemoveBefore removes all elements e such that e < max. Returns num removed.
+func (s *SkipList[T]) RemoveBefore(max T) int {
+	var prev [skipListMaxLevel]*skipNode[T]
+	s.predecessors(max, &prev)
+
+	removed := s.countBefore(s.head.next[0], max)
+	for l := range s.level {
+		s.head.next[l] = prev[l].next[l]
+	}
+
+	s.size -= removed
+	return removed
+}
+
+// RemoveFrom removes all elements e such that e >= min. Returns num removed.
+func (s *SkipList[T]) RemoveFrom(min T) int {
+	var prev [skipListMaxLevel]*skipNode[T]
+	s.predecessors(min, &prev)
+
+	removed := 0
+	for n := prev[0].next[0]; n != nil; n = n.next[0] {
+		removed++
+	}
+	for l := range s.level {
+		prev[l].next[l] = nil
+	}
+
+	s.size -= removed
+	return removed
+}
+
+// RemoveBetween removes all elements e such that min <= e < max. Returns num removed.
+// Panics if max < min.
+func (s *SkipList[T]) RemoveBetween(min, max T) int {
+	if s.cmp.less(max, min) {
+		panic("smallset.SkipList.RemoveBetween: invalid range (max < min)")
+	}
+
+	var first, last [skipListMaxLevel]*skipNode[T]
+	s.predecessors(min, &first)
+	s.predecessors(max, &last)
+
+	removed := s.countBefore(first[0].next[0], max)
+	for l := range s.level {
+		// the nodes in range at level l are the ones after first[l] up to last[l] included
+		first[l].next[l] = last[l].next[l]
+	}
+
+	s.size -= removed
+	return removed
+}
+
+// Min returns the smallest element in the set, or panics if the set is empty.
+func (s *SkipList[T]) Min() T {
+	if s.size == 0 {
+		panic("smallset.SkipList.Min: set is empty")
+	}
+	return s.head.next[0].item
+}
+
+// Max returns the biggest element in the set, or panics if the set is empty. Operation is O(log(N)).
+func (s *SkipList[T]) Max() T {
+	if s.size == 0 {
+		panic("smallset.SkipList.Max: set is empty")
+	}
+	return s.last().item
+}
+
+// PopMin removes and returns the smallest element in the set, or panics if the set is empty.
+func (s *SkipList[T]) PopMin() T {
+	if s.size == 0 {
+		panic("smallset.SkipList.PopMin: set is empty")
+	}
+
+	node := s.head.next[0]
+	for l := range node.next {
+		s.head.next[l] = node.next[l]
+	}
+
+	s.size--
+	return node.item
+}
+
+// PopMax removes and returns the biggest element in the set, or panics if the set is empty.
+// Operation is O(log(N)).
+func (s *SkipList[T]) PopMax() T {
+	if s.size == 0 {
+		panic("smallset.SkipList.PopMax: set is empty")
+	}
+
+	e := s.last().item
+	s.Remove(e)
+	return e
+}
+
+// predecessors fills prev with the last node whose item is < e at each level in use,
+// and returns the one at the lowest level.
+func (s *SkipList[T]) predecessors(e T, prev *[skipListMaxLevel]*skipNode[T]) *skipNode[T] {
+	n := s.head
+	for l := s.level - 1; l >= 0; l-- {
+		for n.next[l] != nil && s.cmp.less(n.next[l].item, e) {
+			n = n.next[l]
+		}
+		prev[l] = n
+	}
+	return n
+}
+
+// countBefore returns the number of nodes from n included whose item is < max.
+func (s *SkipList[T]) countBefore(n *skipNode[T], max T) int {
+	count := 0
+	for ; n != nil && s.cmp.less(n.item, max); n = n.next[0] {
+		count++
+	}
+	return count
+}
+
+// last returns the node with the biggest item. It assumes the set is not empty.
+func (s *SkipList[T]) last() *skipNode[T] {
+	n := s.head
+	for l := s.level - 1; l >= 0; l-- {
+		for n.next[l] != nil {
+			n = n.next[l]
+		}
+	}
+	return n
+}
+
+// randomLevel returns the level of a new node, which is l with probability (1/4)^(l-1).
+func randomLevel() int {
+	level := 1
+	for level < skipListMaxLevel && rand.Uint32()&3 == 0 {
+		level++
+	}
+	return level
+}
@@ -0,0 +1,57 @@
+package smallset
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSkipList(t *testing.T) {
+	s := NewSkipListCustom(PersonCmp)
+	for _, p := range people2 {
+		s.Add(p)
+	}
+
+	expected := CustomFrom(PersonCmp, people2...).Items()
+	if items := s.Items(); !slices.Equal(items, expected) {
+		t.Errorf("Items mismatch.\nExpected: %v\nActual: %v", expected, items)
+	}
+
+	if s.Min().ID != 20 || s.Max().ID != 50 {
+		t.Errorf("expected min 20 and max 50, got %d and %d", s.Min().ID, s.Max().ID)
+	}
+
+	if removed := s.RemoveBetween(Person{ID: 25}, Person{ID: 45}); removed != 2 {
+		t.Errorf("RemoveBetween expected 2, got %d", removed)
+	}
+	if s.Contains(Person{ID: 30}) || !s.Contains(Person{ID: 50}) || s.Size() != 2 {
+		t.Errorf("unexpected items after RemoveBetween: %v", s.Items())
+	}
+}
+
+func BenchmarkSkipListAdd(b *testing.B) {
+	const size = 100_000
+	keys := make([]int, size)
+	for i := range keys {
+		keys[i] = (i * 7919) % size
+	}
+
+	b.Run("ordered", func(b *testing.B) {
+		b.ResetTimer()
+		for range b.N {
+			s := New[int](size)
+			for _, k := range keys {
+				s.Add(k)
+			}
+		}
+	})
+
+	b.Run("skiplist", func(b *testing.B) {
+		b.ResetTimer()
+		for range b.N {
+			s := NewSkipList[int]()
+			for _, k := range keys {
+				s.Add(k)
+			}
+		}
+	})
+}
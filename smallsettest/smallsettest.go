@@ -0,0 +1,255 @@
+// Package smallsettest provides a reference model and a differential test harness for set
+// implementations, like the ones of the smallset package or of custom backends.
+//
+// The harness applies random sequences of operations both to the set under test and to
+// a simple map-based [Model], failing the test as soon as their results differ.
+//
+//	func TestMySet(t *testing.T) {
+//		set := smallset.New[int](10)
+//		smallsettest.RunOps(t, set, rand.New(rand.NewPCG(1, 2)), 10_000, smallsettest.Ints(100))
+//	}
+package smallsettest
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// Set is the interface of the sets that can be tested with [RunOps].
+// It's satisfied by smallset.Ordered and smallset.Custom, as well as by the [Model].
+type Set[T any] interface {
+	Add(e T) bool
+	Remove(e T) bool
+	Contains(e T) bool
+	RemoveBefore(max T) int
+	RemoveFrom(min T) int
+	RemoveBetween(min, max T) int
+	PopMin() T
+	PopMax() T
+	Size() int
+	Items() []T
+	Clear()
+}
+
+// Model is the reference set implementation, made of a map whose keys are sorted on demand.
+// It's simple enough to be obviously correct, at the expense of performance.
+//
+// Like the sets of the smallset package, it considers all NaNs equal to each other,
+// so it contains at most one NaN.
+type Model[T cmp.Ordered] struct {
+	m   map[T]struct{}
+	nan *T // the NaN of the model, kept out of the map where NaNs are never equal
+}
+
+// NewModel returns an empty model.
+func NewModel[T cmp.Ordered]() *Model[T] {
+	return &Model[T]{m: make(map[T]struct{})}
+}
+
+// Add adds the element to the model, returning whether it was added.
+func (m *Model[T]) Add(e T) bool {
+	if isNaN(e) {
+		if m.nan != nil {
+			return false
+		}
+		m.nan = &e
+		return true
+	}
+
+	if _, ok := m.m[e]; ok {
+		return false
+	}
+	m.m[e] = struct{}{}
+	return true
+}
+
+// Remove removes the element from the model, returning whether it was removed.
+func (m *Model[T]) Remove(e T) bool {
+	if isNaN(e) {
+		if m.nan == nil {
+			return false
+		}
+		m.nan = nil
+		return true
+	}
+
+	if _, ok := m.m[e]; !ok {
+		return false
+	}
+	delete(m.m, e)
+	return true
+}
+
+// Contains returns whether the element is in the model.
+func (m *Model[T]) Contains(e T) bool {
+	if isNaN(e) {
+		return m.nan != nil
+	}
+	_, ok := m.m[e]
+	return ok
+}
+
+// RemoveBefore removes all elements smaller than max, returning how many were removed.
+func (m *Model[T]) RemoveBefore(max T) int {
+	return m.removeIf(func(e T) bool { return cmp.Less(e, max) })
+}
+
+// RemoveFrom removes all elements greater than or equal to min, returning how many were removed.
+func (m *Model[T]) RemoveFrom(min T) int {
+	return m.removeIf(func(e T) bool { return !cmp.Less(e, min) })
+}
+
+// RemoveBetween removes all elements in the interval [min, max), returning how many were removed.
+func (m *Model[T]) RemoveBetween(min, max T) int {
+	return m.removeIf(func(e T) bool { return !cmp.Less(e, min) && cmp.Less(e, max) })
+}
+
+// PopMin removes and returns the smallest element. It panics if the model is empty.
+func (m *Model[T]) PopMin() T {
+	e := m.Items()[0]
+	m.Remove(e)
+	return e
+}
+
+// PopMax removes and returns the largest element. It panics if the model is empty.
+func (m *Model[T]) PopMax() T {
+	items := m.Items()
+	e := items[len(items)-1]
+	m.Remove(e)
+	return e
+}
+
+// Size returns the number of elements in the model.
+func (m *Model[T]) Size() int {
+	if m.nan != nil {
+		return len(m.m) + 1
+	}
+	return len(m.m)
+}
+
+// Items returns the elements of the model in ascending order, with the NaN first like [cmp.Compare].
+func (m *Model[T]) Items() []T {
+	items := slices.Sorted(maps.Keys(m.m))
+	if m.nan != nil {
+		items = slices.Insert(items, 0, *m.nan)
+	}
+	return items
+}
+
+// Clear removes all elements from the model.
+func (m *Model[T]) Clear() {
+	clear(m.m)
+	m.nan = nil
+}
+
+func (m *Model[T]) removeIf(pred func(e T) bool) int {
+	removed := 0
+	if m.nan != nil && pred(*m.nan) {
+		m.nan = nil
+		removed++
+	}
+	for e := range m.m {
+		if pred(e) {
+			delete(m.m, e)
+			removed++
+		}
+	}
+	return removed
+}
+
+// equal reports whether a and b are equal, considering NaNs equal to each other like [cmp.Compare].
+func equal[T cmp.Ordered](a, b T) bool {
+	return cmp.Compare(a, b) == 0
+}
+
+// isNaN reports whether x is a floating point NaN. It's always false for the other types.
+func isNaN[T cmp.Ordered](x T) bool {
+	return x != x
+}
+
+// Ints returns a generator of ints in [0, n), for use with [RunOps].
+// A small n causes many collisions, which exercise the handling of duplicates.
+func Ints(n int) func(r *rand.Rand) int {
+	return func(r *rand.Rand) int { return r.IntN(n) }
+}
+
+// RunOps applies ops random operations to set and to a [Model], with elements produced by gen.
+// After every operation it compares their results and their elements, failing the test with
+// the history of the operations as soon as they differ.
+// The set must be ordered consistently with [cmp.Compare].
+func RunOps[T cmp.Ordered](t testing.TB, set Set[T], r *rand.Rand, ops int, gen func(r *rand.Rand) T) {
+	t.Helper()
+	model := NewModel[T]()
+	for _, e := range set.Items() {
+		model.Add(e)
+	}
+
+	var history []string
+	for range ops {
+		op, got, want := apply(set, model, r, gen)
+		history = append(history, op)
+
+		if got != want {
+			t.Fatalf("%s: got %s, want %s\nhistory:\n%s", op, got, want, strings.Join(history, "\n"))
+		}
+
+		if items, expected := set.Items(), model.Items(); !slices.EqualFunc(items, expected, equal) {
+			t.Fatalf("%s: items are %v, want %v\nhistory:\n%s", op, items, expected, strings.Join(history, "\n"))
+		}
+	}
+}
+
+// apply applies a random operation to both the set and the model, returning its description
+// and the formatted results.
+func apply[T cmp.Ordered](set Set[T], model *Model[T], r *rand.Rand, gen func(r *rand.Rand) T) (op, got, want string) {
+	e := gen(r)
+	switch r.IntN(20) {
+	case 0, 1, 2, 3, 4, 5:
+		return fmt.Sprintf("Add(%v)", e), fmt.Sprint(set.Add(e)), fmt.Sprint(model.Add(e))
+
+	case 6, 7, 8:
+		return fmt.Sprintf("Remove(%v)", e), fmt.Sprint(set.Remove(e)), fmt.Sprint(model.Remove(e))
+
+	case 9, 10, 11:
+		return fmt.Sprintf("Contains(%v)", e), fmt.Sprint(set.Contains(e)), fmt.Sprint(model.Contains(e))
+
+	case 12:
+		return fmt.Sprintf("RemoveBefore(%v)", e), fmt.Sprint(set.RemoveBefore(e)), fmt.Sprint(model.RemoveBefore(e))
+
+	case 13:
+		return fmt.Sprintf("RemoveFrom(%v)", e), fmt.Sprint(set.RemoveFrom(e)), fmt.Sprint(model.RemoveFrom(e))
+
+	case 14, 15:
+		min, max := e, gen(r)
+		if cmp.Less(max, min) {
+			min, max = max, min
+		}
+		op = fmt.Sprintf("RemoveBetween(%v, %v)", min, max)
+		return op, fmt.Sprint(set.RemoveBetween(min, max)), fmt.Sprint(model.RemoveBetween(min, max))
+
+	case 16, 17:
+		if model.Size() == 0 {
+			return "PopMin()", fmt.Sprint(set.Size()), "0"
+		}
+		return "PopMin()", fmt.Sprint(set.PopMin()), fmt.Sprint(model.PopMin())
+
+	case 18:
+		if model.Size() == 0 {
+			return "PopMax()", fmt.Sprint(set.Size()), "0"
+		}
+		return "PopMax()", fmt.Sprint(set.PopMax()), fmt.Sprint(model.PopMax())
+
+	default:
+		if r.IntN(10) > 0 {
+			return "Size()", fmt.Sprint(set.Size()), fmt.Sprint(model.Size())
+		}
+		set.Clear()
+		model.Clear()
+		return "Clear()", "", ""
+	}
+}
@@ -0,0 +1,159 @@
+package smallsettest
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/pippellia-btc/smallset"
+)
+
+func TestRunOps(t *testing.T) {
+	t.Run("ordered", func(t *testing.T) {
+		set := smallset.New[int](10)
+		RunOps(t, set, rand.New(rand.NewPCG(1, 2)), 10_000, Ints(50))
+	})
+
+	t.Run("ordered with structures", func(t *testing.T) {
+		set := smallset.New[int](10).WithBloom(256).WithFingerprint()
+		RunOps(t, set, rand.New(rand.NewPCG(3, 4)), 10_000, Ints(50))
+	})
+
+	t.Run("custom", func(t *testing.T) {
+		set := smallset.NewCustom(cmp.Compare[int], 10)
+		RunOps(t, set, rand.New(rand.NewPCG(5, 6)), 10_000, Ints(50))
+	})
+
+	t.Run("paged", func(t *testing.T) {
+		set := smallset.NewPaged[int](4)
+		RunOps(t, set, rand.New(rand.NewPCG(9, 10)), 20_000, Ints(200))
+	})
+
+	t.Run("paged with bigger pages", func(t *testing.T) {
+		set := smallset.NewPaged[int](16)
+		RunOps(t, set, rand.New(rand.NewPCG(23, 24)), 20_000, Ints(500))
+	})
+
+	t.Run("paged custom", func(t *testing.T) {
+		set := smallset.NewPagedCustom(cmp.Compare[int], 8)
+		RunOps(t, set, rand.New(rand.NewPCG(25, 26)), 20_000, Ints(200))
+	})
+
+	t.Run("gapped", func(t *testing.T) {
+		set := smallset.NewGapped[int](1)
+		RunOps(t, set, rand.New(rand.NewPCG(11, 12)), 20_000, Ints(200))
+	})
+
+	t.Run("skiplist", func(t *testing.T) {
+		set := smallset.NewSkipList[int]()
+		RunOps(t, set, rand.New(rand.NewPCG(13, 14)), 20_000, Ints(200))
+	})
+
+	t.Run("buffered", func(t *testing.T) {
+		set := smallset.NewBuffered[int](8)
+		RunOps(t, set, rand.New(rand.NewPCG(15, 16)), 20_000, Ints(200))
+	})
+
+	t.Run("stored", func(t *testing.T) {
+		set := smallset.NewStored[int](smallset.NewSliceStorage[int](0))
+		RunOps(t, set, rand.New(rand.NewPCG(17, 18)), 20_000, Ints(200))
+	})
+
+	t.Run("stored custom", func(t *testing.T) {
+		set := smallset.NewStoredCustom(cmp.Compare[int], smallset.NewSliceStorage[int](0))
+		RunOps(t, set, rand.New(rand.NewPCG(21, 22)), 20_000, Ints(200))
+	})
+
+	t.Run("front coded", func(t *testing.T) {
+		set := smallset.NewFrontCoded()
+		RunOps(t, set, rand.New(rand.NewPCG(27, 28)), 20_000, paths(200))
+	})
+
+	t.Run("floats with NaNs", func(t *testing.T) {
+		set := smallset.New[float64](10)
+		RunOps(t, set, rand.New(rand.NewPCG(19, 20)), 10_000, floats(50))
+	})
+
+	t.Run("model", func(t *testing.T) {
+		RunOps(t, NewModel[int](), rand.New(rand.NewPCG(7, 8)), 1000, Ints(20))
+	})
+}
+
+// floats returns a generator of integral floats in [0, n), and of NaNs with different payloads.
+func floats(n int) func(r *rand.Rand) float64 {
+	return func(r *rand.Rand) float64 {
+		if r.IntN(10) == 0 {
+			return math.Float64frombits(0x7ff8000000000000 | r.Uint64N(1<<51))
+		}
+		return float64(r.IntN(n))
+	}
+}
+
+// paths returns a generator of n URL paths sharing long prefixes.
+func paths(n int) func(r *rand.Rand) string {
+	return func(r *rand.Rand) string {
+		return fmt.Sprintf("/users/%d/posts/%d", r.IntN(n/10+1), r.IntN(10))
+	}
+}
+
+func TestModelNaN(t *testing.T) {
+	m := NewModel[float64]()
+	if !m.Add(math.NaN()) || m.Add(-math.NaN()) {
+		t.Fatalf("expected a single NaN to be added")
+	}
+	m.Add(1)
+
+	if size := m.Size(); size != 2 {
+		t.Errorf("expected size 2, got %d", size)
+	}
+	if items := m.Items(); !math.IsNaN(items[0]) || items[1] != 1 {
+		t.Errorf("expected [NaN 1], got %v", items)
+	}
+	if removed := m.RemoveBefore(1); removed != 1 || m.Contains(math.NaN()) {
+		t.Errorf("expected RemoveBefore to remove the NaN, removed %d", removed)
+	}
+}
+
+// brokenSet forgets to deduplicate elements.
+type brokenSet struct {
+	*smallset.Ordered[int]
+}
+
+func (b brokenSet) Add(e int) bool {
+	b.Ordered.Add(e)
+	return true
+}
+
+func TestRunOpsDetectsBugs(t *testing.T) {
+	ft := &fakeT{}
+	func() {
+		defer func() {
+			if r := recover(); r != nil && r != errFatal {
+				panic(r)
+			}
+		}()
+		RunOps(ft, brokenSet{smallset.New[int](10)}, rand.New(rand.NewPCG(1, 2)), 1000, Ints(5))
+	}()
+
+	if !ft.failed {
+		t.Errorf("expected RunOps to detect the broken Add")
+	}
+}
+
+var errFatal = errors.New("fatal")
+
+// fakeT records failures instead of failing the test.
+type fakeT struct {
+	testing.TB
+	failed bool
+}
+
+func (f *fakeT) Helper() {}
+
+func (f *fakeT) Fatalf(format string, args ...any) {
+	f.failed = true
+	panic(errFatal)
+}
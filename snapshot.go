@@ -0,0 +1,49 @@
+package smallset
+
+import (
+	"iter"
+	"slices"
+)
+
+// Snapshot is an immutable view of the elements of a set at the time it was taken with
+// [Ordered.Snapshot] or [Custom.Snapshot]. Taking it is O(1), because the set and its snapshots
+// share the same slice, which the set copies before its next modification (copy-on-write).
+//
+// Hence the snapshot stays the same while the set keeps changing, and since it's never modified,
+// it's safe for concurrent use. It allows background tasks, like reporting, to walk a consistent
+// view of the set without holding the lock that guards it.
+type Snapshot[T any] struct {
+	items []T
+	cmp   compareFunc[T]
+}
+
+// Size returns the number of elements in the snapshot.
+func (s *Snapshot[T]) Size() int {
+	return len(s.items)
+}
+
+// IsEmpty returns whether the snapshot has no elements.
+func (s *Snapshot[T]) IsEmpty() bool {
+	return len(s.items) == 0
+}
+
+// Contains returns whether the element is in the snapshot. Operation is O(log(N)).
+func (s *Snapshot[T]) Contains(e T) bool {
+	_, found := slices.BinarySearchFunc(s.items, e, s.cmp)
+	return found
+}
+
+// Items returns a copy of the elements of the snapshot.
+func (s *Snapshot[T]) Items() []T {
+	return slices.Clone(s.items)
+}
+
+// Ascend returns an iterator over the snapshot in ascending order.
+func (s *Snapshot[T]) Ascend() iter.Seq2[int, T] {
+	return slices.All(s.items)
+}
+
+// Descend returns an iterator over the snapshot in descending order.
+func (s *Snapshot[T]) Descend() iter.Seq2[int, T] {
+	return slices.Backward(s.items)
+}
@@ -0,0 +1,85 @@
+package smallset
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestSnapshot(t *testing.T) {
+	cases := []struct {
+		name   string
+		modify func(s *Ordered[int])
+	}{
+		{name: "Add", modify: func(s *Ordered[int]) { s.Add(0) }},
+		{name: "Remove", modify: func(s *Ordered[int]) { s.Remove(2) }},
+		{name: "ReplaceAt", modify: func(s *Ordered[int]) { s.ReplaceAt(0, 0) }},
+		{name: "AddSorted", modify: func(s *Ordered[int]) { s.AddSorted([]int{0, 4}) }},
+		{name: "RemoveBetween", modify: func(s *Ordered[int]) { s.RemoveBetween(1, 3) }},
+		{name: "Compact", modify: func(s *Ordered[int]) { s.MarkRemove(3); s.Compact() }},
+		{name: "ApplyDiff", modify: func(s *Ordered[int]) { s.ApplyDiff([]int{9}, []int{1}) }},
+		{name: "Clear", modify: func(s *Ordered[int]) { s.Clear() }},
+		{name: "Release", modify: func(s *Ordered[int]) { s.Release()[0] = 100 }},
+	}
+
+	for i, test := range cases {
+		t.Run(fmt.Sprintf("Case_%d", i), func(t *testing.T) {
+			s := New[int](10)
+			s.Add(1)
+			s.Add(3)
+			s.Add(5)
+			snap := s.Snapshot()
+
+			test.modify(s)
+			if items := snap.Items(); !slices.Equal(items, []int{1, 3, 5}) {
+				t.Errorf("%s: expected the snapshot to stay [1 3 5], got %v", test.name, items)
+			}
+
+			// the set is modified as if it had no snapshots
+			fresh := From(1, 3, 5)
+			test.modify(fresh)
+			if !s.IsEqual(fresh) {
+				t.Errorf("%s: expected the set %v, got %v", test.name, fresh.items, s.items)
+			}
+		})
+	}
+}
+
+func TestSnapshotConcurrent(t *testing.T) {
+	s := New[int](10)
+	for i := range 100 {
+		s.Add(i)
+	}
+
+	snap := s.Snapshot()
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		sum := 0
+		for _, e := range snap.Ascend() {
+			sum += e
+		}
+		if sum != 4950 {
+			t.Errorf("expected the snapshot sum to be 4950, got %d", sum)
+		}
+	}()
+
+	for i := range 100 {
+		s.Remove(i)
+		s.Add(1000 + i)
+	}
+	wg.Wait()
+}
+
+func TestCustomSnapshot(t *testing.T) {
+	s := CustomFrom(PersonCmp, people2...)
+	snap := s.Snapshot()
+	s.Remove(Person{ID: 20})
+	s.Add(Person{ID: 10})
+
+	if !snap.Contains(Person{ID: 20}) || snap.Contains(Person{ID: 10}) || snap.Size() != 4 {
+		t.Errorf("expected the snapshot to be unchanged, got %v", snap.items)
+	}
+}
@@ -0,0 +1,19 @@
+package smallset
+
+// sorter is a read-only [sort.Interface] view over a set.
+type sorter[T any] struct {
+	set  indexed[T]
+	less func(a, b T) bool
+}
+
+func (s sorter[T]) Len() int           { return s.set.Size() }
+func (s sorter[T]) Less(i, j int) bool { return s.less(s.set.At(i), s.set.At(j)) }
+
+// Swap panics, because swapping elements would break the sorting of the set.
+// The view is already sorted, so sorting algorithms never call it.
+func (s sorter[T]) Swap(i, j int) {
+	if i == j {
+		return
+	}
+	panic("smallset.sorter.Swap: the set is read-only")
+}
@@ -0,0 +1,80 @@
+package smallset
+
+// Number is a constraint that permits any integer or floating-point type.
+type Number interface {
+	Integer | ~float32 | ~float64
+}
+
+// Summary holds the summary statistics of a set, as returned by [Ordered.Summary]
+// and [Custom.Summary]. Min, Max and Median are the zero value of T when the set is empty.
+type Summary[T any] struct {
+	Count  int
+	Min    T
+	Max    T
+	Median T // the lower median when Count is even, so that it's always an element of the set
+}
+
+// NumericSummary extends [Summary] with the statistics of numeric sets, as returned by [Summarize].
+// Mean is 0 when the set is empty.
+type NumericSummary[T Number] struct {
+	Summary[T]
+	Sum  T
+	Mean float64
+}
+
+// summarize returns the summary of the sorted items in O(1).
+func summarize[T any](items []T) Summary[T] {
+	if len(items) == 0 {
+		return Summary[T]{}
+	}
+	return Summary[T]{
+		Count:  len(items),
+		Min:    items[0],
+		Max:    items[len(items)-1],
+		Median: items[(len(items)-1)/2],
+	}
+}
+
+// Summarize returns the summary statistics of a numeric set, including its Sum and Mean.
+// The Sum is computed in T, so it can overflow for integers. O(N) complexity.
+func Summarize[T Number](s *Ordered[T]) NumericSummary[T] {
+	summary := NumericSummary[T]{Summary: s.Summary()}
+	if summary.Count == 0 {
+		return summary
+	}
+
+	for _, e := range s.Ascend() {
+		summary.Sum += e
+	}
+	summary.Mean = float64(summary.Sum) / float64(summary.Count)
+	return summary
+}
+
+// SumBetween returns the sum of the elements e such that min <= e < max.
+// It finds the range with two binary searches, and sums it in a single pass. O(log(N) + K) complexity.
+// Panics if max < min.
+func SumBetween[T Number](s *Ordered[T], min, max T) T {
+	if max < min {
+		panic("smallset.SumBetween: invalid range (max < min)")
+	}
+	_, sum := countAndSum(s, min, max)
+	return sum
+}
+
+// CountAndSumBetween returns the number and the sum of the elements e such that min <= e < max,
+// for computing windowed averages. O(log(N) + K) complexity. Panics if max < min.
+func CountAndSumBetween[T Number](s *Ordered[T], min, max T) (int, T) {
+	if max < min {
+		panic("smallset.CountAndSumBetween: invalid range (max < min)")
+	}
+	return countAndSum(s, min, max)
+}
+
+func countAndSum[T Number](s *Ordered[T], min, max T) (int, T) {
+	start, end := s.indexRange(min, max)
+	var sum T
+	for i := start; i < end; i++ {
+		sum += s.at(i)
+	}
+	return end - start, sum
+}
@@ -0,0 +1,72 @@
+package smallset
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSummarize(t *testing.T) {
+	cases := []struct {
+		set      []int
+		expected NumericSummary[int]
+	}{
+		{set: []int{}, expected: NumericSummary[int]{}},
+		{
+			set:      []int{7},
+			expected: NumericSummary[int]{Summary: Summary[int]{Count: 1, Min: 7, Max: 7, Median: 7}, Sum: 7, Mean: 7},
+		},
+		{
+			set:      []int{4, 1, 3, 2},
+			expected: NumericSummary[int]{Summary: Summary[int]{Count: 4, Min: 1, Max: 4, Median: 2}, Sum: 10, Mean: 2.5},
+		},
+		{
+			set:      []int{-5, 0, 5, 10, 100},
+			expected: NumericSummary[int]{Summary: Summary[int]{Count: 5, Min: -5, Max: 100, Median: 5}, Sum: 110, Mean: 22},
+		},
+	}
+
+	for i, test := range cases {
+		t.Run(fmt.Sprintf("Case_%d", i), func(t *testing.T) {
+			summary := Summarize(From(test.set...))
+			if summary != test.expected {
+				t.Errorf("Expected %+v, got %+v", test.expected, summary)
+			}
+		})
+	}
+}
+
+func TestCustomSummary(t *testing.T) {
+	s := CustomFrom(PersonCmp, people2...)
+	summary := s.Summary()
+
+	if summary.Count != 4 || summary.Min.ID != 20 || summary.Max.ID != 50 || summary.Median.ID != 30 {
+		t.Errorf("unexpected summary %+v", summary)
+	}
+}
+
+func TestSumBetween(t *testing.T) {
+	s := From(1.5, 2, 3, 10, 20)
+	cases := []struct {
+		min, max float64
+		count    int
+		sum      float64
+	}{
+		{min: 0, max: 100, count: 5, sum: 36.5},
+		{min: 2, max: 10, count: 2, sum: 5},
+		{min: 2.5, max: 3, count: 0, sum: 0},
+		{min: 50, max: 60, count: 0, sum: 0},
+	}
+
+	for i, test := range cases {
+		t.Run(fmt.Sprintf("Case_%d", i), func(t *testing.T) {
+			if sum := SumBetween(s, test.min, test.max); sum != test.sum {
+				t.Errorf("SumBetween expected %v, got %v", test.sum, sum)
+			}
+
+			count, sum := CountAndSumBetween(s, test.min, test.max)
+			if count != test.count || sum != test.sum {
+				t.Errorf("CountAndSumBetween expected (%d, %v), got (%d, %v)", test.count, test.sum, count, sum)
+			}
+		})
+	}
+}
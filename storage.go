@@ -0,0 +1,197 @@
+package smallset
+
+import (
+	"cmp"
+	"iter"
+	"slices"
+)
+
+// Storage holds the sorted elements of a set created with [NewStored] or [NewStoredCustom].
+// Implementations only store and move elements by index, while the set is responsible for keeping
+// them sorted and unique. It allows experimenting with paged, memory-mapped or instrumented storage
+// without forking the package, for example by embedding a [SliceStorage] and overriding some of its methods.
+//
+// If the storage also has a Clone() Storage[T] method, the clones of the set clone it,
+// otherwise they are backed by a slice.
+type Storage[T any] interface {
+	// Len returns the number of elements.
+	Len() int
+
+	// At returns the element at index i, with 0 <= i < Len().
+	At(i int) T
+
+	// Search returns the index of e in the elements sorted by cmp, or the index where it
+	// would be inserted, and whether it's present.
+	Search(e T, cmp func(a, b T) int) (int, bool)
+
+	// Insert inserts e at index i, with 0 <= i <= Len(), shifting the following elements.
+	Insert(i int, e T)
+
+	// DeleteRange deletes the elements in [i, j), with 0 <= i <= j <= Len(),
+	// shifting the following elements.
+	DeleteRange(i, j int)
+
+	// All returns an iterator over the elements in order, together with their index.
+	All() iter.Seq2[int, T]
+}
+
+// SliceStorage is the default [Storage], a slice like the one of [Ordered] and [Custom] sets.
+type SliceStorage[T any] struct {
+	items []T
+}
+
+// NewSliceStorage returns an empty slice storage with the provided capacity.
+// It panics if the capacity is < 0.
+func NewSliceStorage[T any](capacity int) *SliceStorage[T] {
+	if capacity < 0 {
+		panic("smallset.NewSliceStorage: capacity must be >= 0")
+	}
+	return &SliceStorage[T]{items: make([]T, 0, capacity)}
+}
+
+func (s *SliceStorage[T]) Len() int {
+	return len(s.items)
+}
+
+func (s *SliceStorage[T]) At(i int) T {
+	return s.items[i]
+}
+
+func (s *SliceStorage[T]) Search(e T, cmp func(a, b T) int) (int, bool) {
+	return slices.BinarySearchFunc(s.items, e, cmp)
+}
+
+func (s *SliceStorage[T]) Insert(i int, e T) {
+	s.items = slices.Insert(s.items, i, e)
+}
+
+func (s *SliceStorage[T]) DeleteRange(i, j int) {
+	s.items = slices.Delete(s.items, i, j)
+}
+
+func (s *SliceStorage[T]) All() iter.Seq2[int, T] {
+	return slices.All(s.items)
+}
+
+func (s *SliceStorage[T]) Clone() Storage[T] {
+	return &SliceStorage[T]{items: slices.Clone(s.items)}
+}
+
+// NewStored returns a set for ordered types whose elements are kept in the provided storage,
+// which must be empty or hold sorted and unique elements. It panics if storage is nil.
+//
+// Lookups and the modifications of single elements or ranges, like [Ordered.Contains], [Ordered.Add],
+// [Ordered.RemoveBetween], [Ordered.PopMin] or [Ordered.Ascend], go straight to the storage.
+// The other methods, like the set algebra or the encodings, work on a copy of the elements
+// in a slice, which is written back to the storage if they modify it, so they are O(N) even when
+// they are faster on slice-backed sets. The results of the set operations are backed by slices,
+// and the options about the slice, like [Ordered.WithGrowth], have no effect.
+// Since reads may copy the elements, the set is not safe for concurrent reads either.
+func NewStored[T cmp.Ordered](storage Storage[T]) *Ordered[T] {
+	if storage == nil {
+		panic("smallset.NewStored: storage cannot be nil")
+	}
+	return &Ordered[T]{store: &store[T]{storage: storage, cmp: cmp.Compare[T]}}
+}
+
+// NewStoredCustom returns a set with the provided compare function whose elements are kept
+// in the provided storage, which must be empty or hold elements sorted by cmp and unique.
+// It works like the sets of [NewStored]. It panics if cmp or storage are nil.
+func NewStoredCustom[T any](cmp func(a, b T) int, storage Storage[T]) *Custom[T] {
+	if cmp == nil {
+		panic("smallset.NewStoredCustom: cmp cannot be nil")
+	}
+	if storage == nil {
+		panic("smallset.NewStoredCustom: storage cannot be nil")
+	}
+	return &Custom[T]{cmp: cmp, store: &store[T]{storage: storage, cmp: cmp}}
+}
+
+// Storage returns the storage of a set created with [NewStored], or nil if the set is backed by a slice.
+func (s *Ordered[T]) Storage() Storage[T] {
+	if s.store == nil {
+		return nil
+	}
+	return s.store.storage
+}
+
+// Storage returns the storage of a set created with [NewStoredCustom], or nil if the set is backed by a slice.
+func (s *Custom[T]) Storage() Storage[T] {
+	if s.store == nil {
+		return nil
+	}
+	return s.store.storage
+}
+
+// store is the state of a set backed by a [Storage].
+//
+// The methods that work on the whole set pin its elements, copying them into the slice of the set,
+// so that they run the same code of slice-backed sets. When the outermost pin is released,
+// the slice is written back to the storage if it was modified, and dropped.
+// Pins are nested by ignoring all but the outermost one.
+type store[T any] struct {
+	storage Storage[T]
+	cmp     func(a, b T) int // the compare function of the set, kept to pass it to Search without allocating
+	pinned  bool
+	dirty   bool // whether the pinned elements were modified
+}
+
+// pin returns a copy of the elements of the storage.
+func (st *store[T]) pin() []T {
+	st.pinned = true
+	return collectStorage(st.storage)
+}
+
+// unpin writes the pinned elements back to the storage if they were modified.
+func (st *store[T]) unpin(items []T) {
+	st.pinned = false
+	if !st.dirty {
+		return
+	}
+
+	st.storage.DeleteRange(0, st.storage.Len())
+	for i, e := range items {
+		st.storage.Insert(i, e)
+	}
+	st.dirty = false
+}
+
+// touch records that the pinned elements are about to be modified. It's a no-op if they are
+// not pinned, or if st is nil.
+func (st *store[T]) touch() {
+	if st != nil && st.pinned {
+		st.dirty = true
+	}
+}
+
+// clone returns a copy of the store, or nil if its storage can't be cloned.
+func (st *store[T]) clone() *store[T] {
+	if cloner, ok := st.storage.(interface{ Clone() Storage[T] }); ok {
+		return &store[T]{storage: cloner.Clone(), cmp: st.cmp}
+	}
+	return nil
+}
+
+// noop is the unpin function of the sets that don't need pinning.
+// Unlike a func literal in a generic function, it doesn't allocate.
+func noop() {}
+
+// collectStorage returns the elements of the storage in a new slice.
+func collectStorage[T any](storage Storage[T]) []T {
+	items := make([]T, 0, storage.Len())
+	for _, e := range storage.All() {
+		items = append(items, e)
+	}
+	return items
+}
+
+// backward returns an iterator over the elements of the storage in descending order.
+func backward[T any](storage Storage[T]) iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for i := storage.Len() - 1; i >= 0; i-- {
+			if !yield(i, storage.At(i)) {
+				return
+			}
+		}
+	}
+}
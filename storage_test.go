@@ -0,0 +1,115 @@
+package smallset
+
+import (
+	"slices"
+	"testing"
+)
+
+// countingStorage is an instrumented storage that counts the shifts of elements.
+type countingStorage[T any] struct {
+	*SliceStorage[T]
+	inserts, deletes int
+}
+
+func (c *countingStorage[T]) Insert(i int, e T) {
+	c.inserts++
+	c.SliceStorage.Insert(i, e)
+}
+
+func (c *countingStorage[T]) DeleteRange(i, j int) {
+	c.deletes++
+	c.SliceStorage.DeleteRange(i, j)
+}
+
+func TestStored(t *testing.T) {
+	storage := &countingStorage[int]{SliceStorage: NewSliceStorage[int](10)}
+	s := NewStored[int](storage)
+	if s.Storage() != storage {
+		t.Fatalf("expected the storage of the set")
+	}
+
+	for _, e := range []int{5, 1, 3, 1, 9, 7} {
+		s.Add(e)
+	}
+	if storage.inserts != 5 {
+		t.Errorf("expected 5 inserts, got %d", storage.inserts)
+	}
+
+	if removed := s.RemoveBetween(2, 6); removed != 2 {
+		t.Errorf("expected 2 removed, got %d", removed)
+	}
+	if !s.Remove(9) || s.Remove(9) {
+		t.Error("unexpected result of Remove")
+	}
+	if storage.deletes != 2 {
+		t.Errorf("expected 2 deletes, got %d", storage.deletes)
+	}
+
+	expected := []int{1, 7}
+	if items := s.Items(); !slices.Equal(items, expected) {
+		t.Errorf("Items mismatch.\nExpected: %v\nActual: %v", expected, items)
+	}
+	if s.Min() != 1 || s.Max() != 7 {
+		t.Errorf("unexpected min %d or max %d", s.Min(), s.Max())
+	}
+
+	allocs := testing.AllocsPerRun(100, func() { s.Contains(7) })
+	if allocs != 0 {
+		t.Errorf("Contains expected 0 allocations, got %v", allocs)
+	}
+}
+
+func TestStoredSetAlgebra(t *testing.T) {
+	storage := &countingStorage[int]{SliceStorage: NewSliceStorage[int](10)}
+	s := NewStored[int](storage)
+	for _, e := range []int{1, 3, 5, 7, 9} {
+		s.Add(e)
+	}
+	inserts := storage.inserts
+
+	other := From(3, 4, 5)
+	if union := s.Union(other); !slices.Equal(union.Items(), []int{1, 3, 4, 5, 7, 9}) {
+		t.Errorf("unexpected union %v", union.Items())
+	}
+	if inter := other.Intersect(s); !slices.Equal(inter.Items(), []int{3, 5}) {
+		t.Errorf("unexpected intersection %v", inter.Items())
+	}
+	if !s.IsEqual(From(1, 3, 5, 7, 9)) || !slices.Equal(Merge(s, other).Items(), []int{1, 3, 4, 5, 7, 9}) {
+		t.Errorf("unexpected result of IsEqual or Merge")
+	}
+	if storage.inserts != inserts || storage.deletes != 0 {
+		t.Errorf("expected reads not to write back to the storage, got %d inserts and %d deletes",
+			storage.inserts-inserts, storage.deletes)
+	}
+
+	added, removed := s.ApplyDiff([]int{2}, []int{7, 9})
+	if added != 1 || removed != 2 {
+		t.Errorf("expected 1 added and 2 removed, got %d and %d", added, removed)
+	}
+	if items := collectStorage[int](storage); !slices.Equal(items, []int{1, 2, 3, 5}) {
+		t.Errorf("expected the storage to be written back, got %v", items)
+	}
+
+	clone := s.Clone()
+	clone.Add(4)
+	if clone.Storage() == nil || clone.Storage() == Storage[int](storage) || s.Contains(4) {
+		t.Errorf("expected the clone to have its own storage")
+	}
+}
+
+func TestStoredCustom(t *testing.T) {
+	s := NewStoredCustom(PersonCmp, NewSliceStorage[Person](0))
+	for _, p := range people1 {
+		s.Add(p)
+	}
+
+	if items := s.Items(); !slices.Equal(items, unique1) {
+		t.Errorf("Items mismatch.\nExpected: %v\nActual: %v", unique1, items)
+	}
+	if diff := s.Difference(CustomFrom(PersonCmp, unique1[1:]...)); !slices.Equal(diff.Items(), unique1[:1]) {
+		t.Errorf("unexpected difference %v", diff.Items())
+	}
+	if p := s.PopMax(); p.ID != 4 {
+		t.Errorf("expected PopMax to return ID 4, got %v", p)
+	}
+}
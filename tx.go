@@ -0,0 +1,110 @@
+package smallset
+
+import (
+	"cmp"
+	"slices"
+)
+
+// Tx is a transaction that stages additions and removals to a set, and applies them
+// all at once in a single pass on [Tx.Commit], or discards them on [Tx.Rollback].
+// If the same element is staged more than once, the last operation wins.
+// It's obtained from the Begin method of [Ordered] and [Custom] sets.
+// Not safe for concurrent use.
+type Tx[T any] struct {
+	ops   []txOp[T]
+	cmp   func(a, b T) int
+	apply func(add, remove []T) (added, removed int)
+	done  bool
+}
+
+type txOp[T any] struct {
+	item T
+	add  bool
+}
+
+// Begin starts a transaction on the set. The staged operations are applied with ApplyDiff
+// on [Tx.Commit], so their cost is O(N) regardless of their number.
+func (s *Ordered[T]) Begin() *Tx[T] {
+	return &Tx[T]{cmp: cmp.Compare[T], apply: s.ApplyDiff}
+}
+
+// WithTx runs fn in a transaction on the set, which is committed if fn returns nil,
+// and rolled back otherwise. It returns the error of fn.
+func (s *Ordered[T]) WithTx(fn func(tx *Tx[T]) error) error {
+	return withTx(s.Begin(), fn)
+}
+
+// Begin starts a transaction on the set. The staged operations are applied with ApplyDiff
+// on [Tx.Commit], so their cost is O(N) regardless of their number.
+func (s *Custom[T]) Begin() *Tx[T] {
+	return &Tx[T]{cmp: s.cmp, apply: s.ApplyDiff}
+}
+
+// WithTx runs fn in a transaction on the set, which is committed if fn returns nil,
+// and rolled back otherwise. It returns the error of fn.
+func (s *Custom[T]) WithTx(fn func(tx *Tx[T]) error) error {
+	return withTx(s.Begin(), fn)
+}
+
+func withTx[T any](tx *Tx[T], fn func(tx *Tx[T]) error) error {
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	tx.Commit()
+	return nil
+}
+
+// Add stages the addition of an element. It panics if the transaction is already done.
+func (tx *Tx[T]) Add(e T) {
+	if tx.done {
+		panic("smallset.Tx.Add: transaction is already done")
+	}
+	tx.ops = append(tx.ops, txOp[T]{item: e, add: true})
+}
+
+// Remove stages the removal of an element. It panics if the transaction is already done.
+func (tx *Tx[T]) Remove(e T) {
+	if tx.done {
+		panic("smallset.Tx.Remove: transaction is already done")
+	}
+	tx.ops = append(tx.ops, txOp[T]{item: e, add: false})
+}
+
+// Commit applies the staged operations to the set in a single pass, and returns the number
+// of elements that were added or removed as a result. It panics if the transaction is already done.
+func (tx *Tx[T]) Commit() (added, removed int) {
+	if tx.done {
+		panic("smallset.Tx.Commit: transaction is already done")
+	}
+	tx.done = true
+
+	// group the operations by element, keeping their order within each group
+	slices.SortStableFunc(tx.ops, func(a, b txOp[T]) int {
+		return tx.cmp(a.item, b.item)
+	})
+
+	var add, remove []T
+	for i, op := range tx.ops {
+		if i+1 < len(tx.ops) && tx.cmp(op.item, tx.ops[i+1].item) == 0 {
+			// only the last operation of each element counts
+			continue
+		}
+
+		if op.add {
+			add = append(add, op.item)
+		} else {
+			remove = append(remove, op.item)
+		}
+	}
+
+	tx.ops = nil
+	return tx.apply(add, remove)
+}
+
+// Rollback discards the staged operations. It's a no-op if the transaction is already done,
+// so it can be safely deferred.
+func (tx *Tx[T]) Rollback() {
+	tx.done = true
+	tx.ops = nil
+}
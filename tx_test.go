@@ -0,0 +1,79 @@
+package smallset
+
+import (
+	"cmp"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestTxCommit(t *testing.T) {
+	s := From(1, 2, 3)
+	tx := s.Begin()
+	tx.Add(5)
+	tx.Remove(2)
+	tx.Add(4)
+	tx.Remove(4) // last operation wins
+	tx.Remove(3)
+	tx.Add(3)
+
+	if items := []int{1, 2, 3}; !slices.Equal(s.items, items) {
+		t.Fatalf("set modified before commit: %v", s.items)
+	}
+
+	added, removed := tx.Commit()
+	if added != 1 || removed != 1 {
+		t.Errorf("Commit expected (1, 1), got (%d, %d)", added, removed)
+	}
+
+	if items := []int{1, 3, 5}; !slices.Equal(s.items, items) {
+		t.Errorf("Items mismatch.\nExpected: %v\nActual: %v", items, s.items)
+	}
+
+	tx.Rollback() // no-op after commit
+	if items := []int{1, 3, 5}; !slices.Equal(s.items, items) {
+		t.Errorf("Items mismatch.\nExpected: %v\nActual: %v", items, s.items)
+	}
+}
+
+func TestWithTx(t *testing.T) {
+	errInvalid := errors.New("invalid element")
+	validate := func(items ...int) func(tx *Tx[int]) error {
+		return func(tx *Tx[int]) error {
+			for _, e := range items {
+				if e < 0 {
+					return errInvalid
+				}
+				tx.Add(e)
+			}
+			return nil
+		}
+	}
+
+	s := CustomFrom(cmp.Compare[int], 1, 2)
+	if err := s.WithTx(validate(3, -1, 4)); !errors.Is(err, errInvalid) {
+		t.Errorf("expected errInvalid, got %v", err)
+	}
+	if items := []int{1, 2}; !slices.Equal(s.items, items) {
+		t.Errorf("expected rollback, got %v", s.items)
+	}
+
+	if err := s.WithTx(validate(3, 4)); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if items := []int{1, 2, 3, 4}; !slices.Equal(s.items, items) {
+		t.Errorf("Items mismatch.\nExpected: %v\nActual: %v", items, s.items)
+	}
+}
+
+func TestTxDone(t *testing.T) {
+	tx := From(1).Begin()
+	tx.Rollback()
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected Add to panic after Rollback")
+		}
+	}()
+	tx.Add(2)
+}
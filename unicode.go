@@ -0,0 +1,86 @@
+package smallset
+
+import (
+	"iter"
+	"slices"
+	"unicode"
+)
+
+// FromRangeTable returns an initialized set that contains all the runes of the table,
+// like the character classes of the [unicode] package, e.g. unicode.Greek.
+func FromRangeTable(table *unicode.RangeTable) *Ordered[rune] {
+	return FromSeq(tableRunes(table))
+}
+
+// ToRangeTable returns a [unicode.RangeTable] with the runes of the set, which can be used with
+// [unicode.Is] and the rest of the standard library's Unicode machinery.
+// Consecutive runes with the same distance between them are grouped into a single range.
+// Runes outside of [0, unicode.MaxRune] are ignored.
+func ToRangeTable(s *Ordered[rune]) *unicode.RangeTable {
+	defer s.pin()()
+	start, _ := slices.BinarySearch(s.items, 0)
+	end, found := slices.BinarySearch(s.items, unicode.MaxRune)
+	if found {
+		end++
+	}
+	split, _ := slices.BinarySearch(s.items, 1<<16)
+	split = max(split, start)
+
+	table := &unicode.RangeTable{}
+	for _, r := range strideRanges(s.items[start:split]) {
+		table.R16 = append(table.R16, unicode.Range16{Lo: uint16(r.lo), Hi: uint16(r.hi), Stride: uint16(r.stride)})
+		if r.hi <= unicode.MaxLatin1 {
+			table.LatinOffset++
+		}
+	}
+	for _, r := range strideRanges(s.items[split:end]) {
+		table.R32 = append(table.R32, unicode.Range32{Lo: uint32(r.lo), Hi: uint32(r.hi), Stride: uint32(r.stride)})
+	}
+	return table
+}
+
+// tableRunes returns an iterator over the runes of the table in ascending order.
+func tableRunes(table *unicode.RangeTable) iter.Seq[rune] {
+	return func(yield func(rune) bool) {
+		for _, r := range table.R16 {
+			for c := rune(r.Lo); c <= rune(r.Hi); c += rune(r.Stride) {
+				if !yield(c) {
+					return
+				}
+			}
+		}
+		for _, r := range table.R32 {
+			for c := rune(r.Lo); c <= rune(r.Hi); c += rune(r.Stride) {
+				if !yield(c) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// strideRange is a range [lo, hi] of runes at the same distance between each other, the stride.
+type strideRange struct {
+	lo, hi, stride rune
+}
+
+// strideRanges groups the sorted runes into stride ranges, built greedily from the smallest rune.
+func strideRanges(runes []rune) []strideRange {
+	var ranges []strideRange
+	for i := 0; i < len(runes); {
+		if i == len(runes)-1 {
+			ranges = append(ranges, strideRange{lo: runes[i], hi: runes[i], stride: 1})
+			break
+		}
+
+		stride := runes[i+1] - runes[i]
+		j := i + 1
+		for j+1 < len(runes) && runes[j+1]-runes[j] == stride {
+			j++
+		}
+
+		ranges = append(ranges, strideRange{lo: runes[i], hi: runes[j], stride: stride})
+		i = j + 1
+	}
+	return ranges
+}
@@ -0,0 +1,57 @@
+package smallset
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+	"unicode"
+)
+
+func TestRangeTable(t *testing.T) {
+	cases := []*unicode.RangeTable{
+		unicode.Greek,
+		unicode.Upper,
+		unicode.Hiragana,
+		unicode.Nd,
+	}
+
+	for i, table := range cases {
+		t.Run(fmt.Sprintf("Case_%d", i), func(t *testing.T) {
+			s := FromRangeTable(table)
+			exported := ToRangeTable(s)
+
+			for r := rune(0); r <= unicode.MaxRune; r++ {
+				in := unicode.Is(table, r)
+				if s.Contains(r) != in {
+					t.Fatalf("FromRangeTable: rune %U expected %t", r, in)
+				}
+				if unicode.Is(exported, r) != in {
+					t.Fatalf("ToRangeTable: rune %U expected %t", r, in)
+				}
+			}
+
+			if !FromRangeTable(exported).IsEqual(s) {
+				t.Errorf("round trip mismatch")
+			}
+		})
+	}
+}
+
+func TestToRangeTable(t *testing.T) {
+	s := From[rune](-1, 'a', 'b', 'c', 'e', 'g', 'i', 0x1F600, unicode.MaxRune+1)
+	table := ToRangeTable(s)
+
+	r16 := []unicode.Range16{{Lo: 'a', Hi: 'c', Stride: 1}, {Lo: 'e', Hi: 'i', Stride: 2}}
+	if !slices.Equal(table.R16, r16) {
+		t.Errorf("R16 expected %v, got %v", r16, table.R16)
+	}
+
+	r32 := []unicode.Range32{{Lo: 0x1F600, Hi: 0x1F600, Stride: 1}}
+	if !slices.Equal(table.R32, r32) {
+		t.Errorf("R32 expected %v, got %v", r32, table.R32)
+	}
+
+	if table.LatinOffset != 2 {
+		t.Errorf("LatinOffset expected 2, got %d", table.LatinOffset)
+	}
+}
@@ -0,0 +1,63 @@
+package smallset
+
+import "fmt"
+
+// ZMember is a member of an external sorted set with its score, like the ones
+// added by the Redis ZADD command.
+type ZMember struct {
+	Score  float64
+	Member string
+}
+
+// ToZMembers exports the set as (score, member) pairs in the order of the set, for loading it
+// into an external sorted store. The score and member functions extract them from each element.
+// Beware that the external store orders the members by score, so the two orders match only
+// when the score is monotonic in the comparison function of the set.
+// It panics if score or member are nil.
+func ToZMembers[T any](s *Custom[T], score func(T) float64, member func(T) string) []ZMember {
+	if score == nil {
+		panic("smallset.ToZMembers: score cannot be nil")
+	}
+	if member == nil {
+		panic("smallset.ToZMembers: member cannot be nil")
+	}
+
+	members := make([]ZMember, s.Size())
+	for i, e := range s.Ascend() {
+		members[i] = ZMember{Score: score(e), Member: member(e)}
+	}
+	return members
+}
+
+// FromZMembers imports the (score, member) pairs read from an external sorted store, like the
+// reply of the Redis ZRANGE command with WITHSCORES, into a new set with the provided compare function.
+// The decode function rebuilds an element from its pair, and its first error is returned wrapped
+// together with the offending member. It panics if cmp or decode are nil.
+func FromZMembers[T any](cmp func(a, b T) int, members []ZMember, decode func(ZMember) (T, error)) (*Custom[T], error) {
+	if cmp == nil {
+		panic("smallset.FromZMembers: cmp cannot be nil")
+	}
+	if decode == nil {
+		panic("smallset.FromZMembers: decode cannot be nil")
+	}
+
+	items := make([]T, len(members))
+	for i, m := range members {
+		e, err := decode(m)
+		if err != nil {
+			return nil, fmt.Errorf("smallset.FromZMembers: member %q: %w", m.Member, err)
+		}
+		items[i] = e
+	}
+	return CustomFrom(cmp, items...), nil
+}
+
+// ZAddArgs returns the arguments of a ZADD command for the members, alternating each score
+// with its member, to be appended after the key in a generic command call of a Redis client.
+func ZAddArgs(members []ZMember) []any {
+	args := make([]any, 0, 2*len(members))
+	for _, m := range members {
+		args = append(args, m.Score, m.Member)
+	}
+	return args
+}
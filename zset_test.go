@@ -0,0 +1,54 @@
+package smallset
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+func TestZMembers(t *testing.T) {
+	score := func(p Person) float64 { return float64(p.ID) }
+	member := func(p Person) string { return p.Name }
+
+	set := CustomFrom(PersonCmp, people2...)
+	members := ToZMembers(set, score, member)
+	if len(members) != set.Size() {
+		t.Fatalf("expected %d members, got %d", set.Size(), len(members))
+	}
+
+	for i, p := range set.items {
+		if members[i].Score != float64(p.ID) || members[i].Member != p.Name {
+			t.Errorf("member %d: expected %v, got %v", i, p, members[i])
+		}
+	}
+
+	args := ZAddArgs(members[:2])
+	expected := []any{members[0].Score, members[0].Member, members[1].Score, members[1].Member}
+	if !slices.Equal(args, expected) {
+		t.Errorf("ZAddArgs expected %v, got %v", expected, args)
+	}
+
+	decode := func(m ZMember) (Person, error) {
+		return Person{ID: int(m.Score), Name: m.Member}, nil
+	}
+
+	imported, err := FromZMembers(PersonCmp, members, decode)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !imported.IsEqual(set) {
+		t.Errorf("expected %v, got %v", set.items, imported.items)
+	}
+}
+
+func TestFromZMembersError(t *testing.T) {
+	members := []ZMember{{Score: 1, Member: "1"}, {Score: 2, Member: "two"}}
+	_, err := FromZMembers(func(a, b int) int { return a - b }, members, func(m ZMember) (int, error) {
+		return strconv.Atoi(m.Member)
+	})
+
+	if !errors.Is(err, strconv.ErrSyntax) {
+		t.Errorf("expected a wrapped strconv.ErrSyntax, got %v", err)
+	}
+}